@@ -11,10 +11,24 @@ import (
 	"github.com/taymour/elysiandb/internal/configuration"
 	"github.com/taymour/elysiandb/internal/globals"
 	"github.com/taymour/elysiandb/internal/log"
-	"github.com/taymour/elysiandb/internal/storage"
 )
 
 func main() {
+	args := os.Args[1:]
+	if len(args) >= 2 && args[0] == "config" && args[1] == "check" {
+		os.Exit(checkConfig(args[2:]))
+	}
+
+	src, rest, err := configuration.ParseFlags("elysiandb", args, os.Stderr)
+	if err != nil {
+		os.Exit(2)
+	}
+	if len(rest) > 0 {
+		fmt.Fprintf(os.Stderr, "unknown command %q (available: config check)\n", rest[0])
+		os.Exit(2)
+	}
+	src.Lookup = os.LookupEnv
+
 	fmt.Println(`
    ╔══════════════════════════════════════╗
    ║                                      ║
@@ -24,13 +38,17 @@ func main() {
    ╚══════════════════════════════════════╝
 	`)
 
-	cfg, err := configuration.LoadConfig("elysian.yaml")
+	log.DirectInfo("Loading config from ", src.Path)
+
+	cfg, err := src.Load()
 	if err != nil {
-		log.Error("Error loading config:", err)
-		return
+		log.Error("Invalid configuration:\n", err)
+		log.WriteLogs()
+		os.Exit(1)
 	}
 
 	globals.SetConfig(cfg)
+	globals.SetConfigSource(src)
 
 	log.DirectInfo("Using data folder: ", globals.GetConfig().Store.Folder)
 
@@ -38,6 +56,14 @@ func main() {
 		boot.BootStats()
 	}
 
+	boot.BootReloader()
+
+	boot.BootSlowlog()
+
+	if cfg.Encryption.Enabled {
+		boot.BootEncryption()
+	}
+
 	boot.InitDB()
 
 	log.DirectInfo("Ready to serve your key-value needs with elegance.")
@@ -54,10 +80,40 @@ func main() {
 	defer stop()
 
 	<-ctx.Done()
+	stop()
 
-	storage.WriteToDB()
+	log.DirectInfo("Shutting down: draining connections...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), boot.ShutdownTimeout())
+	defer cancel()
+
+	if err := boot.Shutdown(shutdownCtx); err != nil {
+		log.Error("Final flush failed: ", err)
+		log.WriteLogs()
+		cancel()
+		os.Exit(1)
+	}
 
 	log.DirectInfo("Data persisted successfully.")
 
 	log.DirectInfo("ElysianDB shutting down gracefully. Goodbye!")
 }
+
+func checkConfig(args []string) int {
+	src, rest, err := configuration.ParseFlags("elysiandb config check", args, os.Stderr)
+	if err != nil {
+		return 2
+	}
+	if len(rest) > 0 {
+		fmt.Fprintf(os.Stderr, "unexpected argument %q\n", rest[0])
+		return 2
+	}
+	src.Lookup = os.LookupEnv
+
+	if err := configuration.Check(src, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration (%s):\n%v\n", src.Path, err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,178 @@
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	xxhash "github.com/cespare/xxhash/v2"
+)
+
+const (
+	growth    = 2
+	tightness = 0.5
+)
+
+var (
+	ErrInvalidParams = errors.New("error rate must be between 0 and 1 and capacity must be positive")
+	ErrCorrupt       = errors.New("corrupt bloom filter encoding")
+)
+
+type Filter struct {
+	errorRate float64
+	capacity  uint64
+	layers    []*layer
+}
+
+type layer struct {
+	bits     []uint64
+	m        uint64
+	k        uint32
+	capacity uint64
+	count    uint64
+}
+
+func New(errorRate float64, capacity uint64) (*Filter, error) {
+	if !(errorRate > 0 && errorRate < 1) || capacity == 0 {
+		return nil, ErrInvalidParams
+	}
+
+	f := &Filter{errorRate: errorRate, capacity: capacity}
+	f.grow()
+
+	return f, nil
+}
+
+func (f *Filter) Add(data []byte) bool {
+	h1, h2 := hashes(data)
+	if f.test(h1, h2) {
+		return false
+	}
+
+	last := f.layers[len(f.layers)-1]
+	if last.count >= last.capacity {
+		last = f.grow()
+	}
+
+	for i := uint64(0); i < uint64(last.k); i++ {
+		bit := (h1 + i*h2) % last.m
+		last.bits[bit/64] |= 1 << (bit % 64)
+	}
+	last.count++
+
+	return true
+}
+
+func (f *Filter) Exists(data []byte) bool {
+	h1, h2 := hashes(data)
+	return f.test(h1, h2)
+}
+
+func (f *Filter) Count() uint64 {
+	n := uint64(0)
+	for _, l := range f.layers {
+		n += l.count
+	}
+
+	return n
+}
+
+func (f *Filter) Layers() int {
+	return len(f.layers)
+}
+
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 0, 32)
+	out = binary.LittleEndian.AppendUint64(out, math.Float64bits(f.errorRate))
+	out = binary.LittleEndian.AppendUint64(out, f.capacity)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(f.layers)))
+
+	for _, l := range f.layers {
+		out = binary.LittleEndian.AppendUint64(out, l.count)
+		for _, w := range l.bits {
+			out = binary.LittleEndian.AppendUint64(out, w)
+		}
+	}
+
+	return out, nil
+}
+
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	if len(data) < 20 {
+		return ErrCorrupt
+	}
+
+	errorRate := math.Float64frombits(binary.LittleEndian.Uint64(data))
+	capacity := binary.LittleEndian.Uint64(data[8:])
+	n := binary.LittleEndian.Uint32(data[16:])
+	data = data[20:]
+
+	g, err := New(errorRate, capacity)
+	if err != nil {
+		return ErrCorrupt
+	}
+	g.layers = nil
+
+	for i := uint32(0); i < n; i++ {
+		l := g.grow()
+		if len(data) < 8+8*len(l.bits) {
+			return ErrCorrupt
+		}
+
+		l.count = binary.LittleEndian.Uint64(data)
+		data = data[8:]
+		for j := range l.bits {
+			l.bits[j] = binary.LittleEndian.Uint64(data[8*j:])
+		}
+		data = data[8*len(l.bits):]
+	}
+
+	if len(data) != 0 || len(g.layers) == 0 {
+		return ErrCorrupt
+	}
+
+	*f = *g
+
+	return nil
+}
+
+func (f *Filter) grow() *layer {
+	i := len(f.layers)
+	capacity := f.capacity * uint64(math.Pow(growth, float64(i)))
+	p := f.errorRate * math.Pow(tightness, float64(i))
+
+	m := uint64(math.Ceil(-float64(capacity) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	m = (m + 63) / 64 * 64
+	k := uint32(math.Ceil(-math.Log2(p)))
+
+	l := &layer{bits: make([]uint64, m/64), m: m, k: k, capacity: capacity}
+	f.layers = append(f.layers, l)
+
+	return l
+}
+
+func (f *Filter) test(h1, h2 uint64) bool {
+	for _, l := range f.layers {
+		if l.test(h1, h2) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l *layer) test(h1, h2 uint64) bool {
+	for i := uint64(0); i < uint64(l.k); i++ {
+		bit := (h1 + i*h2) % l.m
+		if l.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hashes(data []byte) (uint64, uint64) {
+	h := xxhash.Sum64(data)
+	return h, h>>33 | h<<31 | 1
+}
@@ -0,0 +1,15 @@
+package boot
+
+import (
+	"github.com/taymour/elysiandb/internal/encryption"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/log"
+)
+
+func BootEncryption() {
+	if err := encryption.LoadKeyring(globals.GetConfig().Encryption); err != nil {
+		log.Fatal("Error loading encryption keys", err)
+	}
+
+	log.DirectInfo("Encryption at rest enabled (AES-256-GCM)")
+}
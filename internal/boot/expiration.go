@@ -6,13 +6,18 @@ import (
 	"github.com/taymour/elysiandb/internal/storage"
 )
 
+const (
+	expirationSweepInterval = 10 * time.Millisecond
+	expirationSweepBudget   = 5 * time.Millisecond
+	expirationBatchSize     = 1000
+)
+
 func BootExpirationHandler() {
-	go checkExpirationsPeriodically()
+	runPeriodically(expirationSweepInterval, sweepExpiredKeys)
 }
 
-func checkExpirationsPeriodically() {
-	for {
-		storage.CleanExpiratedKeys(time.Now().Unix())
-		time.Sleep(1 * time.Second)
+func sweepExpiredKeys() {
+	deadline := time.Now().Add(expirationSweepBudget)
+	for storage.ExpireDueKeys(expirationBatchSize) == expirationBatchSize && time.Now().Before(deadline) {
 	}
 }
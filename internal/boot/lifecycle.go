@@ -0,0 +1,75 @@
+package boot
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	stopCh   = make(chan struct{})
+	stopOnce sync.Once
+	workers  sync.WaitGroup
+
+	reloadMu sync.Mutex
+	reloadCh = make(chan struct{})
+)
+
+func runPeriodically(interval time.Duration, fn func()) {
+	runEvery(func() time.Duration { return interval }, fn)
+}
+
+func runEvery(interval func() time.Duration, fn func()) {
+	workers.Add(1)
+
+	go func() {
+		defer workers.Done()
+
+		for {
+			changed := configReloaded()
+
+			var tick <-chan time.Time
+			var timer *time.Timer
+			if d := interval(); d > 0 {
+				timer = time.NewTimer(d)
+				tick = timer.C
+			}
+
+			select {
+			case <-stopCh:
+				stopTimer(timer)
+				return
+			case <-changed:
+				stopTimer(timer)
+			case <-tick:
+				fn()
+			}
+		}
+	}()
+}
+
+func stopTimer(t *time.Timer) {
+	if t != nil {
+		t.Stop()
+	}
+}
+
+func configReloaded() <-chan struct{} {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	return reloadCh
+}
+
+func notifyWorkers() {
+	reloadMu.Lock()
+	close(reloadCh)
+	reloadCh = make(chan struct{})
+	reloadMu.Unlock()
+}
+
+// stopWorkers waits for the workers even past the shutdown deadline: the
+// saver may be in the middle of a snapshot that the final flush relies on.
+func stopWorkers() {
+	stopOnce.Do(func() { close(stopCh) })
+	workers.Wait()
+}
@@ -0,0 +1,50 @@
+package boot
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+)
+
+func listenAddr(cfg configuration.ServerConfig) string {
+	return net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+}
+
+func wantsNetworkListener(cfg configuration.ServerConfig) bool {
+	return cfg.Port != 0 || cfg.UnixSocket == ""
+}
+
+func listenUnix(path string, mode string) (net.Listener, error) {
+	perm := os.FileMode(0o660)
+	if mode != "" {
+		m, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid unix socket mode %q: %w", mode, err)
+		}
+		perm = os.FileMode(m)
+	}
+
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a unix socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(path, perm); err != nil {
+		_ = ln.Close()
+		return nil, err
+	}
+
+	return ln, nil
+}
@@ -1,24 +1,71 @@
 package boot
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/encryption"
 	"github.com/taymour/elysiandb/internal/globals"
 	"github.com/taymour/elysiandb/internal/log"
 )
 
 func BootLogger() {
-	d := time.Duration(globals.GetConfig().Log.FlushIntervalSeconds) * time.Second
-	if d <= 0 {
-		return
+	opts, err := LogOptions(globals.GetConfig().Log)
+	if err != nil {
+		log.Fatal("Invalid log configuration", err)
 	}
-	
-	go WriteLogsPeriodically(d)
+
+	if err := log.Configure(opts); err != nil {
+		log.Fatal("Error opening log file", err)
+	}
+
+	log.WriteLogs()
+	runEvery(logFlushInterval, log.WriteLogs)
 }
 
-func WriteLogsPeriodically(interval time.Duration) {
-	for {
-		log.WriteLogs()
-		time.Sleep(interval)
+func logFlushInterval() time.Duration {
+	return time.Duration(globals.GetConfig().Log.FlushIntervalSeconds) * time.Second
+}
+
+func LogOptions(cfg configuration.LogConfig) (log.Options, error) {
+	level, err := log.ParseLevel(cfg.Level)
+	if err != nil {
+		return log.Options{}, err
+	}
+
+	opts := log.Options{
+		Level:      level,
+		BufferSize: cfg.BufferSize,
+		File:       cfg.File,
+		MaxSizeMB:  cfg.MaxSizeMB,
+		MaxAge:     time.Duration(cfg.MaxAgeHours) * time.Hour,
+		MaxBackups: cfg.MaxBackups,
 	}
+
+	switch strings.ToLower(cfg.Format) {
+	case "", "text":
+	case "json":
+		opts.JSON = true
+	default:
+		return log.Options{}, fmt.Errorf("unknown log format %q (want text or json)", cfg.Format)
+	}
+
+	switch strings.ToLower(cfg.Color) {
+	case "", "auto":
+		opts.Color = log.ColorAuto()
+	case "always":
+		opts.Color = true
+	case "never":
+		opts.Color = false
+	default:
+		return log.Options{}, fmt.Errorf("unknown log color mode %q (want auto, always or never)", cfg.Color)
+	}
+
+	if encryption.Enabled() {
+		opts.Encrypt = encryption.Seal
+	}
+
+	return opts, nil
 }
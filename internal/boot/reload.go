@@ -0,0 +1,48 @@
+package boot
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/log"
+	"github.com/taymour/elysiandb/internal/reload"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func BootReloader() {
+	reload.OnReload(applyReload)
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		for range hup {
+			_, _ = reload.Reload()
+		}
+	}()
+}
+
+func applyReload(applied []string, cfg *configuration.Config) {
+	if reload.Changed(applied, "log") {
+		opts, err := LogOptions(cfg.Log)
+		if err == nil {
+			err = log.Configure(opts)
+		}
+		if err != nil {
+			log.Error("Error applying log configuration: ", err)
+		}
+	}
+
+	if reload.Changed(applied, "stats.enabled") && cfg.Stats.Enabled {
+		BootStats()
+		storage.SyncStats()
+	}
+
+	if reload.Changed(applied, "slowlog") {
+		BootSlowlog()
+	}
+
+	notifyWorkers()
+}
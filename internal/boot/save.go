@@ -8,17 +8,11 @@ import (
 )
 
 func BootSaver() {
-	d := time.Duration(globals.GetConfig().Store.FlushIntervalSeconds) * time.Second
-	if d <= 0 {
-		return
-	}
-
-	go saveDBPeriodically(d)
+	runEvery(saveInterval, func() {
+		_ = storage.WriteToDB()
+	})
 }
 
-func saveDBPeriodically(interval time.Duration) {
-	for {
-		storage.WriteToDB()
-		time.Sleep(interval)
-	}
+func saveInterval() time.Duration {
+	return time.Duration(globals.GetConfig().Store.FlushIntervalSeconds) * time.Second
 }
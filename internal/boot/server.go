@@ -1,7 +1,9 @@
 package boot
 
 import (
-	"fmt"
+	"context"
+	"net"
+	"sync"
 
 	"github.com/fasthttp/router"
 
@@ -11,16 +13,19 @@ import (
 	"github.com/valyala/fasthttp"
 )
 
+var (
+	httpMu  sync.Mutex
+	httpSrv *fasthttp.Server
+)
+
 func StartHTTP() {
 	cfg := globals.GetConfig()
 
-	addr := fmt.Sprintf("%s:%d", cfg.Server.HTTP.Host, cfg.Server.HTTP.Port)
-
 	r := router.New()
 	routing.RegisterRoutes(r)
 
 	srv := &fasthttp.Server{
-		Handler:               r.Handler,
+		Handler:               routing.Instrument(r.Handler),
 		Name:                  "ElysianDB",
 		DisableKeepalive:      false,
 		TCPKeepalive:          true,
@@ -31,9 +36,52 @@ func StartHTTP() {
 		NoDefaultServerHeader: true,
 	}
 
-	log.DirectInfo("ElysianDB HTTP listening on http://", addr)
-	if err := srv.ListenAndServe(addr); err != nil {
-		log.Fatal("server error: ", err)
+	httpMu.Lock()
+	httpSrv = srv
+	httpMu.Unlock()
+
+	httpCfg := cfg.Server.HTTP
+
+	if httpCfg.UnixSocket != "" {
+		uln, err := listenUnix(httpCfg.UnixSocket, httpCfg.UnixSocketMode)
+		if err != nil {
+			log.Fatal("Error starting HTTP server on unix socket", err)
+		}
+
+		log.DirectInfo("ElysianDB HTTP listening on unix:", httpCfg.UnixSocket)
+
+		if !wantsNetworkListener(httpCfg) {
+			serveHTTP(srv, uln)
+			return
+		}
+
+		go serveHTTP(srv, uln)
+	}
+
+	ln, err := net.Listen("tcp", listenAddr(httpCfg))
+	if err != nil {
+		log.Fatal("server error", err)
+	}
+
+	log.DirectInfo("ElysianDB HTTP listening on http://", ln.Addr().String())
+	serveHTTP(srv, ln)
+}
+
+func shutdownHTTP(ctx context.Context) error {
+	httpMu.Lock()
+	srv := httpSrv
+	httpMu.Unlock()
+
+	if srv == nil {
+		return nil
+	}
+
+	return srv.ShutdownWithContext(ctx)
+}
+
+func serveHTTP(srv *fasthttp.Server, ln net.Listener) {
+	if err := srv.Serve(ln); err != nil {
+		log.Fatal("server error", err)
 	}
 
 	log.WriteLogs()
@@ -0,0 +1,54 @@
+package boot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/log"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+const defaultShutdownTimeout = 10 * time.Second
+
+func ShutdownTimeout() time.Duration {
+	d := time.Duration(globals.GetConfig().Server.ShutdownTimeoutSeconds) * time.Second
+	if d <= 0 {
+		return defaultShutdownTimeout
+	}
+
+	return d
+}
+
+func Shutdown(ctx context.Context) error {
+	storage.ReleaseBlockedPops()
+
+	httpErr := make(chan error, 1)
+	go func() { httpErr <- shutdownHTTP(ctx) }()
+
+	var drainErr error
+
+	if err := tcpSrv.shutdown(ctx); err != nil {
+		drainErr = errors.Join(drainErr, fmt.Errorf("tcp drain: %w", err))
+	}
+
+	if err := <-httpErr; err != nil {
+		drainErr = errors.Join(drainErr, fmt.Errorf("http drain: %w", err))
+	}
+
+	stopWorkers()
+
+	if drainErr != nil {
+		log.Warn("Shutdown deadline reached before all clients were drained: ", drainErr)
+	} else {
+		log.Info("All connections drained")
+	}
+
+	err := storage.WriteToDB()
+
+	log.WriteLogs()
+
+	return err
+}
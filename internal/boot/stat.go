@@ -1,19 +1,35 @@
 package boot
 
 import (
+	"sync"
 	"time"
 
+	"github.com/taymour/elysiandb/internal/globals"
 	"github.com/taymour/elysiandb/internal/stat"
 )
 
+var statsOnce sync.Once
+
 func BootStats() {
+	statsOnce.Do(startStats)
+}
+
+func startStats() {
 	stat.Init()
-	go collect()
+	runPeriodically(1*time.Second, func() {
+		if globals.GetConfig().Stats.Enabled {
+			stat.Stats.IncrementUptimeSeconds()
+		}
+	})
 }
 
-func collect() {
-	for {
-		stat.Stats.IncrementUptimeSeconds()
-		time.Sleep(1 * time.Second)
+func BootSlowlog() {
+	cfg := globals.GetConfig().Slowlog
+
+	maxLen := cfg.MaxLen
+	if maxLen <= 0 {
+		maxLen = 128
 	}
+
+	stat.Slowlog.Configure(cfg.Enabled, maxLen, time.Duration(cfg.ThresholdMicros)*time.Microsecond)
 }
@@ -2,40 +2,163 @@ package boot
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"io"
 	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/taymour/elysiandb/internal/globals"
 	"github.com/taymour/elysiandb/internal/log"
+	"github.com/taymour/elysiandb/internal/stat"
 	tcprouting "github.com/taymour/elysiandb/internal/transport/tcp/tcp_routing"
 )
 
+type tcpServer struct {
+	mu           sync.Mutex
+	listeners    map[net.Listener]struct{}
+	conns        map[net.Conn]struct{}
+	wg           sync.WaitGroup
+	shuttingDown atomic.Bool
+}
+
+var tcpSrv = &tcpServer{
+	listeners: make(map[net.Listener]struct{}),
+	conns:     make(map[net.Conn]struct{}),
+}
+
+func (s *tcpServer) trackListener(ln net.Listener) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.shuttingDown.Load() {
+		return false
+	}
+	s.listeners[ln] = struct{}{}
+
+	return true
+}
+
+func (s *tcpServer) trackConn(c net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.shuttingDown.Load() {
+		return false
+	}
+	s.conns[c] = struct{}{}
+	s.wg.Add(1)
+
+	return true
+}
+
+func (s *tcpServer) untrackConn(c net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, c)
+	s.mu.Unlock()
+	s.wg.Done()
+}
+
+func (s *tcpServer) shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.shuttingDown.Store(true)
+	for ln := range s.listeners {
+		_ = ln.Close()
+	}
+	now := time.Now()
+	for c := range s.conns {
+		_ = c.SetReadDeadline(now)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		for c := range s.conns {
+			_ = c.Close()
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
 func InitTCP() {
-	addr := ":8088"
+	cfg := globals.GetConfig().Server.TCP
+
+	if cfg.UnixSocket != "" {
+		uln, err := listenUnix(cfg.UnixSocket, cfg.UnixSocketMode)
+		if err != nil {
+			log.Fatal("Error starting TCP server on unix socket", err)
+			return
+		}
+
+		log.DirectInfo("TCP server listening on unix:", cfg.UnixSocket)
+
+		if !wantsNetworkListener(cfg) {
+			serveTCP(uln)
+			return
+		}
+
+		go serveTCP(uln)
+	}
 
-	ln, err := net.ListenTCP("tcp4", &net.TCPAddr{Port: 8088})
+	addr := listenAddr(cfg)
+
+	ln, err := net.Listen("tcp", addr)
 	if err != nil {
 		log.Fatal("Error starting TCP server:", err)
 		return
 	}
+
+	log.DirectInfo("TCP server listening on ", ln.Addr().String())
+
+	serveTCP(ln)
+}
+
+func serveTCP(ln net.Listener) {
 	defer ln.Close()
 
-	log.DirectInfo("TCP server listening on", addr)
+	if !tcpSrv.trackListener(ln) {
+		return
+	}
 
 	for {
-		tc, err := ln.AcceptTCP()
+		c, err := ln.Accept()
 		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
 			log.Error("Error accepting connection:", err)
 			continue
 		}
 
-		_ = tc.SetNoDelay(true)
-		_ = tc.SetKeepAlive(true)
-		_ = tc.SetKeepAlivePeriod(2 * time.Minute)
-		_ = tc.SetReadBuffer(256 << 10)
-		_ = tc.SetWriteBuffer(256 << 10)
+		if tc, ok := c.(*net.TCPConn); ok {
+			_ = tc.SetNoDelay(true)
+			_ = tc.SetKeepAlive(true)
+			_ = tc.SetKeepAlivePeriod(2 * time.Minute)
+			_ = tc.SetReadBuffer(256 << 10)
+			_ = tc.SetWriteBuffer(256 << 10)
+		}
 
-		go handleConnection(tc)
+		if !tcpSrv.trackConn(c) {
+			_ = c.Close()
+			continue
+		}
+
+		go func() {
+			defer tcpSrv.untrackConn(c)
+			handleConnection(c)
+		}()
 	}
 }
 
@@ -43,13 +166,19 @@ func handleConnection(c net.Conn) {
 	defer c.Close()
 	_ = c.SetDeadline(time.Time{})
 
+	statsEnabled := globals.GetConfig().Stats.Enabled
+	if statsEnabled {
+		stat.Stats.IncrementTCPClients()
+		defer stat.Stats.DecrementTCPClients()
+	}
+
 	r := bufio.NewReaderSize(c, 128<<10)
 	w := bufio.NewWriterSize(c, 128<<10)
 
 	for {
 		line, err := r.ReadSlice('\n')
 		if err != nil {
-			if err != io.EOF {
+			if err != io.EOF && !tcpSrv.shuttingDown.Load() {
 				log.Error("read:", err)
 			}
 			return
@@ -57,6 +186,11 @@ func handleConnection(c net.Conn) {
 
 		resp := tcprouting.RouteLine(line, c)
 
+		if statsEnabled {
+			stat.Stats.AddBytesIn(stat.ProtocolTCP, len(line))
+			stat.Stats.AddBytesOut(stat.ProtocolTCP, len(resp)+1)
+		}
+
 		if len(resp) > 0 {
 			if _, err := w.Write(resp); err != nil {
 				log.Error("write:", err)
@@ -71,5 +205,9 @@ func handleConnection(c net.Conn) {
 			log.Error("flush:", err)
 			return
 		}
+
+		if tcpSrv.shuttingDown.Load() {
+			return
+		}
 	}
 }
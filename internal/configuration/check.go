@@ -0,0 +1,22 @@
+package configuration
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v2"
+)
+
+func Check(src Source, out io.Writer) error {
+	cfg, err := src.Load()
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(data)
+	return err
+}
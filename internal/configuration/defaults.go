@@ -0,0 +1,33 @@
+package configuration
+
+func Defaults() *Config {
+	return &Config{
+		Store: StoreConfig{
+			Folder:               "/data",
+			Shards:               512,
+			FlushIntervalSeconds: 5,
+		},
+		Server: ServersConfig{
+			HTTP:                   ServerConfig{Enabled: true, Host: "0.0.0.0", Port: 8089, UnixSocketMode: "0660"},
+			TCP:                    ServerConfig{Enabled: true, Host: "0.0.0.0", Port: 8088, UnixSocketMode: "0660"},
+			ShutdownTimeoutSeconds: 10,
+		},
+		Log: LogConfig{
+			FlushIntervalSeconds: 5,
+			Level:                "info",
+			Format:               "text",
+			Color:                "auto",
+			BufferSize:           8192,
+			MaxSizeMB:            100,
+			MaxAgeHours:          24,
+			MaxBackups:           5,
+		},
+		Encryption: EncryptionConfig{
+			KeyEnv: "ELYSIAN_ENCRYPTION_KEY",
+		},
+		Slowlog: SlowlogConfig{
+			ThresholdMicros: 10000,
+			MaxLen:          128,
+		},
+	}
+}
@@ -0,0 +1,86 @@
+package configuration
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+func Diff(a *Config, b *Config) []string {
+	changed := make([]string, 0)
+	diffValues("", reflect.ValueOf(*a), reflect.ValueOf(*b), &changed)
+
+	return changed
+}
+
+func diffValues(prefix string, a reflect.Value, b reflect.Value, changed *[]string) {
+	if a.Kind() != reflect.Struct {
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*changed = append(*changed, prefix)
+		}
+		return
+	}
+
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := yamlName(t.Field(i))
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		diffValues(name, a.Field(i), b.Field(i), changed)
+	}
+}
+
+func CopyFields(dst *Config, src *Config, keys []string) {
+	for _, key := range keys {
+		d, ok := fieldByPath(reflect.ValueOf(dst).Elem(), key)
+		if !ok {
+			continue
+		}
+		s, _ := fieldByPath(reflect.ValueOf(src).Elem(), key)
+		d.Set(s)
+	}
+}
+
+func fieldByPath(v reflect.Value, path string) (reflect.Value, bool) {
+	for _, part := range strings.Split(path, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+
+		found := false
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if yamlName(t.Field(i)) == part {
+				v = v.Field(i)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return reflect.Value{}, false
+		}
+	}
+
+	return v, true
+}
+
+func yamlName(f reflect.StructField) string {
+	tag := strings.Split(f.Tag.Get("yaml"), ",")[0]
+	if tag == "" {
+		return strings.ToLower(f.Name)
+	}
+
+	return tag
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,45 @@
+package configuration
+
+import (
+	"strings"
+	"unicode"
+)
+
+const EnvPrefix = "ELYSIAN_"
+
+func EnvName(key string) string {
+	var b strings.Builder
+	b.WriteString(EnvPrefix)
+
+	for _, part := range strings.Split(key, ".") {
+		if b.Len() > len(EnvPrefix) {
+			b.WriteByte('_')
+		}
+
+		prev := rune(0)
+		for _, r := range part {
+			if unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToUpper(r))
+			prev = r
+		}
+	}
+
+	return b.String()
+}
+
+func ApplyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	for _, f := range Fields() {
+		raw, ok := lookup(EnvName(f.Key))
+		if !ok {
+			continue
+		}
+
+		if err := SetField(cfg, f.Key, raw); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package configuration
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type Field struct {
+	Key  string
+	Kind reflect.Kind
+}
+
+func Fields() []Field {
+	fields := make([]Field, 0)
+	collectFields("", reflect.TypeOf(Config{}), &fields)
+
+	return fields
+}
+
+func collectFields(prefix string, t reflect.Type, fields *[]Field) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := yamlName(f)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if f.Type.Kind() == reflect.Struct {
+			collectFields(key, f.Type, fields)
+			continue
+		}
+
+		*fields = append(*fields, Field{Key: key, Kind: f.Type.Kind()})
+	}
+}
+
+func SetField(cfg *Config, key string, raw string) error {
+	v, ok := fieldByPath(reflect.ValueOf(cfg).Elem(), key)
+	if !ok || v.Kind() == reflect.Struct {
+		return fmt.Errorf("unknown config key %q", key)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%s: expected an integer, got %q", key, raw)
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%s: expected true or false, got %q", key, raw)
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		items := make([]string, 0)
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("%s: unsupported field type %s", key, v.Kind())
+	}
+
+	return nil
+}
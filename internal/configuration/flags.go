@@ -0,0 +1,81 @@
+package configuration
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+const DefaultConfigPath = "elysian.yaml"
+
+type Source struct {
+	Path      string
+	Overrides map[string]string
+	Lookup    func(string) (string, bool)
+}
+
+type override struct {
+	key    string
+	isBool bool
+	values map[string]string
+}
+
+func (o *override) String() string   { return "" }
+func (o *override) IsBoolFlag() bool { return o.isBool }
+
+func (o *override) Set(raw string) error {
+	o.values[o.key] = raw
+	return nil
+}
+
+func ParseFlags(name string, args []string, output io.Writer) (Source, []string, error) {
+	src := Source{Overrides: make(map[string]string)}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&src.Path, "config", DefaultConfigPath, "path to the YAML configuration file")
+
+	for _, f := range Fields() {
+		o := &override{key: f.Key, isBool: f.Kind == reflect.Bool, values: src.Overrides}
+		fs.Var(o, f.Key, fmt.Sprintf("override %s (env %s)", f.Key, EnvName(f.Key)))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return Source{}, nil, err
+	}
+
+	return src, fs.Args(), nil
+}
+
+func (s Source) Load() (*Config, error) {
+	cfg, err := ReadConfig(s.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Lookup != nil {
+		if err := ApplyEnv(cfg, s.Lookup); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make([]string, 0, len(s.Overrides))
+	for k := range s.Overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := SetField(cfg, k, s.Overrides[k]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := Validate(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
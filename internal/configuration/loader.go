@@ -9,52 +9,91 @@ import (
 )
 
 type Config struct {
-	Store  StoreConfig   `yaml:"store"`
-	Server ServersConfig `yaml:"server"`
-	Log    LogConfig     `yaml:"log"`
-	Stats  StatsConfig   `yaml:"stats"`
+	Store      StoreConfig      `yaml:"store"`
+	Server     ServersConfig    `yaml:"server"`
+	Log        LogConfig        `yaml:"log"`
+	Stats      StatsConfig      `yaml:"stats"`
+	Encryption EncryptionConfig `yaml:"encryption"`
+	Slowlog    SlowlogConfig    `yaml:"slowlog"`
 }
 
 type ServersConfig struct {
-	HTTP ServerConfig `yaml:"http"`
-	TCP  ServerConfig `yaml:"tcp"`
+	HTTP                   ServerConfig `yaml:"http"`
+	TCP                    ServerConfig `yaml:"tcp"`
+	ShutdownTimeoutSeconds int          `yaml:"shutdownTimeoutSeconds"`
 }
 
 type LogConfig struct {
-	FlushIntervalSeconds int `yaml:"flushIntervalSeconds"`
+	FlushIntervalSeconds int    `yaml:"flushIntervalSeconds"`
+	Level                string `yaml:"level"`
+	Format               string `yaml:"format"`
+	Color                string `yaml:"color"`
+	BufferSize           int    `yaml:"bufferSize"`
+	File                 string `yaml:"file"`
+	MaxSizeMB            int    `yaml:"maxSizeMB"`
+	MaxAgeHours          int    `yaml:"maxAgeHours"`
+	MaxBackups           int    `yaml:"maxBackups"`
 }
 
 type ServerConfig struct {
-	Enabled bool   `yaml:"enabled"`
-	Host    string `yaml:"host"`
-	Port    int    `yaml:"port"`
+	Enabled        bool   `yaml:"enabled"`
+	Host           string `yaml:"host"`
+	Port           int    `yaml:"port"`
+	UnixSocket     string `yaml:"unixSocket"`
+	UnixSocketMode string `yaml:"unixSocketMode"`
 }
 
 type StoreConfig struct {
-	Folder               string `yaml:"folder"`
-	Shards               int    `yaml:"shards"`
-	FlushIntervalSeconds int    `yaml:"flushIntervalSeconds"`
+	Folder               string   `yaml:"folder"`
+	Shards               int      `yaml:"shards"`
+	FlushIntervalSeconds int      `yaml:"flushIntervalSeconds"`
+	MaxKeyBytes          int      `yaml:"maxKeyBytes"`
+	MaxValueBytes        int      `yaml:"maxValueBytes"`
+	Indexes              []string `yaml:"indexes"`
 }
 
 type StatsConfig struct {
 	Enabled bool `yaml:"enabled"`
 }
 
+type SlowlogConfig struct {
+	Enabled         bool `yaml:"enabled"`
+	ThresholdMicros int  `yaml:"thresholdMicros"`
+	MaxLen          int  `yaml:"maxLen"`
+}
+
+type EncryptionConfig struct {
+	Enabled          bool     `yaml:"enabled"`
+	KeyFile          string   `yaml:"keyFile"`
+	KeyEnv           string   `yaml:"keyEnv"`
+	PreviousKeyFiles []string `yaml:"previousKeyFiles"`
+	PreviousKeysEnv  string   `yaml:"previousKeysEnv"`
+}
+
 func LoadConfig(path string) (*Config, error) {
 	fmt.Println("Loading config from", path)
-	data, err := os.ReadFile(path)
+
+	cfg, err := ReadConfig(path)
 	if err != nil {
 		log.Fatal("error:", err)
 		return nil, err
 	}
 
-	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		log.Fatal("error:", err)
+	fmt.Printf("Loaded config: %+v\n", *cfg)
+
+	return cfg, nil
+}
+
+func ReadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
 		return nil, err
 	}
 
-	fmt.Printf("Loaded config: %+v\n", cfg)
+	cfg := Defaults()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
 
-	return &cfg, nil
+	return cfg, nil
 }
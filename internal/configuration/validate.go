@@ -0,0 +1,107 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/taymour/elysiandb/internal/jsondoc"
+	"github.com/taymour/elysiandb/internal/log"
+)
+
+func Validate(cfg *Config) error {
+	var errs []error
+
+	fail := func(key string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	if strings.TrimSpace(cfg.Store.Folder) == "" {
+		fail("store.folder", "must not be empty")
+	}
+
+	if cfg.Store.Shards < 1 {
+		fail("store.shards", "must be >= 1, got %d", cfg.Store.Shards)
+	} else if cfg.Store.Shards&(cfg.Store.Shards-1) != 0 {
+		fail("store.shards", "must be a power of two (e.g. 128, 256, 512), got %d", cfg.Store.Shards)
+	}
+
+	nonNegative := map[string]int{
+		"store.flushIntervalSeconds":    cfg.Store.FlushIntervalSeconds,
+		"store.maxKeyBytes":             cfg.Store.MaxKeyBytes,
+		"store.maxValueBytes":           cfg.Store.MaxValueBytes,
+		"server.shutdownTimeoutSeconds": cfg.Server.ShutdownTimeoutSeconds,
+		"log.flushIntervalSeconds":      cfg.Log.FlushIntervalSeconds,
+		"log.bufferSize":                cfg.Log.BufferSize,
+		"log.maxSizeMB":                 cfg.Log.MaxSizeMB,
+		"log.maxAgeHours":               cfg.Log.MaxAgeHours,
+		"log.maxBackups":                cfg.Log.MaxBackups,
+		"slowlog.thresholdMicros":       cfg.Slowlog.ThresholdMicros,
+		"slowlog.maxLen":                cfg.Slowlog.MaxLen,
+	}
+	for _, key := range sortedKeys(nonNegative) {
+		if v := nonNegative[key]; v < 0 {
+			fail(key, "must be >= 0, got %d", v)
+		}
+	}
+
+	names := make(map[string]bool, len(cfg.Store.Indexes))
+	for _, spec := range cfg.Store.Indexes {
+		parts := strings.Fields(spec)
+		if len(parts) != 3 {
+			fail("store.indexes", "%q must be \"<name> <key-pattern> <json-path>\"", spec)
+			continue
+		}
+		if names[parts[0]] {
+			fail("store.indexes", "duplicate index name %q", parts[0])
+		}
+		names[parts[0]] = true
+		if _, err := jsondoc.ParsePath(parts[2]); err != nil {
+			fail("store.indexes", "%q: %v", spec, err)
+		}
+	}
+
+	validateServer := func(prefix string, s ServerConfig) {
+		if !s.Enabled {
+			return
+		}
+		if s.Port < 0 || s.Port > 65535 {
+			fail(prefix+".port", "must be between 0 and 65535, got %d", s.Port)
+		}
+		if s.Port == 0 && s.UnixSocket == "" {
+			fail(prefix+".port", "must be set when no unixSocket is configured")
+		}
+		if s.UnixSocketMode != "" {
+			if _, err := strconv.ParseUint(s.UnixSocketMode, 8, 32); err != nil {
+				fail(prefix+".unixSocketMode", "must be an octal permission string such as \"0660\", got %q", s.UnixSocketMode)
+			}
+		}
+	}
+	validateServer("server.http", cfg.Server.HTTP)
+	validateServer("server.tcp", cfg.Server.TCP)
+
+	if cfg.Server.HTTP.Enabled && cfg.Server.TCP.Enabled &&
+		cfg.Server.HTTP.Port != 0 && cfg.Server.HTTP.Port == cfg.Server.TCP.Port &&
+		cfg.Server.HTTP.Host == cfg.Server.TCP.Host {
+		fail("server.tcp.port", "conflicts with server.http.port (%d)", cfg.Server.TCP.Port)
+	}
+
+	if _, err := log.ParseLevel(cfg.Log.Level); err != nil {
+		fail("log.level", "%v", err)
+	}
+
+	switch strings.ToLower(cfg.Log.Format) {
+	case "", "text", "json":
+	default:
+		fail("log.format", "must be text or json, got %q", cfg.Log.Format)
+	}
+
+	switch strings.ToLower(cfg.Log.Color) {
+	case "", "auto", "always", "never":
+	default:
+		fail("log.color", "must be auto, always or never, got %q", cfg.Log.Color)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,273 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+)
+
+const (
+	KeySize   = 32
+	keyIDSize = 8
+)
+
+var Magic = []byte("ELYSENC1")
+
+var (
+	ErrNoKey      = errors.New("data is encrypted but no encryption key is configured")
+	ErrUnknownKey = errors.New("data is encrypted with an unknown key")
+	ErrCorrupted  = errors.New("encrypted data is truncated or corrupted")
+)
+
+type key struct {
+	id   []byte
+	aead cipher.AEAD
+}
+
+var (
+	mu       sync.RWMutex
+	current  *key
+	previous []*key
+)
+
+func LoadKeyring(cfg configuration.EncryptionConfig) error {
+	if !cfg.Enabled {
+		SetKeys(nil)
+		return nil
+	}
+
+	raw, err := loadCurrentKey(cfg)
+	if err != nil {
+		return err
+	}
+
+	all := [][]byte{raw}
+
+	for _, path := range cfg.PreviousKeyFiles {
+		k, err := readKeyFile(path)
+		if err != nil {
+			return err
+		}
+		all = append(all, k)
+	}
+
+	if cfg.PreviousKeysEnv != "" {
+		for _, v := range strings.Split(os.Getenv(cfg.PreviousKeysEnv), ",") {
+			if strings.TrimSpace(v) == "" {
+				continue
+			}
+			k, err := ParseKey([]byte(v))
+			if err != nil {
+				return fmt.Errorf("%s: %w", cfg.PreviousKeysEnv, err)
+			}
+			all = append(all, k)
+		}
+	}
+
+	return SetKeys(all)
+}
+
+func loadCurrentKey(cfg configuration.EncryptionConfig) ([]byte, error) {
+	if cfg.KeyFile != "" {
+		return readKeyFile(cfg.KeyFile)
+	}
+
+	name := cfg.KeyEnv
+	if name == "" {
+		name = "ELYSIAN_ENCRYPTION_KEY"
+	}
+
+	v, ok := os.LookupEnv(name)
+	if !ok || strings.TrimSpace(v) == "" {
+		return nil, fmt.Errorf("encryption is enabled but neither keyFile nor %s is set", name)
+	}
+
+	k, err := ParseKey([]byte(v))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return k, nil
+}
+
+func readKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := ParseKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return k, nil
+}
+
+func ParseKey(data []byte) ([]byte, error) {
+	if len(data) == KeySize {
+		return data, nil
+	}
+
+	s := strings.TrimSpace(string(data))
+
+	if len(s) == KeySize*2 {
+		if k, err := hex.DecodeString(s); err == nil {
+			return k, nil
+		}
+	}
+
+	if k, err := base64.StdEncoding.DecodeString(s); err == nil && len(k) == KeySize {
+		return k, nil
+	}
+
+	if len(s) == KeySize {
+		return []byte(s), nil
+	}
+
+	return nil, fmt.Errorf("encryption key must be %d bytes (raw, hex or base64 encoded)", KeySize)
+}
+
+func SetKeys(keys [][]byte) error {
+	ring := make([]*key, 0, len(keys))
+	for _, raw := range keys {
+		k, err := newKey(raw)
+		if err != nil {
+			return err
+		}
+		ring = append(ring, k)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(ring) == 0 {
+		current = nil
+		previous = nil
+		return nil
+	}
+
+	current = ring[0]
+	previous = ring[1:]
+
+	return nil
+}
+
+func newKey(raw []byte) (*key, error) {
+	if len(raw) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(raw))
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(raw)
+
+	return &key{id: sum[:keyIDSize], aead: aead}, nil
+}
+
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return current != nil
+}
+
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, Magic)
+}
+
+func Seal(plain []byte) ([]byte, error) {
+	mu.RLock()
+	k := current
+	mu.RUnlock()
+
+	if k == nil {
+		return plain, nil
+	}
+
+	header := make([]byte, 0, len(Magic)+keyIDSize)
+	header = append(header, Magic...)
+	header = append(header, k.id...)
+
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(header)+len(nonce)+len(plain)+k.aead.Overhead())
+	out = append(out, header...)
+	out = append(out, nonce...)
+
+	return k.aead.Seal(out, nonce, plain, header), nil
+}
+
+func Open(data []byte) ([]byte, bool, error) {
+	mu.RLock()
+	cur := current
+	prev := previous
+	mu.RUnlock()
+
+	if !IsSealed(data) {
+		return data, cur != nil, nil
+	}
+
+	if cur == nil {
+		return nil, false, ErrNoKey
+	}
+
+	headerSize := len(Magic) + keyIDSize
+	if len(data) < headerSize {
+		return nil, false, ErrCorrupted
+	}
+
+	header := data[:headerSize]
+	id := header[len(Magic):]
+
+	k, stale := cur, false
+	if !bytes.Equal(id, cur.id) {
+		k = nil
+		for _, p := range prev {
+			if bytes.Equal(id, p.id) {
+				k, stale = p, true
+				break
+			}
+		}
+	}
+
+	if k == nil {
+		return nil, false, ErrUnknownKey
+	}
+
+	rest := data[headerSize:]
+	if len(rest) < k.aead.NonceSize() {
+		return nil, false, ErrCorrupted
+	}
+
+	nonce, ciphertext := rest[:k.aead.NonceSize()], rest[k.aead.NonceSize():]
+
+	plain, err := k.aead.Open(nil, nonce, ciphertext, header)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+
+	return plain, stale, nil
+}
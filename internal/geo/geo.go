@@ -0,0 +1,166 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	Step = 26
+
+	MinLon = -180.0
+	MaxLon = 180.0
+	MinLat = -85.05112878
+	MaxLat = 85.05112878
+
+	earthRadius     = 6372797.560856
+	metersPerDegree = earthRadius * math.Pi / 180
+)
+
+var ErrInvalidCoordinates = errors.New("invalid longitude,latitude pair")
+
+type Range struct {
+	Min, Max uint64
+}
+
+func Valid(lon, lat float64) bool {
+	return lon >= MinLon && lon <= MaxLon && lat >= MinLat && lat <= MaxLat
+}
+
+func Encode(lon, lat float64) uint64 {
+	return interleave(cellIndex(lon, MinLon, MaxLon, Step), cellIndex(lat, MinLat, MaxLat, Step))
+}
+
+func Decode(hash uint64) (float64, float64) {
+	x, y := deinterleave(hash)
+	cells := float64(uint64(1) << Step)
+
+	lon := MinLon + (float64(x)+0.5)*(MaxLon-MinLon)/cells
+	lat := MinLat + (float64(y)+0.5)*(MaxLat-MinLat)/cells
+
+	return lon, lat
+}
+
+func Distance(lon1, lat1, lon2, lat2 float64) float64 {
+	phi1, phi2 := radians(lat1), radians(lat2)
+	u := math.Sin((phi2 - phi1) / 2)
+	v := math.Sin(radians(lon2-lon1) / 2)
+
+	return 2 * earthRadius * math.Asin(math.Sqrt(u*u+math.Cos(phi1)*math.Cos(phi2)*v*v))
+}
+
+func InBox(lon, lat, centerLon, centerLat, width, height float64) bool {
+	if Distance(centerLon, lat, centerLon, centerLat) > height/2 {
+		return false
+	}
+
+	return Distance(lon, lat, centerLon, lat) <= width/2
+}
+
+func SearchRanges(lon, lat, radius float64) []Range {
+	step := searchStep(lat, radius)
+	cells := int64(1) << step
+	shift := 2 * (Step - step)
+
+	x := int64(cellIndex(lon, MinLon, MaxLon, step))
+	y := int64(cellIndex(lat, MinLat, MaxLat, step))
+
+	ranges := make([]Range, 0, 9)
+	for dy := int64(-1); dy <= 1; dy++ {
+		if y+dy < 0 || y+dy >= cells {
+			continue
+		}
+		for dx := int64(-1); dx <= 1; dx++ {
+			h := interleave(uint32((x+dx+cells)%cells), uint32(y+dy))
+			ranges = append(ranges, Range{Min: h << shift, Max: (h + 1) << shift})
+		}
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Min < ranges[j].Min })
+
+	merged := ranges[:0]
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.Min <= merged[n-1].Max {
+			merged[n-1].Max = max(merged[n-1].Max, r.Max)
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+func UnitFactor(unit string) (float64, bool) {
+	switch strings.ToLower(unit) {
+	case "m":
+		return 1, true
+	case "km":
+		return 1000, true
+	case "mi":
+		return 1609.34, true
+	case "ft":
+		return 0.3048, true
+	}
+
+	return 0, false
+}
+
+func searchStep(lat, radius float64) uint {
+	edge := min(math.Abs(lat)+radius/metersPerDegree, 90)
+
+	step := uint(1)
+	for step < Step {
+		next := float64(uint64(1) << (step + 1))
+		height := (MaxLat - MinLat) / next * metersPerDegree
+		width := (MaxLon - MinLon) / next * metersPerDegree * math.Cos(radians(edge))
+		if height < radius || width < radius {
+			break
+		}
+		step++
+	}
+
+	return step
+}
+
+func cellIndex(v, lo, hi float64, step uint) uint32 {
+	cells := float64(uint64(1) << step)
+	idx := math.Floor((v - lo) / (hi - lo) * cells)
+
+	return uint32(min(max(idx, 0), cells-1))
+}
+
+func interleave(x, y uint32) uint64 {
+	return spread(x)<<1 | spread(y)
+}
+
+func deinterleave(h uint64) (uint32, uint32) {
+	return squash(h >> 1), squash(h)
+}
+
+func spread(v uint32) uint64 {
+	x := uint64(v)
+	x = (x | x<<16) & 0x0000FFFF0000FFFF
+	x = (x | x<<8) & 0x00FF00FF00FF00FF
+	x = (x | x<<4) & 0x0F0F0F0F0F0F0F0F
+	x = (x | x<<2) & 0x3333333333333333
+	x = (x | x<<1) & 0x5555555555555555
+
+	return x
+}
+
+func squash(x uint64) uint32 {
+	x &= 0x5555555555555555
+	x = (x | x>>1) & 0x3333333333333333
+	x = (x | x>>2) & 0x0F0F0F0F0F0F0F0F
+	x = (x | x>>4) & 0x00FF00FF00FF00FF
+	x = (x | x>>8) & 0x0000FFFF0000FFFF
+	x = (x | x>>16) & 0x00000000FFFFFFFF
+
+	return uint32(x)
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -7,8 +7,9 @@ import (
 )
 
 var (
-	mu  sync.RWMutex
-	cfg *configuration.Config
+	mu     sync.RWMutex
+	cfg    *configuration.Config
+	source configuration.Source
 )
 
 func SetConfig(c *configuration.Config) {
@@ -24,3 +25,17 @@ func GetConfig() *configuration.Config {
 
 	return c
 }
+
+func SetConfigSource(src configuration.Source) {
+	mu.Lock()
+	source = src
+	mu.Unlock()
+}
+
+func GetConfigSource() configuration.Source {
+	mu.RLock()
+	src := source
+	mu.RUnlock()
+
+	return src
+}
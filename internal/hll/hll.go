@@ -0,0 +1,170 @@
+package hll
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"sort"
+
+	xxhash "github.com/cespare/xxhash/v2"
+)
+
+const (
+	precision   = 14
+	registers   = 1 << precision
+	sparseLimit = registers / 8
+)
+
+const (
+	encodingSparse byte = iota
+	encodingDense
+)
+
+var ErrCorrupt = errors.New("corrupt HyperLogLog encoding")
+
+type Sketch struct {
+	sparse []uint32
+	dense  []uint8
+}
+
+func New() *Sketch {
+	return &Sketch{}
+}
+
+func (s *Sketch) Add(data []byte) bool {
+	h := xxhash.Sum64(data)
+	idx := uint32(h >> (64 - precision))
+	rank := uint8(bits.LeadingZeros64(h<<precision|1<<(precision-1)) + 1)
+
+	return s.set(idx, rank)
+}
+
+func (s *Sketch) Merge(o *Sketch) {
+	o.each(func(idx uint32, rank uint8) {
+		s.set(idx, rank)
+	})
+}
+
+func (s *Sketch) Clone() *Sketch {
+	return &Sketch{
+		sparse: append([]uint32(nil), s.sparse...),
+		dense:  append([]uint8(nil), s.dense...),
+	}
+}
+
+func (s *Sketch) Count() uint64 {
+	sum, zeros := 0.0, registers
+	s.each(func(_ uint32, rank uint8) {
+		sum += math.Ldexp(1, -int(rank))
+		zeros--
+	})
+	sum += float64(zeros)
+
+	m := float64(registers)
+	estimate := 0.7213 / (1 + 1.079/m) * m * m / sum
+
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(estimate + 0.5)
+}
+
+func (s *Sketch) MarshalBinary() ([]byte, error) {
+	if s.dense != nil {
+		return append([]byte{encodingDense}, s.dense...), nil
+	}
+
+	out := make([]byte, 1, 1+4*len(s.sparse))
+	out[0] = encodingSparse
+	for _, e := range s.sparse {
+		out = binary.LittleEndian.AppendUint32(out, e)
+	}
+
+	return out, nil
+}
+
+func (s *Sketch) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return ErrCorrupt
+	}
+
+	*s = Sketch{}
+	body := data[1:]
+
+	switch data[0] {
+	case encodingDense:
+		if len(body) != registers {
+			return ErrCorrupt
+		}
+		s.dense = append([]uint8(nil), body...)
+	case encodingSparse:
+		if len(body)%4 != 0 {
+			return ErrCorrupt
+		}
+		for i := 0; i < len(body); i += 4 {
+			e := binary.LittleEndian.Uint32(body[i:])
+			if e>>8 >= registers {
+				return ErrCorrupt
+			}
+			s.set(e>>8, uint8(e))
+		}
+	default:
+		return ErrCorrupt
+	}
+
+	return nil
+}
+
+func (s *Sketch) set(idx uint32, rank uint8) bool {
+	if s.dense != nil {
+		if s.dense[idx] >= rank {
+			return false
+		}
+		s.dense[idx] = rank
+		return true
+	}
+
+	i := sort.Search(len(s.sparse), func(i int) bool { return s.sparse[i]>>8 >= idx })
+	if i < len(s.sparse) && s.sparse[i]>>8 == idx {
+		if uint8(s.sparse[i]) >= rank {
+			return false
+		}
+		s.sparse[i] = idx<<8 | uint32(rank)
+		return true
+	}
+
+	s.sparse = append(s.sparse, 0)
+	copy(s.sparse[i+1:], s.sparse[i:])
+	s.sparse[i] = idx<<8 | uint32(rank)
+
+	if len(s.sparse) > sparseLimit {
+		s.densify()
+	}
+
+	return true
+}
+
+func (s *Sketch) densify() {
+	s.dense = make([]uint8, registers)
+	for _, e := range s.sparse {
+		s.dense[e>>8] = uint8(e)
+	}
+	s.sparse = nil
+}
+
+func (s *Sketch) each(fn func(idx uint32, rank uint8)) {
+	if s.dense != nil {
+		for i, r := range s.dense {
+			if r > 0 {
+				fn(uint32(i), r)
+			}
+		}
+		return
+	}
+
+	for _, e := range s.sparse {
+		fn(e>>8, uint8(e))
+	}
+}
@@ -0,0 +1,321 @@
+package jsondoc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrTestFailed   = errors.New("patch test operation failed")
+	ErrInvalidPatch = errors.New("invalid patch")
+)
+
+type PatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+func MergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = MergePatch(t[k], v)
+	}
+
+	return t
+}
+
+func ApplyPatch(doc any, ops []PatchOp) (any, error) {
+	doc = Clone(doc)
+
+	for i, op := range ops {
+		var err error
+		if doc, err = applyOp(doc, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return doc, nil
+}
+
+func applyOp(doc any, op PatchOp) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		value, err := Decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		switch op.Op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			if _, err := lookup(doc, path); err != nil {
+				return nil, err
+			}
+			return replace(doc, path, value)
+		}
+
+		current, err := lookup(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !Equal(current, value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	case "remove":
+		doc, _, err := remove(doc, path)
+		return doc, err
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := lookup(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.Op == "move" {
+			if doc, _, err = remove(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			value = Clone(value)
+		}
+		return add(doc, path, value)
+	}
+
+	return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.Op)
+}
+
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, ErrInvalidPath
+	}
+
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+func lookup(doc any, tokens []string) (any, error) {
+	node := doc
+	for _, t := range tokens {
+		switch n := node.(type) {
+		case map[string]any:
+			child, ok := n[t]
+			if !ok {
+				return nil, ErrPathNotFound
+			}
+			node = child
+		case []any:
+			idx, err := strconv.Atoi(t)
+			if err != nil || idx < 0 || idx >= len(n) {
+				return nil, ErrPathNotFound
+			}
+			node = n[idx]
+		default:
+			return nil, ErrPathNotFound
+		}
+	}
+
+	return node, nil
+}
+
+func withParent(doc any, tokens []string, fn func(parent any, last string) (any, error)) (any, error) {
+	if len(tokens) == 1 {
+		return fn(doc, tokens[0])
+	}
+
+	switch n := doc.(type) {
+	case map[string]any:
+		child, ok := n[tokens[0]]
+		if !ok {
+			return nil, ErrPathNotFound
+		}
+		updated, err := withParent(child, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[tokens[0]] = updated
+		return n, nil
+	case []any:
+		idx, err := strconv.Atoi(tokens[0])
+		if err != nil || idx < 0 || idx >= len(n) {
+			return nil, ErrPathNotFound
+		}
+		updated, err := withParent(n[idx], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[idx] = updated
+		return n, nil
+	}
+
+	return nil, ErrPathNotFound
+}
+
+func add(doc any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	return withParent(doc, tokens, func(parent any, last string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			p[last] = value
+			return p, nil
+		case []any:
+			if last == "-" {
+				return append(p, value), nil
+			}
+			idx, err := strconv.Atoi(last)
+			if err != nil || idx < 0 || idx > len(p) {
+				return nil, ErrPathNotFound
+			}
+			p = append(p, nil)
+			copy(p[idx+1:], p[idx:])
+			p[idx] = value
+			return p, nil
+		}
+		return nil, ErrPathNotFound
+	})
+}
+
+func replace(doc any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	return withParent(doc, tokens, func(parent any, last string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			p[last] = value
+			return p, nil
+		case []any:
+			idx, _ := strconv.Atoi(last)
+			p[idx] = value
+			return p, nil
+		}
+		return nil, ErrPathNotFound
+	})
+}
+
+func remove(doc any, tokens []string) (any, any, error) {
+	if len(tokens) == 0 {
+		return nil, nil, ErrInvalidPath
+	}
+
+	var removed any
+	doc, err := withParent(doc, tokens, func(parent any, last string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			v, ok := p[last]
+			if !ok {
+				return nil, ErrPathNotFound
+			}
+			removed = v
+			delete(p, last)
+			return p, nil
+		case []any:
+			idx, err := strconv.Atoi(last)
+			if err != nil || idx < 0 || idx >= len(p) {
+				return nil, ErrPathNotFound
+			}
+			removed = p[idx]
+			return append(p[:idx], p[idx+1:]...), nil
+		}
+		return nil, ErrPathNotFound
+	})
+
+	return doc, removed, err
+}
+
+func Clone(v any) any {
+	switch n := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(n))
+		for k, c := range n {
+			out[k] = Clone(c)
+		}
+		return out
+	case []any:
+		out := make([]any, len(n))
+		for i, c := range n {
+			out[i] = Clone(c)
+		}
+		return out
+	}
+
+	return v
+}
+
+func Equal(a, b any) bool {
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !Equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !Equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		rx, okx := new(big.Rat).SetString(string(x))
+		ry, oky := new(big.Rat).SetString(string(y))
+		return okx && oky && rx.Cmp(ry) == 0
+	}
+
+	return a == b
+}
@@ -0,0 +1,179 @@
+package jsondoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidJSON  = errors.New("value is not valid JSON")
+	ErrInvalidPath  = errors.New("invalid path")
+	ErrPathNotFound = errors.New("path not found")
+)
+
+type Segment struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+type Path []Segment
+
+func Decode(raw []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, ErrInvalidJSON
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, ErrInvalidJSON
+	}
+
+	return v, nil
+}
+
+func ParsePath(s string) (Path, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "" || s == "$":
+		return Path{}, nil
+	case strings.HasPrefix(s, "$"):
+		s = s[1:]
+	case !strings.HasPrefix(s, ".") && !strings.HasPrefix(s, "["):
+		s = "." + s
+	}
+
+	path := make(Path, 0, 4)
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			if end == 0 {
+				return nil, ErrInvalidPath
+			}
+			path = append(path, Segment{Key: s[:end]})
+			s = s[end:]
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, ErrInvalidPath
+			}
+			inner := s[1:end]
+			s = s[end+1:]
+
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				path = append(path, Segment{Key: inner[1 : len(inner)-1]})
+				continue
+			}
+
+			idx, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, ErrInvalidPath
+			}
+			path = append(path, Segment{Index: idx, IsIndex: true})
+		default:
+			return nil, ErrInvalidPath
+		}
+	}
+
+	return path, nil
+}
+
+func Get(node any, path Path) (any, bool) {
+	for _, seg := range path {
+		switch n := node.(type) {
+		case map[string]any:
+			if seg.IsIndex {
+				return nil, false
+			}
+			child, ok := n[seg.Key]
+			if !ok {
+				return nil, false
+			}
+			node = child
+		case []any:
+			idx, ok := arrayIndex(seg, len(n))
+			if !ok || idx >= len(n) {
+				return nil, false
+			}
+			node = n[idx]
+		default:
+			return nil, false
+		}
+	}
+
+	return node, true
+}
+
+func Set(node any, path Path, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	seg := path[0]
+	switch n := node.(type) {
+	case map[string]any:
+		if seg.IsIndex {
+			return nil, ErrPathNotFound
+		}
+
+		child, ok := n[seg.Key]
+		if !ok {
+			if len(path) > 1 {
+				return nil, ErrPathNotFound
+			}
+			n[seg.Key] = value
+			return n, nil
+		}
+
+		updated, err := Set(child, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[seg.Key] = updated
+		return n, nil
+	case []any:
+		idx, ok := arrayIndex(seg, len(n))
+		if !ok {
+			return nil, ErrPathNotFound
+		}
+		if idx == len(n) && len(path) == 1 {
+			return append(n, value), nil
+		}
+		if idx >= len(n) {
+			return nil, ErrPathNotFound
+		}
+
+		updated, err := Set(n[idx], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[idx] = updated
+		return n, nil
+	}
+
+	return nil, ErrPathNotFound
+}
+
+func arrayIndex(seg Segment, length int) (int, bool) {
+	if !seg.IsIndex {
+		return 0, false
+	}
+
+	idx := seg.Index
+	if idx < 0 {
+		idx += length
+	}
+
+	return idx, idx >= 0
+}
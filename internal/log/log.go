@@ -1,9 +1,13 @@
 package log
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,69 +20,318 @@ const (
 	cyan    = "\033[36m"
 )
 
+const DefaultBufferSize = 8192
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "", "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+
+	return LevelInfo, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", s)
+}
+
+type Options struct {
+	Level      Level
+	JSON       bool
+	Color      bool
+	BufferSize int
+	File       string
+	MaxSizeMB  int
+	MaxAge     time.Duration
+	MaxBackups int
+	Encrypt    func([]byte) ([]byte, error)
+}
+
+type entry struct {
+	time  time.Time
+	level Level
+	label string
+	color string
+	msg   string
+}
+
 type logsContainer struct {
-	mu       sync.Mutex
-	LogsList []string
+	mu      sync.Mutex
+	ring    []entry
+	head    int
+	count   int
+	dropped uint64
+
+	writeMu sync.Mutex
+	opts    Options
+	file    *rotatingFile
+	level   atomic.Int32
 }
 
-var Logs = logsContainer{}
+var Logs = newLogsContainer()
+
+func newLogsContainer() *logsContainer {
+	l := &logsContainer{
+		ring: make([]entry, DefaultBufferSize),
+		opts: Options{Level: LevelDebug, Color: stdoutIsTerminal()},
+	}
+	l.level.Store(int32(LevelDebug))
+
+	return l
+}
+
+func Configure(opts Options) error {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = DefaultBufferSize
+	}
+
+	var file *rotatingFile
+	if opts.File != "" {
+		f, err := openRotatingFile(opts.File, int64(opts.MaxSizeMB)<<20, opts.MaxAge, opts.MaxBackups)
+		if err != nil {
+			return err
+		}
+		file = f
+	}
+
+	WriteLogs()
+
+	Logs.writeMu.Lock()
+	old := Logs.file
+	Logs.opts = opts
+	Logs.file = file
+	Logs.writeMu.Unlock()
+
+	Logs.level.Store(int32(opts.Level))
+
+	if old != nil {
+		_ = old.Close()
+	}
+
+	Logs.mu.Lock()
+	if len(Logs.ring) != opts.BufferSize {
+		Logs.ring = make([]entry, opts.BufferSize)
+		Logs.head = 0
+		Logs.count = 0
+	}
+	Logs.mu.Unlock()
+
+	return nil
+}
+
+func SetLevel(level Level) {
+	Logs.writeMu.Lock()
+	Logs.opts.Level = level
+	Logs.writeMu.Unlock()
+
+	Logs.level.Store(int32(level))
+}
+
+func Dropped() uint64 {
+	Logs.mu.Lock()
+	defer Logs.mu.Unlock()
+
+	return Logs.dropped
+}
 
 func Info(args ...interface{}) {
-	go log("INFO", cyan, args...)
+	log(LevelInfo, "INFO", cyan, args...)
 }
 
 func DirectInfo(args ...interface{}) {
-	directLog("INFO", cyan, args...)
+	directLog(LevelInfo, "INFO", cyan, args...)
 }
 
 func Success(args ...interface{}) {
-	go log("SUCCESS", green, args...)
+	log(LevelInfo, "SUCCESS", green, args...)
 }
 
 func Warn(args ...interface{}) {
-	go log("WARN", yellow, args...)
+	log(LevelWarn, "WARN", yellow, args...)
 }
 
 func Error(args ...interface{}) {
-	go log("ERROR", red, args...)
+	log(LevelError, "ERROR", red, args...)
 }
 
 func Debug(args ...interface{}) {
-	go log("DEBUG", magenta, args...)
+	log(LevelDebug, "DEBUG", magenta, args...)
 }
 
+// Fatal queues the message behind anything still buffered regardless of the
+// configured level, flushes every sink and exits.
 func Fatal(message string, err error) {
-	fmt.Printf("FATAL: %s: %v\n", message, err)
+	e := entry{time: time.Now(), level: LevelError, label: "FATAL", color: red, msg: fmt.Sprintf("%s: %v", strings.TrimSuffix(message, ":"), err)}
+
+	Logs.push(e)
+	WriteLogs()
+
+	Logs.writeMu.Lock()
+	if Logs.file != nil {
+		_ = Logs.file.Sync()
+	}
+	Logs.writeMu.Unlock()
+
 	os.Exit(1)
 }
 
-func log(level string, color string, args ...interface{}) {
-	now := time.Now().Format("2006-01-02 15:04:05")
-	msg := fmt.Sprint(args...)
+func enabled(level Level) bool {
+	return int32(level) >= Logs.level.Load()
+}
 
-	levelBadge := fmt.Sprintf("%s%s%s", color, level, reset)
+func log(level Level, label string, color string, args ...interface{}) {
+	if !enabled(level) {
+		return
+	}
 
-	Logs.mu.Lock()
-	defer Logs.mu.Unlock()
-	Logs.LogsList = append(Logs.LogsList, fmt.Sprintf("[%s] %s %s", now, levelBadge, msg))
+	Logs.push(entry{time: time.Now(), level: level, label: label, color: color, msg: fmt.Sprint(args...)})
+}
+
+func (l *logsContainer) push(e entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	size := len(l.ring)
+	if l.count == size {
+		l.ring[l.head] = e
+		l.head = (l.head + 1) % size
+		l.dropped++
+		return
+	}
+
+	l.ring[(l.head+l.count)%size] = e
+	l.count++
 }
 
-func directLog(level string, color string, args ...interface{}) {
-	now := time.Now().Format("2006-01-02 15:04:05")
-	msg := fmt.Sprint(args...)
+func directLog(level Level, label string, color string, args ...interface{}) {
+	if !enabled(level) {
+		return
+	}
+
+	WriteLogs()
+
+	e := entry{time: time.Now(), level: level, label: label, color: color, msg: fmt.Sprint(args...)}
 
-	levelBadge := fmt.Sprintf("%s%s%s", color, level, reset)
+	Logs.writeMu.Lock()
+	defer Logs.writeMu.Unlock()
 
-	fmt.Printf("[%s] %s %s\n", now, levelBadge, msg)
+	Logs.write([]entry{e})
 }
 
 func WriteLogs() {
+	Logs.writeMu.Lock()
+	defer Logs.writeMu.Unlock()
+
 	Logs.mu.Lock()
-	logsList := Logs.LogsList
-	Logs.LogsList = Logs.LogsList[:0]
+	pending := make([]entry, 0, Logs.count)
+	for i := 0; i < Logs.count; i++ {
+		pending = append(pending, Logs.ring[(Logs.head+i)%len(Logs.ring)])
+	}
+	Logs.head = 0
+	Logs.count = 0
+	dropped := Logs.dropped
+	Logs.dropped = 0
 	Logs.mu.Unlock()
 
-	for _, log := range logsList {
-		fmt.Println(log)
+	if dropped > 0 {
+		pending = append(pending, entry{
+			time:  time.Now(),
+			level: LevelWarn,
+			label: "WARN",
+			color: yellow,
+			msg:   fmt.Sprintf("log buffer full, %d message(s) dropped", dropped),
+		})
 	}
+
+	Logs.write(pending)
+}
+
+func (l *logsContainer) write(entries []entry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	var out strings.Builder
+	var plain strings.Builder
+
+	for _, e := range entries {
+		out.WriteString(l.format(e, l.opts.Color))
+		out.WriteByte('\n')
+
+		if l.file != nil {
+			line := l.format(e, false)
+			if l.opts.Encrypt != nil {
+				line = l.seal(line)
+			}
+			if line != "" {
+				plain.WriteString(line)
+				plain.WriteByte('\n')
+			}
+		}
+	}
+
+	_, _ = os.Stdout.WriteString(out.String())
+
+	if l.file != nil && plain.Len() > 0 {
+		if _, err := l.file.Write([]byte(plain.String())); err != nil {
+			fmt.Fprintf(os.Stderr, "log file write error: %v\n", err)
+		}
+	}
+}
+
+func (l *logsContainer) format(e entry, color bool) string {
+	if l.opts.JSON {
+		b, _ := json.Marshal(struct {
+			Time  string `json:"time"`
+			Level string `json:"level"`
+			Msg   string `json:"msg"`
+		}{
+			Time:  e.time.Format(time.RFC3339Nano),
+			Level: strings.ToLower(e.label),
+			Msg:   e.msg,
+		})
+		return string(b)
+	}
+
+	now := e.time.Format("2006-01-02 15:04:05")
+	levelBadge := e.label
+	if color {
+		levelBadge = fmt.Sprintf("%s%s%s", e.color, e.label, reset)
+	}
+
+	return fmt.Sprintf("[%s] %s %s", now, levelBadge, e.msg)
+}
+
+func (l *logsContainer) seal(line string) string {
+	sealed, err := l.opts.Encrypt([]byte(line))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "log encryption error: %v\n", err)
+		return ""
+	}
+
+	return base64.StdEncoding.EncodeToString(sealed)
+}
+
+func stdoutIsTerminal() bool {
+	fi, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+func ColorAuto() bool {
+	return stdoutIsTerminal()
 }
@@ -0,0 +1,110 @@
+package reload
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/log"
+)
+
+type Result struct {
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restartRequired"`
+}
+
+type Hook func(applied []string, cfg *configuration.Config)
+
+var reloadable = []string{
+	"store.flushIntervalSeconds",
+	"store.maxKeyBytes",
+	"store.maxValueBytes",
+	"server.shutdownTimeoutSeconds",
+	"log.",
+	"stats.enabled",
+	"slowlog.",
+}
+
+var (
+	mu    sync.Mutex
+	hooks []Hook
+)
+
+func OnReload(h Hook) {
+	mu.Lock()
+	hooks = append(hooks, h)
+	mu.Unlock()
+}
+
+func Reload() (*Result, error) {
+	res, err := apply()
+	if err != nil {
+		log.Error("Config reload rejected: ", err)
+		return nil, err
+	}
+
+	if len(res.Applied) > 0 {
+		log.Info("Config reloaded, applied: ", strings.Join(res.Applied, ", "))
+	}
+	if len(res.RestartRequired) > 0 {
+		log.Warn("Config changes require a restart: ", strings.Join(res.RestartRequired, ", "))
+	}
+
+	return res, nil
+}
+
+func apply() (*Result, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	next, err := globals.GetConfigSource().Load()
+	if err != nil {
+		return nil, err
+	}
+
+	current := globals.GetConfig()
+	result := &Result{Applied: []string{}, RestartRequired: []string{}}
+
+	for _, key := range configuration.Diff(current, next) {
+		if IsReloadable(key) {
+			result.Applied = append(result.Applied, key)
+		} else {
+			result.RestartRequired = append(result.RestartRequired, key)
+		}
+	}
+
+	if len(result.Applied) == 0 {
+		return result, nil
+	}
+
+	merged := *current
+	configuration.CopyFields(&merged, next, result.Applied)
+	globals.SetConfig(&merged)
+
+	for _, h := range hooks {
+		h(result.Applied, &merged)
+	}
+
+	return result, nil
+}
+
+func IsReloadable(key string) bool {
+	for _, r := range reloadable {
+		if key == r || (strings.HasSuffix(r, ".") && strings.HasPrefix(key, r)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func Changed(applied []string, prefix string) bool {
+	for _, key := range applied {
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,52 @@
+package routing
+
+import (
+	"time"
+
+	"github.com/fasthttp/router"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/stat"
+	"github.com/taymour/elysiandb/internal/transport/http/controller"
+	"github.com/valyala/fasthttp"
+)
+
+func Instrument(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		statsEnabled := globals.GetConfig().Stats.Enabled
+		if !statsEnabled && !stat.Slowlog.Enabled() {
+			next(ctx)
+			return
+		}
+
+		start := time.Now()
+		next(ctx)
+		elapsed := time.Since(start)
+
+		if statsEnabled {
+			stat.Stats.ObserveCommand(stat.ProtocolHTTP, routeName(ctx), elapsed)
+			stat.Stats.AddBytesIn(stat.ProtocolHTTP, len(ctx.Request.Header.RawHeaders())+len(ctx.Request.Body()))
+			stat.Stats.AddBytesOut(stat.ProtocolHTTP, len(ctx.Response.Body()))
+		}
+
+		if blocking, _ := ctx.UserValue(controller.BlockingUserValue).(bool); !blocking && stat.Slowlog.IsSlow(elapsed) {
+			stat.Slowlog.Add(stat.ProtocolHTTP, routeName(ctx), slowlogKey(ctx), start, elapsed)
+		}
+	}
+}
+
+func routeName(ctx *fasthttp.RequestCtx) string {
+	path, ok := ctx.UserValue(router.MatchedRoutePathParam).(string)
+	if !ok {
+		path = "unmatched"
+	}
+
+	return string(ctx.Method()) + " " + path
+}
+
+func slowlogKey(ctx *fasthttp.RequestCtx) string {
+	if key, ok := ctx.UserValue("key").(string); ok {
+		return key
+	}
+
+	return string(ctx.Path())
+}
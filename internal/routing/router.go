@@ -2,23 +2,112 @@ package routing
 
 import (
 	"github.com/fasthttp/router"
-	"github.com/taymour/elysiandb/internal/globals"
 	"github.com/taymour/elysiandb/internal/transport/http/controller"
 )
 
 func RegisterRoutes(r *router.Router) {
+	r.SaveMatchedRoutePath = true
+
 	r.GET("/health", controller.HealthController)
 
 	r.GET("/kv/mget", controller.MultiGetController)
 	r.GET("/kv/{key}", controller.GetKeyController)
+	r.HEAD("/kv/{key}", controller.HeadKeyController)
 	r.PUT("/kv/{key}", controller.PutKeyController)
 	r.DELETE("/kv/{key}", controller.DeleteKeyController)
 
+	r.GET("/kv/{key}/ttl", controller.GetTTLController)
+	r.PUT("/kv/{key}/ttl", controller.PutTTLController)
+	r.DELETE("/kv/{key}/ttl", controller.DeleteTTLController)
+
+	r.GET("/hash/{key}", controller.GetHashController)
+	r.PUT("/hash/{key}", controller.PutHashController)
+	r.GET("/hash/{key}/{field}", controller.GetHashFieldController)
+	r.PUT("/hash/{key}/{field}", controller.PutHashFieldController)
+	r.DELETE("/hash/{key}/{field}", controller.DeleteHashFieldController)
+	r.POST("/hash/{key}/{field}/incr", controller.IncrHashFieldController)
+
+	r.GET("/list/{key}", controller.GetListController)
+	r.GET("/list/{key}/len", controller.ListLengthController)
+	r.POST("/list/{key}/lpush", controller.LPushController)
+	r.POST("/list/{key}/rpush", controller.RPushController)
+	r.POST("/list/{key}/lpop", controller.LPopController)
+	r.POST("/list/{key}/rpop", controller.RPopController)
+	r.POST("/list/{key}/trim", controller.TrimListController)
+
+	r.GET("/set/inter", controller.SetInterController)
+	r.GET("/set/union", controller.SetUnionController)
+	r.GET("/set/{key}", controller.GetSetController)
+	r.PUT("/set/{key}", controller.PutSetController)
+	r.GET("/set/{key}/{member}", controller.GetSetMemberController)
+	r.DELETE("/set/{key}/{member}", controller.DeleteSetMemberController)
+
+	r.GET("/zset/{key}", controller.GetZSetController)
+	r.PUT("/zset/{key}", controller.PutZSetController)
+	r.GET("/zset/{key}/{member}/rank", controller.ZSetRankController)
+	r.POST("/zset/{key}/{member}/incr", controller.IncrZSetMemberController)
+	r.DELETE("/zset/{key}/{member}", controller.DeleteZSetMemberController)
+
+	r.GET("/stream/{key}", controller.GetStreamController)
+	r.POST("/stream/{key}", controller.AddStreamEntryController)
+	r.GET("/stream/{key}/len", controller.StreamLengthController)
+	r.GET("/stream/{key}/read", controller.ReadStreamController)
+	r.POST("/stream/{key}/trim", controller.TrimStreamController)
+	r.POST("/stream/{key}/groups/{group}", controller.CreateStreamGroupController)
+	r.DELETE("/stream/{key}/groups/{group}", controller.DeleteStreamGroupController)
+	r.POST("/stream/{key}/groups/{group}/read", controller.ReadStreamGroupController)
+	r.POST("/stream/{key}/groups/{group}/ack", controller.AckStreamGroupController)
+	r.GET("/stream/{key}/groups/{group}/pending", controller.PendingStreamGroupController)
+	r.POST("/stream/{key}/groups/{group}/claim", controller.ClaimStreamGroupController)
+
+	r.GET("/doc/{key}", controller.GetDocumentController)
+	r.PUT("/doc/{key}", controller.PutDocumentController)
+	r.PATCH("/doc/{key}", controller.PatchDocumentController)
+
+	r.PUT("/geo/{key}", controller.PutGeoController)
+	r.GET("/geo/{key}/pos", controller.GeoPosController)
+	r.GET("/geo/{key}/dist", controller.GeoDistController)
+	r.GET("/geo/{key}/search", controller.GeoSearchController)
+
+	r.GET("/hll/count", controller.CountHLLUnionController)
+	r.GET("/hll/{key}", controller.CountHLLController)
+	r.POST("/hll/{key}", controller.AddHLLController)
+	r.POST("/hll/{key}/merge", controller.MergeHLLController)
+
+	r.PUT("/bloom/{key}", controller.ReserveBloomController)
+	r.GET("/bloom/{key}/{item}", controller.GetBloomItemController)
+	r.POST("/bloom/{key}/{item}", controller.AddBloomItemController)
+
+	r.POST("/locks/{name}", controller.AcquireLockController)
+	r.POST("/locks/{name}/renew", controller.RenewLockController)
+	r.DELETE("/locks/{name}", controller.ReleaseLockController)
+
+	r.POST("/ratelimit/{key}", controller.RateLimitController)
+
+	r.POST("/queues/{name}", controller.EnqueueController)
+	r.GET("/queues/{name}", controller.QueueInfoController)
+	r.POST("/queues/{name}/reserve", controller.ReserveJobController)
+	r.GET("/queues/{name}/dead", controller.DeadJobsController)
+	r.POST("/queues/{name}/jobs/{id}/ack", controller.AckJobController)
+	r.POST("/queues/{name}/jobs/{id}/nack", controller.NackJobController)
+	r.POST("/queues/{name}/jobs/{id}/touch", controller.TouchJobController)
+
+	r.DELETE("/tags/{tag}", controller.InvalidateTagController)
+
+	r.GET("/indexes", controller.ListIndexesController)
+	r.POST("/indexes", controller.CreateIndexController)
+	r.DELETE("/indexes/{name}", controller.DeleteIndexController)
+	r.GET("/query", controller.QueryController)
+
 	r.POST("/save", controller.SaveController)
 
 	r.POST("/reset", controller.ResetController)
 
-	if globals.GetConfig().Stats.Enabled {
-		r.GET("/stats", controller.StatsController)
-	}
+	r.GET("/slowlog", controller.SlowlogController)
+	r.DELETE("/slowlog", controller.ResetSlowlogController)
+
+	r.GET("/stats", controller.StatsController)
+	r.GET("/metrics", controller.MetricsController)
+
+	r.POST("/admin/config/reload", controller.ReloadConfigController)
 }
@@ -0,0 +1,254 @@
+package skiplist
+
+import "math/rand/v2"
+
+const (
+	maxLevel    = 32
+	probability = 0.25
+)
+
+type Element struct {
+	Member string
+	Score  float64
+}
+
+type level struct {
+	next *node
+	span int
+}
+
+type node struct {
+	Element
+	prev   *node
+	levels []level
+}
+
+type List struct {
+	head   *node
+	tail   *node
+	length int
+	level  int
+}
+
+func New() *List {
+	return &List{
+		head:  &node{levels: make([]level, maxLevel)},
+		level: 1,
+	}
+}
+
+func (l *List) Len() int {
+	return l.length
+}
+
+func (n *node) before(score float64, member string) bool {
+	return n.Score < score || (n.Score == score && n.Member < member)
+}
+
+func (n *node) atOrBefore(score float64, member string) bool {
+	return n.Score < score || (n.Score == score && n.Member <= member)
+}
+
+func randomLevel() int {
+	lvl := 1
+	for lvl < maxLevel && rand.Float64() < probability {
+		lvl++
+	}
+
+	return lvl
+}
+
+func (l *List) Insert(member string, score float64) {
+	var update [maxLevel]*node
+	var rank [maxLevel]int
+
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		if i < l.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.levels[i].next != nil && x.levels[i].next.before(score, member) {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+
+	lvl := randomLevel()
+	if lvl > l.level {
+		for i := l.level; i < lvl; i++ {
+			rank[i] = 0
+			update[i] = l.head
+			update[i].levels[i].span = l.length
+		}
+		l.level = lvl
+	}
+
+	n := &node{Element: Element{Member: member, Score: score}, levels: make([]level, lvl)}
+	for i := 0; i < lvl; i++ {
+		n.levels[i].next = update[i].levels[i].next
+		update[i].levels[i].next = n
+
+		n.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+
+	for i := lvl; i < l.level; i++ {
+		update[i].levels[i].span++
+	}
+
+	if update[0] != l.head {
+		n.prev = update[0]
+	}
+	if n.levels[0].next != nil {
+		n.levels[0].next.prev = n
+	} else {
+		l.tail = n
+	}
+
+	l.length++
+}
+
+func (l *List) Delete(member string, score float64) bool {
+	var update [maxLevel]*node
+
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && x.levels[i].next.before(score, member) {
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+
+	x = x.levels[0].next
+	if x == nil || x.Score != score || x.Member != member {
+		return false
+	}
+
+	for i := 0; i < l.level; i++ {
+		if update[i].levels[i].next == x {
+			update[i].levels[i].span += x.levels[i].span - 1
+			update[i].levels[i].next = x.levels[i].next
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+
+	if x.levels[0].next != nil {
+		x.levels[0].next.prev = x.prev
+	} else {
+		l.tail = x.prev
+	}
+
+	for l.level > 1 && l.head.levels[l.level-1].next == nil {
+		l.level--
+	}
+	l.length--
+
+	return true
+}
+
+func (l *List) Rank(member string, score float64) (int, bool) {
+	rank := 0
+
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && x.levels[i].next.atOrBefore(score, member) {
+			rank += x.levels[i].span
+			x = x.levels[i].next
+		}
+		if x != l.head && x.Score == score && x.Member == member {
+			return rank - 1, true
+		}
+	}
+
+	return 0, false
+}
+
+func (l *List) byRank(rank int) *node {
+	traversed := 0
+
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && traversed+x.levels[i].span <= rank {
+			traversed += x.levels[i].span
+			x = x.levels[i].next
+		}
+		if traversed == rank {
+			return x
+		}
+	}
+
+	return nil
+}
+
+func (l *List) Range(start, stop int) []Element {
+	if start < 0 || stop < start || start >= l.length {
+		return nil
+	}
+	stop = min(stop, l.length-1)
+
+	n := stop - start + 1
+	out := make([]Element, 0, n)
+	for x := l.byRank(start + 1); x != nil && len(out) < n; x = x.levels[0].next {
+		out = append(out, x.Element)
+	}
+
+	return out
+}
+
+type ScoreRange struct {
+	Min, Max                   float64
+	MinExclusive, MaxExclusive bool
+}
+
+func (r ScoreRange) aboveMin(score float64) bool {
+	if r.MinExclusive {
+		return score > r.Min
+	}
+
+	return score >= r.Min
+}
+
+func (r ScoreRange) belowMax(score float64) bool {
+	if r.MaxExclusive {
+		return score < r.Max
+	}
+
+	return score <= r.Max
+}
+
+func (l *List) RangeByScore(r ScoreRange, offset, count int) []Element {
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && !r.aboveMin(x.levels[i].next.Score) {
+			x = x.levels[i].next
+		}
+	}
+
+	out := make([]Element, 0)
+	for x = x.levels[0].next; x != nil && r.belowMax(x.Score); x = x.levels[0].next {
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if count >= 0 && len(out) == count {
+			break
+		}
+		out = append(out, x.Element)
+	}
+
+	return out
+}
+
+func (l *List) Ascend(score float64, member string, fn func(Element) bool) {
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && x.levels[i].next.before(score, member) {
+			x = x.levels[i].next
+		}
+	}
+
+	for x = x.levels[0].next; x != nil && fn(x.Element); x = x.levels[0].next {
+	}
+}
@@ -0,0 +1,107 @@
+package stat
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	subBucketBits  = 4
+	subBucketCount = 1 << subBucketBits
+	linearBuckets  = subBucketCount * 2
+	hdrBucketCount = linearBuckets + (64-subBucketBits-1)*subBucketCount
+)
+
+type LatencyHistogram struct {
+	counts    [hdrBucketCount]atomic.Uint64
+	total     atomic.Uint64
+	sumMicros atomic.Uint64
+	maxMicros atomic.Uint64
+}
+
+func hdrIndex(v uint64) int {
+	if v < linearBuckets {
+		return int(v)
+	}
+
+	shift := bits.Len64(v) - subBucketBits - 1
+
+	return linearBuckets + (shift-1)*subBucketCount + int(v>>shift) - subBucketCount
+}
+
+func hdrUpperBound(i int) uint64 {
+	if i < linearBuckets {
+		return uint64(i)
+	}
+
+	shift := (i-linearBuckets)/subBucketCount + 1
+	sub := uint64((i-linearBuckets)%subBucketCount + subBucketCount)
+
+	return ((sub + 1) << shift) - 1
+}
+
+func (h *LatencyHistogram) Record(d time.Duration) {
+	us := uint64(d.Microseconds())
+	if d < 0 {
+		us = 0
+	}
+
+	h.counts[hdrIndex(us)].Add(1)
+	h.total.Add(1)
+	h.sumMicros.Add(us)
+
+	for {
+		cur := h.maxMicros.Load()
+		if us <= cur || h.maxMicros.CompareAndSwap(cur, us) {
+			return
+		}
+	}
+}
+
+func (h *LatencyHistogram) Count() uint64 {
+	return h.total.Load()
+}
+
+func (h *LatencyHistogram) Sum() time.Duration {
+	return time.Duration(h.sumMicros.Load()) * time.Microsecond
+}
+
+func (h *LatencyHistogram) Max() time.Duration {
+	return time.Duration(h.maxMicros.Load()) * time.Microsecond
+}
+
+func (h *LatencyHistogram) Quantile(q float64) time.Duration {
+	total := h.total.Load()
+	if total == 0 {
+		return 0
+	}
+
+	rank := uint64(q * float64(total))
+	if rank == 0 {
+		rank = 1
+	}
+
+	seen := uint64(0)
+	for i := range h.counts {
+		seen += h.counts[i].Load()
+		if seen >= rank {
+			us := hdrUpperBound(i)
+			if m := h.maxMicros.Load(); us > m {
+				us = m
+			}
+			return time.Duration(us) * time.Microsecond
+		}
+	}
+
+	return h.Max()
+}
+
+func (h *LatencyHistogram) Reset() {
+	for i := range h.counts {
+		h.counts[i].Store(0)
+	}
+	h.total.Store(0)
+	h.sumMicros.Store(0)
+	h.maxMicros.Store(0)
+}
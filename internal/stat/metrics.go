@@ -0,0 +1,227 @@
+package stat
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	ProtocolTCP  = "tcp"
+	ProtocolHTTP = "http"
+)
+
+var protocols = [...]string{ProtocolTCP, ProtocolHTTP}
+
+var latencyBuckets = [...]float64{
+	0.00005, 0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005,
+	0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5,
+}
+
+type histogram struct {
+	buckets  [len(latencyBuckets) + 1]atomic.Uint64
+	count    atomic.Uint64
+	sumNanos atomic.Uint64
+}
+
+func (h *histogram) observe(d time.Duration) {
+	s := d.Seconds()
+	i := sort.SearchFloat64s(latencyBuckets[:], s)
+	h.buckets[i].Add(1)
+	h.count.Add(1)
+	h.sumNanos.Add(uint64(d.Nanoseconds()))
+}
+
+func (h *histogram) reset() {
+	for i := range h.buckets {
+		h.buckets[i].Store(0)
+	}
+	h.count.Store(0)
+	h.sumNanos.Store(0)
+}
+
+type commandKey struct {
+	protocol string
+	name     string
+}
+
+type commandMetrics struct {
+	requests atomic.Uint64
+	latency  LatencyHistogram
+}
+
+var commandQuantiles = [...]float64{0.5, 0.9, 0.99, 0.999}
+
+func protocolIndex(protocol string) int {
+	if protocol == ProtocolHTTP {
+		return 1
+	}
+	return 0
+}
+
+func (s *StatsContainer) command(protocol string, name string) *commandMetrics {
+	k := commandKey{protocol: protocol, name: name}
+	if v, ok := s.commands.Load(k); ok {
+		return v.(*commandMetrics)
+	}
+
+	v, _ := s.commands.LoadOrStore(k, &commandMetrics{})
+	return v.(*commandMetrics)
+}
+
+func (s *StatsContainer) ObserveCommand(protocol string, name string, d time.Duration) {
+	c := s.command(protocol, name)
+	c.requests.Add(1)
+	c.latency.Record(d)
+	s.latency[protocolIndex(protocol)].observe(d)
+}
+
+func (s *StatsContainer) AddBytesIn(protocol string, n int) {
+	s.bytesIn[protocolIndex(protocol)].Add(uint64(n))
+}
+
+func (s *StatsContainer) AddBytesOut(protocol string, n int) {
+	s.bytesOut[protocolIndex(protocol)].Add(uint64(n))
+}
+
+func (s *StatsContainer) IncrementTCPClients() { s.tcpClients.Add(1) }
+func (s *StatsContainer) DecrementTCPClients() { s.tcpClients.Add(-1) }
+func (s *StatsContainer) AddExpiredKeys(n int) { s.expiredKeys.Add(uint64(n)) }
+
+func (s *StatsContainer) ObserveSnapshot(d time.Duration, size int64) {
+	s.snapshotDurationNanos.Store(uint64(d.Nanoseconds()))
+	s.snapshotSizeBytes.Store(uint64(size))
+	s.lastSaveUnix.Store(uint64(time.Now().Unix()))
+	s.snapshots.Add(1)
+}
+
+func (s *StatsContainer) resetMetrics() {
+	for i := range s.latency {
+		s.latency[i].reset()
+		s.bytesIn[i].Store(0)
+		s.bytesOut[i].Store(0)
+	}
+	s.commands.Range(func(k, _ any) bool {
+		s.commands.Delete(k)
+		return true
+	})
+	s.expiredKeys.Store(0)
+	s.snapshotDurationNanos.Store(0)
+	s.snapshotSizeBytes.Store(0)
+	s.lastSaveUnix.Store(0)
+	s.snapshots.Store(0)
+}
+
+func (s *StatsContainer) ToOpenMetrics() string {
+	var b strings.Builder
+
+	gauge := func(name string, help string, v float64) {
+		fmt.Fprintf(&b, "# TYPE %s gauge\n# HELP %s %s\n%s %s\n", name, name, help, name, formatFloat(v))
+	}
+	counter := func(name string, help string, v uint64) {
+		fmt.Fprintf(&b, "# TYPE %s counter\n# HELP %s %s\n%s_total %d\n", name, name, help, name, v)
+	}
+
+	gauge("elysiandb_keys", "Number of live keys in the store.", float64(s.keysCount.Load()))
+	gauge("elysiandb_expiration_keys", "Number of keys tracked with a TTL.", float64(s.expirationKeysCount.Load()))
+	gauge("elysiandb_uptime_seconds", "Seconds since the process started.", float64(s.uptimeSeconds.Load()))
+	counter("elysiandb_requests", "Requests handled over all protocols.", s.totalRequests.Load())
+	counter("elysiandb_hits", "Lookups that found a value.", s.hits.Load())
+	counter("elysiandb_misses", "Lookups that did not find a value.", s.misses.Load())
+	counter("elysiandb_expired_keys", "Keys removed because their TTL elapsed.", s.expiredKeys.Load())
+	gauge("elysiandb_tcp_connected_clients", "Currently connected TCP clients.", float64(s.tcpClients.Load()))
+
+	b.WriteString("# TYPE elysiandb_command_requests counter\n")
+	b.WriteString("# HELP elysiandb_command_requests Requests per protocol and command.\n")
+	for _, k := range s.sortedCommands() {
+		v, _ := s.commands.Load(k)
+		fmt.Fprintf(&b, "elysiandb_command_requests_total{protocol=\"%s\",command=\"%s\"} %d\n",
+			k.protocol, escapeLabel(k.name), v.(*commandMetrics).requests.Load())
+	}
+
+	b.WriteString("# TYPE elysiandb_command_duration_seconds summary\n")
+	b.WriteString("# HELP elysiandb_command_duration_seconds Latency quantiles per protocol and command.\n")
+	for _, k := range s.sortedCommands() {
+		v, _ := s.commands.Load(k)
+		h := &v.(*commandMetrics).latency
+		labels := fmt.Sprintf("protocol=\"%s\",command=\"%s\"", k.protocol, escapeLabel(k.name))
+		for _, q := range commandQuantiles {
+			fmt.Fprintf(&b, "elysiandb_command_duration_seconds{%s,quantile=\"%s\"} %s\n", labels, formatFloat(q), formatFloat(h.Quantile(q).Seconds()))
+		}
+		fmt.Fprintf(&b, "elysiandb_command_duration_seconds_sum{%s} %s\n", labels, formatFloat(h.Sum().Seconds()))
+		fmt.Fprintf(&b, "elysiandb_command_duration_seconds_count{%s} %d\n", labels, h.Count())
+	}
+
+	b.WriteString("# TYPE elysiandb_received_bytes counter\n")
+	b.WriteString("# HELP elysiandb_received_bytes Bytes read from clients.\n")
+	for i, p := range protocols {
+		fmt.Fprintf(&b, "elysiandb_received_bytes_total{protocol=%q} %d\n", p, s.bytesIn[i].Load())
+	}
+
+	b.WriteString("# TYPE elysiandb_sent_bytes counter\n")
+	b.WriteString("# HELP elysiandb_sent_bytes Bytes written to clients.\n")
+	for i, p := range protocols {
+		fmt.Fprintf(&b, "elysiandb_sent_bytes_total{protocol=%q} %d\n", p, s.bytesOut[i].Load())
+	}
+
+	b.WriteString("# TYPE elysiandb_request_duration_seconds histogram\n")
+	b.WriteString("# HELP elysiandb_request_duration_seconds Request latency per protocol.\n")
+	for i, p := range protocols {
+		h := &s.latency[i]
+		cumulative := uint64(0)
+		for j, le := range latencyBuckets {
+			cumulative += h.buckets[j].Load()
+			fmt.Fprintf(&b, "elysiandb_request_duration_seconds_bucket{protocol=%q,le=\"%s\"} %d\n", p, formatFloat(le), cumulative)
+		}
+		cumulative += h.buckets[len(latencyBuckets)].Load()
+		fmt.Fprintf(&b, "elysiandb_request_duration_seconds_bucket{protocol=%q,le=\"+Inf\"} %d\n", p, cumulative)
+		fmt.Fprintf(&b, "elysiandb_request_duration_seconds_sum{protocol=%q} %s\n", p, formatFloat(time.Duration(h.sumNanos.Load()).Seconds()))
+		fmt.Fprintf(&b, "elysiandb_request_duration_seconds_count{protocol=%q} %d\n", p, h.count.Load())
+	}
+
+	counter("elysiandb_snapshots", "Snapshots written to disk.", s.snapshots.Load())
+	gauge("elysiandb_snapshot_duration_seconds", "Duration of the last snapshot.", time.Duration(s.snapshotDurationNanos.Load()).Seconds())
+	gauge("elysiandb_snapshot_size_bytes", "Size of the last snapshot.", float64(s.snapshotSizeBytes.Load()))
+	gauge("elysiandb_last_save_timestamp_seconds", "Unix time of the last successful snapshot.", float64(s.lastSaveUnix.Load()))
+
+	b.WriteString("# EOF\n")
+
+	return b.String()
+}
+
+func (s *StatsContainer) CommandLatency(protocol string, name string) (*LatencyHistogram, bool) {
+	v, ok := s.commands.Load(commandKey{protocol: protocol, name: name})
+	if !ok {
+		return nil, false
+	}
+
+	return &v.(*commandMetrics).latency, true
+}
+
+func (s *StatsContainer) sortedCommands() []commandKey {
+	keys := make([]commandKey, 0)
+	s.commands.Range(func(k, _ any) bool {
+		keys = append(keys, k.(commandKey))
+		return true
+	})
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].protocol != keys[j].protocol {
+			return keys[i].protocol < keys[j].protocol
+		}
+		return keys[i].name < keys[j].name
+	})
+
+	return keys
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(v)
+}
@@ -0,0 +1,134 @@
+package stat
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+const SlowlogMaxKeyLength = 64
+
+type SlowlogEntry struct {
+	ID             uint64    `json:"id"`
+	Timestamp      time.Time `json:"timestamp"`
+	DurationMicros int64     `json:"duration_us"`
+	Protocol       string    `json:"protocol"`
+	Command        string    `json:"command"`
+	Key            string    `json:"key"`
+}
+
+type SlowlogContainer struct {
+	mu        sync.Mutex
+	entries   []SlowlogEntry
+	next      int
+	nextID    uint64
+	maxLen    int
+	enabled   atomic.Bool
+	threshold atomic.Int64
+}
+
+var Slowlog = &SlowlogContainer{maxLen: 128}
+
+func NewSlowlog(maxLen int, threshold time.Duration) *SlowlogContainer {
+	s := &SlowlogContainer{}
+	s.Configure(true, maxLen, threshold)
+
+	return s
+}
+
+func (s *SlowlogContainer) Configure(enabled bool, maxLen int, threshold time.Duration) {
+	if maxLen < 1 {
+		maxLen = 1
+	}
+
+	s.mu.Lock()
+	if maxLen != s.maxLen {
+		kept := s.snapshotLocked(maxLen)
+		s.entries = make([]SlowlogEntry, 0, maxLen)
+		for i := len(kept) - 1; i >= 0; i-- {
+			s.entries = append(s.entries, kept[i])
+		}
+		s.next = len(s.entries) % maxLen
+		s.maxLen = maxLen
+	}
+	s.mu.Unlock()
+
+	s.threshold.Store(int64(threshold))
+	s.enabled.Store(enabled)
+}
+
+func (s *SlowlogContainer) Enabled() bool {
+	return s.enabled.Load()
+}
+
+func (s *SlowlogContainer) IsSlow(d time.Duration) bool {
+	return s.enabled.Load() && int64(d) >= s.threshold.Load()
+}
+
+func (s *SlowlogContainer) Add(protocol string, command string, key string, at time.Time, d time.Duration) {
+	if len(key) > SlowlogMaxKeyLength {
+		// Back the cut off to a rune boundary so the entry stays valid UTF-8.
+		n := SlowlogMaxKeyLength
+		for n > 0 && !utf8.RuneStart(key[n]) {
+			n--
+		}
+		key = key[:n] + "..."
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	e := SlowlogEntry{
+		ID:             s.nextID,
+		Timestamp:      at,
+		DurationMicros: d.Microseconds(),
+		Protocol:       protocol,
+		Command:        command,
+		Key:            key,
+	}
+
+	if len(s.entries) < s.maxLen {
+		s.entries = append(s.entries, e)
+	} else {
+		s.entries[s.next] = e
+	}
+	s.next = (s.next + 1) % s.maxLen
+}
+
+func (s *SlowlogContainer) Get(count int) []SlowlogEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.snapshotLocked(count)
+}
+
+func (s *SlowlogContainer) snapshotLocked(count int) []SlowlogEntry {
+	n := len(s.entries)
+	if count < 0 || count > n {
+		count = n
+	}
+
+	out := make([]SlowlogEntry, 0, count)
+	for i := 0; i < count; i++ {
+		idx := (s.next - 1 - i + n) % n
+		out = append(out, s.entries[idx])
+	}
+
+	return out
+}
+
+func (s *SlowlogContainer) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entries)
+}
+
+func (s *SlowlogContainer) Reset() {
+	s.mu.Lock()
+	s.entries = s.entries[:0]
+	s.next = 0
+	s.mu.Unlock()
+}
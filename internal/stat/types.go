@@ -2,6 +2,7 @@ package stat
 
 import (
 	"encoding/json"
+	"sync"
 	"sync/atomic"
 )
 
@@ -12,6 +13,17 @@ type StatsContainer struct {
 	totalRequests       atomic.Uint64
 	hits                atomic.Uint64
 	misses              atomic.Uint64
+
+	commands              sync.Map
+	latency               [len(protocols)]histogram
+	bytesIn               [len(protocols)]atomic.Uint64
+	bytesOut              [len(protocols)]atomic.Uint64
+	tcpClients            atomic.Int64
+	expiredKeys           atomic.Uint64
+	snapshots             atomic.Uint64
+	snapshotDurationNanos atomic.Uint64
+	snapshotSizeBytes     atomic.Uint64
+	lastSaveUnix          atomic.Uint64
 }
 
 func NewStatsContainer() *StatsContainer {
@@ -58,6 +70,7 @@ func (s *StatsContainer) Reset() {
 	s.totalRequests.Store(0)
 	s.hits.Store(0)
 	s.misses.Store(0)
+	s.resetMetrics()
 }
 
 type statsDTO struct {
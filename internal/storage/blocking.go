@@ -0,0 +1,280 @@
+package storage
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type poppedItem struct {
+	key   string
+	value []byte
+}
+
+type popWaiter struct {
+	left    bool
+	claimed atomic.Bool
+	ch      chan poppedItem
+}
+
+var (
+	blockMu        sync.Mutex
+	listWaiters    = make(map[string][]*popWaiter)
+	releaseBlocked = make(chan struct{})
+)
+
+func BLPop(keys []string, timeout time.Duration, done <-chan struct{}) (string, []byte, bool, error) {
+	return blockingPop(keys, timeout, done, true)
+}
+
+func BRPop(keys []string, timeout time.Duration, done <-chan struct{}) (string, []byte, bool, error) {
+	return blockingPop(keys, timeout, done, false)
+}
+
+func ReleaseBlockedPops() {
+	blockMu.Lock()
+	select {
+	case <-releaseBlocked:
+	default:
+		close(releaseBlocked)
+	}
+	blockMu.Unlock()
+}
+
+func resetBlockedPops() {
+	blockMu.Lock()
+	select {
+	case <-releaseBlocked:
+		releaseBlocked = make(chan struct{})
+	default:
+	}
+	blockMu.Unlock()
+}
+
+func BlockedClients() int {
+	blockMu.Lock()
+	defer blockMu.Unlock()
+
+	seen := make(map[any]struct{})
+	for _, q := range listWaiters {
+		for _, w := range q {
+			if !w.claimed.Load() {
+				seen[w] = struct{}{}
+			}
+		}
+	}
+	for _, q := range keyWatchers {
+		for _, w := range q {
+			seen[w] = struct{}{}
+		}
+	}
+
+	return len(seen)
+}
+
+func blockingPop(keys []string, timeout time.Duration, done <-chan struct{}, left bool) (string, []byte, bool, error) {
+	w := &popWaiter{left: left, ch: make(chan poppedItem, 1)}
+
+	blockMu.Lock()
+	release := releaseBlocked
+	blockMu.Unlock()
+
+	registered := make([]string, 0, len(keys))
+	defer func() { unregisterWaiter(w, registered) }()
+
+	for _, k := range keys {
+		var item []byte
+		var popped, waiting bool
+
+		err := updateTyped(k, TypeList, newList, func(l *listValue) (bool, error) {
+			if l.len() == 0 {
+				blockMu.Lock()
+				listWaiters[k] = append(listWaiters[k], w)
+				blockMu.Unlock()
+				waiting = true
+				return true, nil
+			}
+
+			if w.claimed.CompareAndSwap(false, true) {
+				item, popped = l.pop(left), true
+			}
+			return l.len() == 0, nil
+		})
+
+		if waiting {
+			registered = append(registered, k)
+		}
+		if popped {
+			return k, item, true, nil
+		}
+		if err != nil || w.claimed.Load() {
+			if w.claimed.CompareAndSwap(false, true) {
+				return "", nil, false, err
+			}
+			got := <-w.ch
+			return got.key, got.value, true, nil
+		}
+	}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case got := <-w.ch:
+		return got.key, got.value, true, nil
+	case <-expired:
+	case <-done:
+	case <-release:
+	}
+
+	if w.claimed.CompareAndSwap(false, true) {
+		return "", nil, false, nil
+	}
+
+	got := <-w.ch
+	return got.key, got.value, true, nil
+}
+
+func serveWaiters(key string, l *listValue) {
+	blockMu.Lock()
+	defer blockMu.Unlock()
+
+	q := listWaiters[key]
+	for len(q) > 0 && l.len() > 0 {
+		w := q[0]
+		q[0] = nil
+		q = q[1:]
+
+		if w.claimed.CompareAndSwap(false, true) {
+			w.ch <- poppedItem{key: key, value: l.pop(w.left)}
+		}
+	}
+
+	if len(q) == 0 {
+		delete(listWaiters, key)
+	} else {
+		listWaiters[key] = q
+	}
+}
+
+func unregisterWaiter(w *popWaiter, keys []string) {
+	blockMu.Lock()
+	defer blockMu.Unlock()
+
+	for _, k := range keys {
+		q := listWaiters[k]
+		for i, other := range q {
+			if other == w {
+				q = append(q[:i], q[i+1:]...)
+				break
+			}
+		}
+
+		if len(q) == 0 {
+			delete(listWaiters, k)
+		} else {
+			listWaiters[k] = q
+		}
+	}
+}
+
+type keyWatcher struct {
+	ch chan struct{}
+}
+
+var (
+	keyWatchers = make(map[string][]*keyWatcher)
+	watchedKeys atomic.Int64
+)
+
+func newKeyWatcher() *keyWatcher {
+	return &keyWatcher{ch: make(chan struct{}, 1)}
+}
+
+func watchKey(key string, w *keyWatcher) {
+	blockMu.Lock()
+	keyWatchers[key] = append(keyWatchers[key], w)
+	watchedKeys.Store(int64(len(keyWatchers)))
+	blockMu.Unlock()
+}
+
+func notifyKey(key string) {
+	if watchedKeys.Load() == 0 {
+		return
+	}
+
+	blockMu.Lock()
+	for _, w := range keyWatchers[key] {
+		select {
+		case w.ch <- struct{}{}:
+		default:
+		}
+	}
+	delete(keyWatchers, key)
+	watchedKeys.Store(int64(len(keyWatchers)))
+	blockMu.Unlock()
+}
+
+func unwatchKeys(w *keyWatcher, keys []string) {
+	blockMu.Lock()
+	defer blockMu.Unlock()
+
+	for _, k := range keys {
+		q := keyWatchers[k]
+		for i, other := range q {
+			if other == w {
+				q = append(q[:i], q[i+1:]...)
+				break
+			}
+		}
+
+		if len(q) == 0 {
+			delete(keyWatchers, k)
+		} else {
+			keyWatchers[k] = q
+		}
+	}
+	watchedKeys.Store(int64(len(keyWatchers)))
+}
+
+func waitUntilReady[T any](keys []string, timeout time.Duration, done <-chan struct{}, try func() (T, bool, error)) (T, bool, error) {
+	w := newKeyWatcher()
+	defer unwatchKeys(w, keys)
+
+	blockMu.Lock()
+	release := releaseBlocked
+	blockMu.Unlock()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for {
+		for _, k := range keys {
+			watchKey(k, w)
+		}
+
+		result, ready, err := try()
+		if err != nil || ready {
+			return result, ready, err
+		}
+
+		select {
+		case <-w.ch:
+			unwatchKeys(w, keys)
+		case <-expired:
+			return result, false, nil
+		case <-done:
+			return result, false, nil
+		case <-release:
+			return result, false, nil
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/taymour/elysiandb/internal/bloom"
+	"github.com/taymour/elysiandb/internal/globals"
+)
+
+const (
+	defaultBloomErrorRate = 0.01
+	defaultBloomCapacity  = 100
+)
+
+var ErrKeyExists = errors.New("key already exists")
+
+type bloomValue struct {
+	filter *bloom.Filter
+}
+
+func init() {
+	registerType(TypeBloom, "bloom", func(raw json.RawMessage) (typedValue, error) {
+		var data []byte
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, err
+		}
+
+		v := &bloomValue{filter: &bloom.Filter{}}
+		if err := v.filter.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+}
+
+func newBloom() *bloomValue {
+	f, _ := bloom.New(defaultBloomErrorRate, defaultBloomCapacity)
+	return &bloomValue{filter: f}
+}
+
+func (b *bloomValue) valueType() ValueType { return TypeBloom }
+
+func (b *bloomValue) snapshot() any {
+	data, _ := b.filter.MarshalBinary()
+	return data
+}
+
+func BFReserve(key string, errorRate float64, capacity uint64) error {
+	f, err := bloom.New(errorRate, capacity)
+	if err != nil {
+		return err
+	}
+
+	created := false
+	err = updateTyped(key, TypeBloom, func() *bloomValue {
+		created = true
+		return &bloomValue{filter: f}
+	}, func(b *bloomValue) (bool, error) {
+		if !created {
+			return false, errUnchanged
+		}
+		return false, nil
+	})
+	if err == nil && !created {
+		return ErrKeyExists
+	}
+
+	return err
+}
+
+func BFAdd(key string, item string) (bool, error) {
+	if err := checkSizeLimits(globals.GetConfig(), key, []byte(item)); err != nil {
+		return false, err
+	}
+
+	added := false
+	err := updateTyped(key, TypeBloom, newBloom, func(b *bloomValue) (bool, error) {
+		if added = b.filter.Add([]byte(item)); !added {
+			return false, errUnchanged
+		}
+		return false, nil
+	})
+
+	return added, err
+}
+
+func BFExists(key string, item string) (bool, error) {
+	exists := false
+
+	_, err := viewTyped(key, TypeBloom, func(b *bloomValue) error {
+		exists = b.filter.Exists([]byte(item))
+		return nil
+	})
+
+	return exists, err
+}
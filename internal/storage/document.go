@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"encoding/json"
+
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/jsondoc"
+)
+
+type PatchKind uint8
+
+const (
+	MergePatch PatchKind = iota
+	JSONPatch
+)
+
+type docValue struct {
+	root any
+}
+
+func init() {
+	registerType(TypeJSON, "json", func(raw json.RawMessage) (typedValue, error) {
+		root, err := jsondoc.Decode(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &docValue{root: root}, nil
+	})
+}
+
+func newDoc() *docValue {
+	return &docValue{}
+}
+
+func (d *docValue) valueType() ValueType { return TypeJSON }
+func (d *docValue) snapshot() any        { return d.root }
+
+func (d *docValue) document() (any, bool) {
+	return d.root, true
+}
+
+func SetDocument(key string, raw []byte) error {
+	return SetDocumentPath(key, "$", raw)
+}
+
+func SetDocumentPath(key string, path string, raw []byte) error {
+	if err := checkSizeLimits(globals.GetConfig(), key, raw); err != nil {
+		return err
+	}
+
+	p, err := jsondoc.ParsePath(path)
+	if err != nil {
+		return err
+	}
+
+	value, err := jsondoc.Decode(raw)
+	if err != nil {
+		return err
+	}
+
+	create := newDoc
+	if len(p) > 0 {
+		create = nil
+	}
+
+	found := false
+	err = updateTyped(key, TypeJSON, create, func(d *docValue) (bool, error) {
+		found = true
+
+		root, err := jsondoc.Set(d.root, p, value)
+		if err != nil {
+			return false, err
+		}
+		d.root = root
+		mainStore.indexes.observe(key, d.document)
+
+		return false, nil
+	})
+	if err == nil && !found {
+		return jsondoc.ErrPathNotFound
+	}
+
+	return err
+}
+
+func GetDocument(key string, path string) ([]byte, bool, error) {
+	p, err := jsondoc.ParsePath(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var out []byte
+	found, err := viewTyped(key, TypeJSON, func(d *docValue) error {
+		v, ok := jsondoc.Get(d.root, p)
+		if !ok {
+			return jsondoc.ErrPathNotFound
+		}
+
+		b, err := json.Marshal(v)
+		out = b
+		return err
+	})
+
+	return out, found, err
+}
+
+func PatchDocument(key string, raw []byte, kind PatchKind) ([]byte, error) {
+	if err := checkSizeLimits(globals.GetConfig(), key, raw); err != nil {
+		return nil, err
+	}
+
+	var ops []jsondoc.PatchOp
+	var patch any
+	var err error
+
+	if kind == JSONPatch {
+		if err := json.Unmarshal(raw, &ops); err != nil {
+			return nil, jsondoc.ErrInvalidJSON
+		}
+	} else if patch, err = jsondoc.Decode(raw); err != nil {
+		return nil, err
+	}
+
+	create := newDoc
+	if kind == JSONPatch {
+		create = nil
+	}
+
+	var out []byte
+	found := false
+	err = updateTyped(key, TypeJSON, create, func(d *docValue) (bool, error) {
+		found = true
+
+		root := d.root
+		if kind == JSONPatch {
+			patched, err := jsondoc.ApplyPatch(root, ops)
+			if err != nil {
+				return false, err
+			}
+			root = patched
+		} else {
+			root = jsondoc.MergePatch(root, patch)
+		}
+
+		b, err := json.Marshal(root)
+		if err != nil {
+			return false, err
+		}
+		out, d.root = b, root
+		mainStore.indexes.observe(key, d.document)
+
+		return false, nil
+	})
+	if err == nil && !found {
+		return nil, jsondoc.ErrPathNotFound
+	}
+
+	return out, err
+}
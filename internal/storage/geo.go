@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/taymour/elysiandb/internal/geo"
+)
+
+var ErrNoSuchMember = errors.New("member not found")
+
+type GeoPoint struct {
+	Member string
+	Lon    float64
+	Lat    float64
+}
+
+type GeoMatch struct {
+	GeoPoint
+	Dist float64
+}
+
+type GeoQuery struct {
+	Member        string
+	Lon, Lat      float64
+	Radius        float64
+	Width, Height float64
+	Desc          bool
+	Count         int
+}
+
+func GeoAdd(key string, points []GeoPoint) (int, error) {
+	members := make([]ScoredMember, len(points))
+	for i, p := range points {
+		if !geo.Valid(p.Lon, p.Lat) {
+			return 0, geo.ErrInvalidCoordinates
+		}
+		members[i] = ScoredMember{Member: p.Member, Score: float64(geo.Encode(p.Lon, p.Lat))}
+	}
+
+	return ZAdd(key, members)
+}
+
+func GeoPos(key string, members []string) ([]*GeoPoint, error) {
+	out := make([]*GeoPoint, len(members))
+
+	_, err := viewTyped(key, TypeZSet, func(z *zsetValue) error {
+		for i, m := range members {
+			if score, ok := z.scores[m]; ok {
+				lon, lat := geo.Decode(uint64(score))
+				out[i] = &GeoPoint{Member: m, Lon: lon, Lat: lat}
+			}
+		}
+		return nil
+	})
+
+	return out, err
+}
+
+func GeoDist(key string, from, to string) (float64, error) {
+	points, err := GeoPos(key, []string{from, to})
+	if err != nil {
+		return 0, err
+	}
+	if points[0] == nil || points[1] == nil {
+		return 0, ErrNoSuchMember
+	}
+
+	return geo.Distance(points[0].Lon, points[0].Lat, points[1].Lon, points[1].Lat), nil
+}
+
+func GeoSearch(key string, q GeoQuery) ([]GeoMatch, error) {
+	if q.Member == "" && !geo.Valid(q.Lon, q.Lat) {
+		return nil, geo.ErrInvalidCoordinates
+	}
+
+	var out []GeoMatch
+
+	_, err := viewTyped(key, TypeZSet, func(z *zsetValue) error {
+		lon, lat := q.Lon, q.Lat
+		if q.Member != "" {
+			score, ok := z.scores[q.Member]
+			if !ok {
+				return ErrNoSuchMember
+			}
+			lon, lat = geo.Decode(uint64(score))
+		}
+
+		radius := q.Radius
+		if q.Width > 0 {
+			radius = math.Hypot(q.Width/2, q.Height/2)
+		}
+
+		for _, r := range geo.SearchRanges(lon, lat, radius) {
+			candidates := z.list.RangeByScore(ScoreRange{Min: float64(r.Min), Max: float64(r.Max), MaxExclusive: true}, 0, -1)
+			for _, c := range candidates {
+				plon, plat := geo.Decode(uint64(c.Score))
+				dist := geo.Distance(lon, lat, plon, plat)
+
+				if q.Width > 0 {
+					if !geo.InBox(plon, plat, lon, lat, q.Width, q.Height) {
+						continue
+					}
+				} else if dist > q.Radius {
+					continue
+				}
+
+				out = append(out, GeoMatch{GeoPoint: GeoPoint{Member: c.Member, Lon: plon, Lat: plat}, Dist: dist})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if q.Desc {
+			a, b = b, a
+		}
+		if a.Dist != b.Dist {
+			return a.Dist < b.Dist
+		}
+		return a.Member < b.Member
+	})
+
+	if q.Count > 0 && len(out) > q.Count {
+		out = out[:q.Count]
+	}
+	if out == nil {
+		out = []GeoMatch{}
+	}
+
+	return out, nil
+}
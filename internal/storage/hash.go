@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/taymour/elysiandb/internal/globals"
+)
+
+type hashValue struct {
+	fields map[string][]byte
+}
+
+func init() {
+	registerType(TypeHash, "hash", func(raw json.RawMessage) (typedValue, error) {
+		h := newHash()
+		if err := json.Unmarshal(raw, &h.fields); err != nil {
+			return nil, err
+		}
+		return h, nil
+	})
+}
+
+func newHash() *hashValue {
+	return &hashValue{fields: make(map[string][]byte)}
+}
+
+func (h *hashValue) valueType() ValueType { return TypeHash }
+func (h *hashValue) snapshot() any        { return h.fields }
+
+type HashField struct {
+	Field string
+	Value []byte
+}
+
+func HSet(key string, pairs []HashField) (int, error) {
+	cfg := globals.GetConfig()
+	for _, p := range pairs {
+		if err := checkSizeLimits(cfg, key, p.Value); err != nil {
+			return 0, err
+		}
+	}
+
+	added := 0
+	err := updateTyped(key, TypeHash, newHash, func(h *hashValue) (bool, error) {
+		for _, p := range pairs {
+			if _, ok := h.fields[p.Field]; !ok {
+				added++
+			}
+			buf := make([]byte, len(p.Value))
+			copy(buf, p.Value)
+			h.fields[p.Field] = buf
+		}
+		return len(h.fields) == 0, nil
+	})
+
+	return added, err
+}
+
+func HGet(key string, field string) ([]byte, bool, error) {
+	var out []byte
+	var found bool
+
+	_, err := viewTyped(key, TypeHash, func(h *hashValue) error {
+		v, ok := h.fields[field]
+		if ok {
+			out = append([]byte(nil), v...)
+			found = true
+		}
+		return nil
+	})
+
+	return out, found, err
+}
+
+func HMGet(key string, fields []string) ([][]byte, error) {
+	out := make([][]byte, len(fields))
+
+	_, err := viewTyped(key, TypeHash, func(h *hashValue) error {
+		for i, f := range fields {
+			if v, ok := h.fields[f]; ok {
+				out[i] = append([]byte{}, v...)
+			}
+		}
+		return nil
+	})
+
+	return out, err
+}
+
+func HDel(key string, fields []string) (int, error) {
+	removed := 0
+
+	err := updateTyped(key, TypeHash, nil, func(h *hashValue) (bool, error) {
+		for _, f := range fields {
+			if _, ok := h.fields[f]; ok {
+				delete(h.fields, f)
+				removed++
+			}
+		}
+		if removed == 0 {
+			return false, errUnchanged
+		}
+		return len(h.fields) == 0, nil
+	})
+
+	return removed, err
+}
+
+func HGetAll(key string) ([]HashField, bool, error) {
+	var out []HashField
+
+	found, err := viewTyped(key, TypeHash, func(h *hashValue) error {
+		out = make([]HashField, 0, len(h.fields))
+		for f, v := range h.fields {
+			out = append(out, HashField{Field: f, Value: append([]byte{}, v...)})
+		}
+		return nil
+	})
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Field < out[j].Field })
+
+	return out, found, err
+}
+
+func HIncrBy(key string, field string, delta int64) (int64, error) {
+	var result int64
+
+	err := updateTyped(key, TypeHash, newHash, func(h *hashValue) (bool, error) {
+		var current int64
+		if raw, ok := h.fields[field]; ok {
+			n, err := strconv.ParseInt(string(raw), 10, 64)
+			if err != nil {
+				return false, ErrNotInteger
+			}
+			current = n
+		}
+
+		if (delta > 0 && current > math.MaxInt64-delta) || (delta < 0 && current < math.MinInt64-delta) {
+			return false, ErrOverflow
+		}
+
+		result = current + delta
+		h.fields[field] = []byte(strconv.FormatInt(result, 10))
+
+		return false, nil
+	})
+
+	return result, err
+}
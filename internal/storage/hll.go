@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"encoding/json"
+
+	"github.com/taymour/elysiandb/internal/hll"
+)
+
+type hllValue struct {
+	sketch *hll.Sketch
+}
+
+func init() {
+	registerType(TypeHLL, "hyperloglog", func(raw json.RawMessage) (typedValue, error) {
+		var data []byte
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, err
+		}
+
+		v := newHLL()
+		if err := v.sketch.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+}
+
+func newHLL() *hllValue {
+	return &hllValue{sketch: hll.New()}
+}
+
+func (h *hllValue) valueType() ValueType { return TypeHLL }
+
+func (h *hllValue) snapshot() any {
+	data, _ := h.sketch.MarshalBinary()
+	return data
+}
+
+func PFAdd(key string, elements []string) (bool, error) {
+	changed := false
+
+	err := updateTyped(key, TypeHLL, func() *hllValue {
+		changed = true
+		return newHLL()
+	}, func(h *hllValue) (bool, error) {
+		for _, e := range elements {
+			if h.sketch.Add([]byte(e)) {
+				changed = true
+			}
+		}
+		if !changed {
+			return false, errUnchanged
+		}
+		return false, nil
+	})
+
+	return changed, err
+}
+
+func PFCount(keys []string) (uint64, error) {
+	merged, err := mergedSketch(keys)
+	if err != nil {
+		return 0, err
+	}
+
+	return merged.Count(), nil
+}
+
+func PFMerge(dest string, sources []string) error {
+	merged, err := mergedSketch(sources)
+	if err != nil {
+		return err
+	}
+
+	return updateTyped(dest, TypeHLL, newHLL, func(h *hllValue) (bool, error) {
+		h.sketch.Merge(merged)
+		return false, nil
+	})
+}
+
+func mergedSketch(keys []string) (*hll.Sketch, error) {
+	merged := hll.New()
+
+	for _, k := range keys {
+		_, err := viewTyped(k, TypeHLL, func(h *hllValue) error {
+			merged.Merge(h.sketch)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,523 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/jsondoc"
+	"github.com/taymour/elysiandb/internal/log"
+	"github.com/taymour/elysiandb/internal/skiplist"
+)
+
+const IndexDataFile = "elysiandb.indexes.json"
+
+var (
+	ErrInvalidIndex = errors.New("index needs a name, a key pattern and a JSON path")
+	ErrIndexExists  = errors.New("index already exists")
+	ErrNoSuchIndex  = errors.New("no such index")
+
+	ErrInvalidIndexQuery = errors.New("index query type must be string or number, and number bounds must be numeric")
+)
+
+// Index query types. IndexAny reads each bound as a JSON literal, so 42 is a
+// number and "42" a string; the other two take the bound as given.
+const (
+	IndexAny    = ""
+	IndexString = "string"
+	IndexNumber = "number"
+)
+
+type IndexDefinition struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+	Path    string `json:"path"`
+}
+
+type IndexQuery struct {
+	Min, Max                   string
+	HasMin, HasMax             bool
+	MinExclusive, MaxExclusive bool
+	Type                       string
+	Limit                      int
+}
+
+type IndexMatch struct {
+	Key   string
+	Value json.RawMessage
+}
+
+type indexValue struct {
+	num   float64
+	str   string
+	isNum bool
+}
+
+// secondaryIndex keeps numbers and strings in separate skip lists, numbers
+// first. Number entries are scored by value with the key as member; string
+// entries share one score and order on strMember(value, key).
+type secondaryIndex struct {
+	def   IndexDefinition
+	path  jsondoc.Path
+	mu    sync.RWMutex
+	byKey map[string]indexValue
+	nums  *skiplist.List
+	strs  *skiplist.List
+}
+
+type indexRegistry struct {
+	mu         sync.RWMutex
+	indexes    map[string]*secondaryIndex
+	configured map[string]bool
+	saved      atomic.Bool
+}
+
+func ParseIndexDefinition(spec string) (IndexDefinition, error) {
+	parts := strings.Fields(spec)
+	if len(parts) != 3 {
+		return IndexDefinition{}, ErrInvalidIndex
+	}
+
+	return IndexDefinition{Name: parts[0], Pattern: parts[1], Path: parts[2]}, nil
+}
+
+func CreateIndex(def IndexDefinition) error {
+	return mainStore.indexes.create(mainStore, def, false)
+}
+
+func DropIndex(name string) bool {
+	return mainStore.indexes.drop(name)
+}
+
+func Indexes() []IndexDefinition {
+	return mainStore.indexes.definitions()
+}
+
+func QueryIndex(name string, q IndexQuery) ([]IndexMatch, error) {
+	idx, ok := mainStore.indexes.get(name)
+	if !ok {
+		return nil, ErrNoSuchIndex
+	}
+
+	var lower, upper indexValue
+	var err error
+	if q.HasMin {
+		if lower, err = parseIndexValue(q.Min, q.Type); err != nil {
+			return nil, err
+		}
+	}
+	if q.HasMax {
+		if upper, err = parseIndexValue(q.Max, q.Type); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := idx.scan(q, lower, upper)
+
+	// The limit counts live matches, so it is applied after expired or
+	// deleted keys have been dropped.
+	out := make([]IndexMatch, 0, len(keys))
+	for _, k := range keys {
+		if q.Limit > 0 && len(out) == q.Limit {
+			break
+		}
+		value, err := indexedValue(k)
+		if err != nil || !json.Valid(value) {
+			continue
+		}
+		out = append(out, IndexMatch{Key: k, Value: value})
+	}
+
+	return out, nil
+}
+
+func indexedValue(key string) ([]byte, error) {
+	value, err := GetByKey(key)
+	if errors.Is(err, ErrWrongType) {
+		doc, found, err := GetDocument(key, "$")
+		if err == nil && !found {
+			err = jsondoc.ErrPathNotFound
+		}
+		return doc, err
+	}
+
+	return value, err
+}
+
+func newIndexRegistry() *indexRegistry {
+	r := &indexRegistry{
+		indexes:    make(map[string]*secondaryIndex),
+		configured: make(map[string]bool),
+	}
+	r.saved.Store(true)
+
+	return r
+}
+
+func loadIndexes(cfg *configuration.Config, fileName string, s *Store) *indexRegistry {
+	r := newIndexRegistry()
+
+	for _, spec := range cfg.Store.Indexes {
+		def, err := ParseIndexDefinition(spec)
+		if err == nil {
+			err = r.create(s, def, true)
+		}
+		if err != nil {
+			log.Fatal("Error loading configured index "+spec+":", err)
+		}
+	}
+
+	byteValue, stale, err := readFile(fileName)
+	if err != nil {
+		log.Fatal("Error loading indexes:", err)
+	}
+
+	persisted := make(map[string]IndexDefinition)
+	if len(byteValue) > 0 {
+		if err := json.Unmarshal(byteValue, &persisted); err != nil {
+			log.Fatal("Error loading indexes:", err)
+		}
+	}
+
+	for _, def := range persisted {
+		if r.configured[def.Name] {
+			continue
+		}
+		if err := r.create(s, def, false); err != nil {
+			log.Error("Skipping index "+def.Name+":", err)
+		}
+	}
+
+	r.saved.Store(!stale)
+
+	return r
+}
+
+func writeIndexesToFile(cfg *configuration.Config, fileName string, r *indexRegistry) (int, error) {
+	if r.saved.Swap(true) {
+		return 0, nil
+	}
+
+	r.mu.RLock()
+	defs := make(map[string]IndexDefinition, len(r.indexes))
+	for name, idx := range r.indexes {
+		if !r.configured[name] {
+			defs[name] = idx.def
+		}
+	}
+	r.mu.RUnlock()
+
+	n, err := writeJSONFile(cfg.Store.Folder+"/"+fileName, defs)
+	if err != nil {
+		r.saved.Store(false)
+	}
+
+	return n, err
+}
+
+func (r *indexRegistry) create(s *Store, def IndexDefinition, configured bool) error {
+	if def.Name == "" || def.Pattern == "" || def.Path == "" {
+		return ErrInvalidIndex
+	}
+
+	path, err := jsondoc.ParsePath(def.Path)
+	if err != nil {
+		return err
+	}
+
+	idx := &secondaryIndex{def: def, path: path, byKey: make(map[string]indexValue), nums: skiplist.New(), strs: skiplist.New()}
+
+	r.mu.Lock()
+	if _, ok := r.indexes[def.Name]; ok {
+		r.mu.Unlock()
+		return ErrIndexExists
+	}
+	r.indexes[def.Name] = idx
+	r.configured[def.Name] = configured
+	r.saved.Store(false)
+	r.mu.Unlock()
+
+	idx.backfill(s)
+
+	return nil
+}
+
+func (r *indexRegistry) drop(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.indexes[name]; !ok {
+		return false
+	}
+
+	delete(r.indexes, name)
+	delete(r.configured, name)
+	r.saved.Store(false)
+
+	return true
+}
+
+func (r *indexRegistry) get(name string) (*secondaryIndex, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	idx, ok := r.indexes[name]
+	return idx, ok
+}
+
+func (r *indexRegistry) definitions() []IndexDefinition {
+	r.mu.RLock()
+	out := make([]IndexDefinition, 0, len(r.indexes))
+	for _, idx := range r.indexes {
+		out = append(out, idx.def)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+
+	return out
+}
+
+func (r *indexRegistry) observe(key string, doc func() (any, bool)) {
+	if r == nil {
+		return
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var root any
+	decoded, ok := false, false
+
+	for _, idx := range r.indexes {
+		if !matchGlob(idx.def.Pattern, key) {
+			continue
+		}
+		if !decoded {
+			root, ok = doc()
+			decoded = true
+		}
+
+		idx.update(key, root, ok)
+	}
+}
+
+func (r *indexRegistry) forget(key string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.RLock()
+	for _, idx := range r.indexes {
+		if matchGlob(idx.def.Pattern, key) {
+			idx.update(key, nil, false)
+		}
+	}
+	r.mu.RUnlock()
+}
+
+func (r *indexRegistry) clear() {
+	if r == nil {
+		return
+	}
+
+	r.mu.RLock()
+	for _, idx := range r.indexes {
+		idx.mu.Lock()
+		idx.byKey = make(map[string]indexValue)
+		idx.nums, idx.strs = skiplist.New(), skiplist.New()
+		idx.mu.Unlock()
+	}
+	r.mu.RUnlock()
+}
+
+func (idx *secondaryIndex) backfill(s *Store) {
+	for i := 0; i < s.shardCount; i++ {
+		sh := s.shards[i]
+		sh.mu.RLock()
+		for k, v := range sh.m {
+			if matchGlob(idx.def.Pattern, k) {
+				root, err := jsondoc.Decode(v)
+				idx.update(k, root, err == nil)
+			}
+		}
+		for k, v := range sh.typed {
+			if d, ok := v.(*docValue); ok && matchGlob(idx.def.Pattern, k) {
+				idx.update(k, d.root, true)
+			}
+		}
+		sh.mu.RUnlock()
+	}
+}
+
+func (idx *secondaryIndex) update(key string, root any, ok bool) {
+	var value indexValue
+	if ok {
+		node, found := jsondoc.Get(root, idx.path)
+		if ok = found; ok {
+			value, ok = toIndexValue(node)
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if old, indexed := idx.byKey[key]; indexed {
+		if ok && compareIndexValues(old, value) == 0 {
+			return
+		}
+		if old.isNum {
+			idx.nums.Delete(key, old.num)
+		} else {
+			idx.strs.Delete(strMember(old.str, key), 0)
+		}
+		delete(idx.byKey, key)
+	}
+
+	if !ok {
+		return
+	}
+
+	if value.isNum {
+		idx.nums.Insert(key, value.num)
+	} else {
+		idx.strs.Insert(strMember(value.str, key), 0)
+	}
+	idx.byKey[key] = value
+}
+
+func (idx *secondaryIndex) scan(q IndexQuery, lower, upper indexValue) []string {
+	if q.HasMin && q.HasMax && lower.isNum != upper.isNum {
+		return nil
+	}
+
+	isNum := lower.isNum
+	if !q.HasMin {
+		isNum = upper.isNum
+	}
+	bounded := q.HasMin || q.HasMax
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	keys := make([]string, 0)
+
+	if !bounded || isNum {
+		from := math.Inf(-1)
+		if q.HasMin {
+			from = lower.num
+		}
+		idx.nums.Ascend(from, "", func(e skiplist.Element) bool {
+			if q.HasMin && q.MinExclusive && e.Score == lower.num {
+				return true
+			}
+			if q.HasMax && (e.Score > upper.num || (e.Score == upper.num && q.MaxExclusive)) {
+				return false
+			}
+			keys = append(keys, e.Member)
+			return true
+		})
+	}
+
+	if !bounded || !isNum {
+		from := ""
+		if q.HasMin {
+			from = escapeIndexString(lower.str)
+		}
+		idx.strs.Ascend(0, from, func(e skiplist.Element) bool {
+			value, key := splitStrMember(e.Member)
+			if q.HasMin && q.MinExclusive && value == lower.str {
+				return true
+			}
+			if q.HasMax {
+				if c := strings.Compare(value, upper.str); c > 0 || (c == 0 && q.MaxExclusive) {
+					return false
+				}
+			}
+			keys = append(keys, key)
+			return true
+		})
+	}
+
+	return keys
+}
+
+// strMember orders string entries by value and then key. NUL bytes in the
+// value are escaped to NUL 0x01 so the NUL NUL separator sorts below any
+// continuation of the value.
+func strMember(value string, key string) string {
+	return escapeIndexString(value) + "\x00\x00" + key
+}
+
+func escapeIndexString(s string) string {
+	return strings.ReplaceAll(s, "\x00", "\x00\x01")
+}
+
+func splitStrMember(member string) (string, string) {
+	i := strings.Index(member, "\x00\x00")
+	return strings.ReplaceAll(member[:i], "\x00\x01", "\x00"), member[i+2:]
+}
+
+func toIndexValue(node any) (indexValue, bool) {
+	switch v := node.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return indexValue{num: f, isNum: true}, err == nil
+	case string:
+		return indexValue{str: v}, true
+	case bool:
+		return indexValue{str: strconv.FormatBool(v)}, true
+	}
+
+	return indexValue{}, false
+}
+
+func parseIndexValue(raw string, typ string) (indexValue, error) {
+	switch typ {
+	case IndexString:
+		return indexValue{str: raw}, nil
+	case IndexNumber:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return indexValue{}, ErrInvalidIndexQuery
+		}
+		return indexValue{num: f, isNum: true}, nil
+	case IndexAny:
+	default:
+		return indexValue{}, ErrInvalidIndexQuery
+	}
+
+	if v, err := jsondoc.Decode([]byte(raw)); err == nil {
+		if value, ok := toIndexValue(v); ok {
+			return value, nil
+		}
+	}
+
+	return indexValue{str: raw}, nil
+}
+
+func compareIndexValues(a, b indexValue) int {
+	switch {
+	case a.isNum != b.isNum:
+		if a.isNum {
+			return -1
+		}
+		return 1
+	case a.isNum:
+		if a.num < b.num {
+			return -1
+		}
+		if a.num > b.num {
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(a.str, b.str)
+}
@@ -1,17 +1,24 @@
 package storage
 
 import (
+	"errors"
 	"fmt"
 	"maps"
 	"os"
 	"sync"
 	"time"
 
+	"github.com/taymour/elysiandb/internal/configuration"
 	"github.com/taymour/elysiandb/internal/globals"
 	"github.com/taymour/elysiandb/internal/log"
 	"github.com/taymour/elysiandb/internal/stat"
 )
 
+var (
+	ErrKeyTooLarge   = errors.New("key exceeds store.maxKeyBytes")
+	ErrValueTooLarge = errors.New("value exceeds store.maxValueBytes")
+)
+
 var mainStore *Store
 var expirationContainer *ExpirationContainer
 var rootMu sync.RWMutex
@@ -22,40 +29,67 @@ func LoadDB() {
 	createFolder(cfg.Store.Folder)
 	createFile(cfg.Store.Folder, DataFile)
 	createFile(cfg.Store.Folder, ExpirationDataFile)
+	createFile(cfg.Store.Folder, TypedDataFile)
+	createFile(cfg.Store.Folder, IndexDataFile)
+	createFile(cfg.Store.Folder, FencingDataFile)
+	createFile(cfg.Store.Folder, TagDataFile)
+	createFile(cfg.Store.Folder, SoftTTLDataFile)
 
 	ms := createStore(DataFile)
+	ms.indexes = loadIndexes(cfg, IndexDataFile, ms)
+	ms.tags = loadTags(TagDataFile, ms)
+	ms.soft = loadSoftTTLs(SoftTTLDataFile, ms)
+	loadFencing(FencingDataFile)
 	ec := createExpirationContainer(ExpirationDataFile)
 
+	ms.expired = ec.expired
+
 	rootMu.Lock()
 	mainStore = ms
 	expirationContainer = ec
 	rootMu.Unlock()
 
+	resetBlockedPops()
+	resetLeases()
 	CleanAllPastKeys()
 
 	if cfg.Stats.Enabled {
-		stat.Stats.SetKeysCount(mainStore.CountTotalKeys())
-		stat.Stats.SetExpirationKeysCount(expirationContainer.CountTotalKeys())
+		SyncStats()
 	}
 }
 
+func SyncStats() {
+	rootMu.RLock()
+	ms, ec := mainStore, expirationContainer
+	rootMu.RUnlock()
+
+	stat.Stats.SetKeysCount(ms.CountTotalKeys())
+	stat.Stats.SetExpirationKeysCount(ec.CountTotalKeys())
+}
+
 func createExpirationContainer(fileName string) *ExpirationContainer {
 	container := newExpirationContainer()
 
-	data, err := ReadExpirationsFromDB(fileName)
+	data, stale, err := readExpirationsFile(fileName)
 	if err != nil {
 		log.Fatal("Error loading expiration database:", err)
 	}
 
 	for ts, keys := range data {
+		if ts < legacySecondsThreshold {
+			ts *= 1000
+			stale = true
+		}
 		container.put(ts, keys)
 	}
 
+	container.saved.Store(!stale)
+
 	return container
 }
 
 func createStore(file string) *Store {
-	data, err := ReadFromDB(file)
+	data, stale, err := readStoreFile(file)
 	if err != nil {
 		log.Fatal("Error loading database:", err)
 	}
@@ -63,9 +97,17 @@ func createStore(file string) *Store {
 	bytesData := make(map[string][]byte, len(data))
 	maps.Copy(bytesData, data)
 
+	typed, typedStale, err := readTypedFile(TypedDataFile)
+	if err != nil {
+		log.Fatal("Error loading typed values:", err)
+	}
+
 	newStore := NewStore()
 	newStore.FromMap(bytesData)
-	newStore.saved.Store(true)
+	if err := newStore.typedFromMap(typed); err != nil {
+		log.Fatal("Error loading typed values:", err)
+	}
+	newStore.saved.Store(!stale && !typedStale)
 
 	return newStore
 }
@@ -89,9 +131,17 @@ func createFile(folder string, file string) {
 }
 
 func GetByKey(key string) ([]byte, error) {
+	if KeyHasExpired(key) {
+		expireKey(key)
+		return nil, fmt.Errorf("key not found: %s", key)
+	}
+
 	if val, ok := mainStore.get(key); ok {
 		return val, nil
 	}
+	if mainStore.has(key) {
+		return nil, ErrWrongType
+	}
 	return nil, fmt.Errorf("key not found: %s", key)
 }
 
@@ -112,11 +162,6 @@ func GetByWildcardKey(pattern string) map[string][]byte {
 	}
 
 	for _, k := range keys {
-		if KeyHasExpired(k) {
-			DeleteByKey(k)
-			continue
-		}
-
 		value, err := GetByKey(k)
 		if err != nil {
 			continue
@@ -132,11 +177,11 @@ func DeleteByWildcardKey(pattern string) int {
 	keys := make([]string, 0)
 
 	if isBareStar(pattern) {
-		mainStore.Iterate(func(k string, v []byte) {
+		mainStore.IterateKeys(func(k string) {
 			keys = append(keys, k)
 		})
 	} else {
-		mainStore.Iterate(func(k string, v []byte) {
+		mainStore.IterateKeys(func(k string) {
 			if matchGlob(pattern, k) {
 				keys = append(keys, k)
 			}
@@ -155,35 +200,34 @@ func PutKeyValue(key string, value []byte) error {
 }
 
 func PutKeyValueWithTTL(key string, value []byte, ttl int) error {
-	cfg := globals.GetConfig()
-	_, existed := mainStore.get(key)
-	hadTTL := hasTTL(key)
-
-	mainStore.put(key, value)
-
-	if ttl > 0 {
-		expiration := time.Now().Unix() + int64(ttl)
-		expirationContainer.put(expiration, []string{key})
-		if cfg.Stats.Enabled && !hadTTL {
-			stat.Stats.IncrementExpirationKeysCount()
-		}
-	}
-
-	if cfg.Stats.Enabled && !existed {
-		stat.Stats.IncrementKeysCount()
-	}
+	return PutKeyValueWithTTLDuration(key, value, time.Duration(ttl)*time.Second)
+}
 
-	return nil
+func PutKeyValueWithTTLDuration(key string, value []byte, ttl time.Duration) error {
+	return PutKeyValueWithOptions(key, value, PutOptions{TTL: ttl})
 }
 
 func DeleteByKey(key string) {
+	deleteKey(key, false)
+}
+
+// deleteKey drops the value and its expiry under the shard lock. Lazy
+// expiry passes onlyIfExpired so that the deadline is checked again there:
+// a key that was rewritten after the caller saw it expire is left alone.
+func deleteKey(key string, onlyIfExpired bool) bool {
 	cfg := globals.GetConfig()
 
-	_, existed := mainStore.get(key)
-	hadTTL := hasTTL(key)
+	sh := mainStore.shards[mainStore.shardIndex(key)]
+	sh.mu.Lock()
+	if onlyIfExpired && !KeyHasExpired(key) {
+		sh.mu.Unlock()
+		return false
+	}
+	existed := mainStore.delLocked(sh, key)
+	hadTTL := expirationContainer.del(key)
+	sh.mu.Unlock()
 
-	mainStore.del(key)
-	expirationContainer.del(key)
+	notifyKey(key)
 
 	if cfg.Stats.Enabled {
 		if existed {
@@ -193,6 +237,8 @@ func DeleteByKey(key string) {
 			stat.Stats.DecrementExpirationKeysCount()
 		}
 	}
+
+	return true
 }
 
 func ResetStore() {
@@ -207,53 +253,65 @@ func ResetStore() {
 	}
 }
 
-func CleanExpiratedKeys(index int64) {
-	expirationContainer.mu.RLock()
-	bucket, ok := expirationContainer.Buckets[index]
-	if !ok {
-		expirationContainer.mu.RUnlock()
-		return
-	}
-	expirationContainer.mu.RUnlock()
+func ExpireDueKeys(max int) int {
+	rootMu.RLock()
+	ec := expirationContainer
+	rootMu.RUnlock()
+
+	keys := ec.due(time.Now(), max)
+
+	now := time.Now().UnixMilli()
+	expired := 0
+	for _, k := range keys {
+		ts, ok := expiresAt(k)
+		if !ok {
+			continue
+		}
+
+		if ts > now {
+			ec.mu.Lock()
+			if cur, ok := ec.index[k]; ok && cur == ts {
+				ec.wheel.Schedule(k, wallDeadline(ts))
+			}
+			ec.mu.Unlock()
+			continue
+		}
 
-	bucket.mu.RLock()
-	snapshot := make([]string, len(bucket.Keys))
-	copy(snapshot, bucket.Keys)
-	bucket.mu.RUnlock()
+		if deleteKey(k, true) {
+			expired++
+		}
+	}
 
-	for _, v := range snapshot {
-		DeleteByKey(v)
+	if expired > 0 && globals.GetConfig().Stats.Enabled {
+		stat.Stats.AddExpiredKeys(expired)
 	}
 
-	expirationContainer.mu.Lock()
-	delete(expirationContainer.Buckets, index)
-	expirationContainer.mu.Unlock()
+	return len(keys)
 }
 
 func KeyHasExpired(key string) bool {
-	expirationContainer.mu.RLock()
-	expTs, ok := expirationContainer.index[key]
-	expirationContainer.mu.RUnlock()
+	expTs, ok := expiresAt(key)
 	if !ok {
 		return false
 	}
-	return time.Now().Unix() >= expTs
+	return time.Now().UnixMilli() >= expTs
 }
 
 func CleanAllPastKeys() {
-	expirationContainer.mu.RLock()
-	bucketKeys := make([]int64, 0, len(expirationContainer.Buckets))
-	for k := range expirationContainer.Buckets {
-		bucketKeys = append(bucketKeys, k)
+	for ExpireDueKeys(expirationBatchSize) == expirationBatchSize {
 	}
-	expirationContainer.mu.RUnlock()
+}
 
-	now := time.Now().Unix()
-	for _, k := range bucketKeys {
-		if k < now {
-			CleanExpiratedKeys(k)
-		}
+func checkSizeLimits(cfg *configuration.Config, key string, value []byte) error {
+	if cfg.Store.MaxKeyBytes > 0 && len(key) > cfg.Store.MaxKeyBytes {
+		return ErrKeyTooLarge
 	}
+
+	if cfg.Store.MaxValueBytes > 0 && len(value) > cfg.Store.MaxValueBytes {
+		return ErrValueTooLarge
+	}
+
+	return nil
 }
 
 func hasTTL(key string) bool {
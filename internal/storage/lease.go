@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrInvalidLease = errors.New("lease token is not valid for this key")
+
+type LeaseResult struct {
+	Value []byte
+	Found bool
+	Stale bool
+	Token string
+}
+
+type lease struct {
+	token string
+	timer *time.Timer
+}
+
+var leases = struct {
+	mu sync.Mutex
+	m  map[string]*lease
+}{m: make(map[string]*lease)}
+
+func GetLease(key string, ttl time.Duration, wait bool, timeout time.Duration, done <-chan struct{}) (LeaseResult, error) {
+	try := func() (LeaseResult, bool, error) {
+		value, stale, err := GetByKeyWithStale(key)
+		if errors.Is(err, ErrWrongType) {
+			return LeaseResult{}, false, err
+		}
+		if err == nil {
+			// A stale value is served to everyone; the first caller also
+			// gets the lease and is expected to refresh it.
+			res := LeaseResult{Value: value, Found: true, Stale: stale}
+			if stale {
+				res.Token, _ = grantLease(key, ttl)
+			}
+			return res, true, nil
+		}
+
+		if token, ok := grantLease(key, ttl); ok {
+			return LeaseResult{Token: token}, true, nil
+		}
+		return LeaseResult{}, false, nil
+	}
+
+	if !wait {
+		res, _, err := try()
+		return res, err
+	}
+
+	res, _, err := waitUntilReady([]string{key}, timeout, done, try)
+	return res, err
+}
+
+func PutKeyValueWithLease(key string, value []byte, opts PutOptions, token string) error {
+	leases.mu.Lock()
+	l, ok := leases.m[key]
+	if !ok || l.token != token {
+		leases.mu.Unlock()
+		return ErrInvalidLease
+	}
+
+	// Fill before dropping the lease so that no caller can miss in between
+	// and start a second computation.
+	err := PutKeyValueWithOptions(key, value, opts)
+	if err == nil {
+		l.timer.Stop()
+		delete(leases.m, key)
+	}
+	leases.mu.Unlock()
+
+	return err
+}
+
+func grantLease(key string, ttl time.Duration) (string, bool) {
+	leases.mu.Lock()
+	defer leases.mu.Unlock()
+
+	if _, held := leases.m[key]; held {
+		return "", false
+	}
+
+	l := &lease{token: randomToken()}
+	l.timer = time.AfterFunc(ttl, func() { expireLease(key, l) })
+	leases.m[key] = l
+
+	return l.token, true
+}
+
+func expireLease(key string, l *lease) {
+	leases.mu.Lock()
+	if leases.m[key] == l {
+		delete(leases.m, key)
+	}
+	leases.mu.Unlock()
+
+	notifyKey(key)
+}
+
+func resetLeases() {
+	leases.mu.Lock()
+	for _, l := range leases.m {
+		l.timer.Stop()
+	}
+	leases.m = make(map[string]*lease)
+	leases.mu.Unlock()
+}
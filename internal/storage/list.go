@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"encoding/json"
+
+	"github.com/taymour/elysiandb/internal/globals"
+)
+
+const listCompactThreshold = 1024
+
+type listValue struct {
+	items [][]byte
+	head  int
+}
+
+func init() {
+	registerType(TypeList, "list", func(raw json.RawMessage) (typedValue, error) {
+		var items [][]byte
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, err
+		}
+		return &listValue{items: items}, nil
+	})
+}
+
+func newList() *listValue {
+	return &listValue{}
+}
+
+func (l *listValue) valueType() ValueType { return TypeList }
+func (l *listValue) snapshot() any        { return l.items[l.head:] }
+
+func (l *listValue) len() int {
+	return len(l.items) - l.head
+}
+
+func (l *listValue) pushBack(v []byte) {
+	l.items = append(l.items, v)
+}
+
+func (l *listValue) pushFront(v []byte) {
+	if l.head == 0 {
+		n := l.len()
+		room := max(n, 8)
+		buf := make([][]byte, room+n, room+n+n/2)
+		copy(buf[room:], l.items)
+		l.items, l.head = buf, room
+	}
+
+	l.head--
+	l.items[l.head] = v
+}
+
+func (l *listValue) popFront() []byte {
+	v := l.items[l.head]
+	l.items[l.head] = nil
+	l.head++
+
+	switch {
+	case l.head == len(l.items):
+		l.items, l.head = l.items[:0], 0
+	case l.head >= listCompactThreshold && l.head*2 >= len(l.items):
+		l.items, l.head = append([][]byte(nil), l.items[l.head:]...), 0
+	}
+
+	return v
+}
+
+func (l *listValue) popBack() []byte {
+	last := len(l.items) - 1
+	v := l.items[last]
+	l.items[last] = nil
+	l.items = l.items[:last]
+
+	if l.head == len(l.items) {
+		l.items, l.head = l.items[:0], 0
+	}
+
+	return v
+}
+
+func (l *listValue) pop(left bool) []byte {
+	if left {
+		return l.popFront()
+	}
+
+	return l.popBack()
+}
+
+func (l *listValue) bounds(start, stop int) (int, int, bool) {
+	n := l.len()
+	if start < 0 {
+		start = max(n+start, 0)
+	}
+	if stop < 0 {
+		stop = n + stop
+	}
+	stop = min(stop, n-1)
+
+	if start > stop || start >= n {
+		return 0, 0, false
+	}
+
+	return start, stop, true
+}
+
+func LPush(key string, values [][]byte) (int, error) {
+	return push(key, values, true)
+}
+
+func RPush(key string, values [][]byte) (int, error) {
+	return push(key, values, false)
+}
+
+func push(key string, values [][]byte, left bool) (int, error) {
+	cfg := globals.GetConfig()
+	for _, v := range values {
+		if err := checkSizeLimits(cfg, key, v); err != nil {
+			return 0, err
+		}
+	}
+
+	length := 0
+	err := updateTyped(key, TypeList, newList, func(l *listValue) (bool, error) {
+		for _, v := range values {
+			buf := append([]byte{}, v...)
+			if left {
+				l.pushFront(buf)
+			} else {
+				l.pushBack(buf)
+			}
+		}
+		length = l.len()
+
+		serveWaiters(key, l)
+
+		return l.len() == 0, nil
+	})
+
+	return length, err
+}
+
+func LPop(key string, count int) ([][]byte, error) {
+	return pop(key, count, true)
+}
+
+func RPop(key string, count int) ([][]byte, error) {
+	return pop(key, count, false)
+}
+
+func pop(key string, count int, left bool) ([][]byte, error) {
+	var out [][]byte
+
+	err := updateTyped(key, TypeList, nil, func(l *listValue) (bool, error) {
+		n := min(count, l.len())
+		out = make([][]byte, 0, n)
+		for i := 0; i < n; i++ {
+			out = append(out, l.pop(left))
+		}
+		return l.len() == 0, nil
+	})
+
+	return out, err
+}
+
+func LRange(key string, start, stop int) ([][]byte, bool, error) {
+	var out [][]byte
+
+	found, err := viewTyped(key, TypeList, func(l *listValue) error {
+		from, to, ok := l.bounds(start, stop)
+		out = make([][]byte, 0)
+		if !ok {
+			return nil
+		}
+		for _, v := range l.items[l.head+from : l.head+to+1] {
+			out = append(out, append([]byte{}, v...))
+		}
+		return nil
+	})
+
+	return out, found, err
+}
+
+func LLen(key string) (int, error) {
+	n := 0
+
+	_, err := viewTyped(key, TypeList, func(l *listValue) error {
+		n = l.len()
+		return nil
+	})
+
+	return n, err
+}
+
+func LTrim(key string, start, stop int) error {
+	return updateTyped(key, TypeList, nil, func(l *listValue) (bool, error) {
+		from, to, ok := l.bounds(start, stop)
+		if !ok {
+			return true, nil
+		}
+
+		l.items = append([][]byte(nil), l.items[l.head+from:l.head+to+1]...)
+		l.head = 0
+
+		return false, nil
+	})
+}
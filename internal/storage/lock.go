@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/log"
+)
+
+const (
+	FencingDataFile = "elysiandb.fencing.json"
+	fenceReserve    = 1000
+)
+
+var ErrNotLockOwner = errors.New("lock is not held by this owner")
+
+type Lock struct {
+	Owner string
+	Fence uint64
+}
+
+type lockValue struct {
+	Owner string `json:"owner"`
+	Fence uint64 `json:"fence"`
+}
+
+type fencingState struct {
+	Reserved uint64 `json:"reserved"`
+}
+
+var fencing struct {
+	mu       sync.Mutex
+	next     uint64
+	reserved uint64
+}
+
+func init() {
+	registerType(TypeLock, "lock", func(raw json.RawMessage) (typedValue, error) {
+		var l lockValue
+		if err := json.Unmarshal(raw, &l); err != nil {
+			return nil, err
+		}
+		return &l, nil
+	})
+}
+
+func (l *lockValue) valueType() ValueType { return TypeLock }
+func (l *lockValue) snapshot() any        { return l }
+
+func AcquireLock(name string, ttl time.Duration, wait bool, timeout time.Duration, done <-chan struct{}) (Lock, bool, error) {
+	if err := checkSizeLimits(globals.GetConfig(), name, nil); err != nil {
+		return Lock{}, false, err
+	}
+
+	if !wait {
+		return tryAcquireLock(name, ttl)
+	}
+
+	return waitUntilReady([]string{name}, timeout, done, func() (Lock, bool, error) {
+		return tryAcquireLock(name, ttl)
+	})
+}
+
+func RenewLock(name string, owner string, ttl time.Duration) (Lock, error) {
+	var lock Lock
+
+	err := updateTyped(name, TypeLock, nil, func(l *lockValue) (bool, error) {
+		if l.Owner != owner {
+			return false, ErrNotLockOwner
+		}
+		lock = Lock{Owner: l.Owner, Fence: l.Fence}
+		// Still under the shard lock, so the lock cannot expire and be taken
+		// by another owner before its TTL is extended.
+		setExpiration(globals.GetConfig(), name, time.Now().Add(ttl).UnixMilli())
+		return false, errUnchanged
+	})
+	if err != nil {
+		return Lock{}, err
+	}
+	if lock.Owner == "" {
+		return Lock{}, ErrNotLockOwner
+	}
+
+	return lock, nil
+}
+
+func ReleaseLock(name string, owner string) error {
+	released := false
+
+	err := updateTyped(name, TypeLock, nil, func(l *lockValue) (bool, error) {
+		if l.Owner != owner {
+			return false, ErrNotLockOwner
+		}
+		released = true
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	if !released {
+		return ErrNotLockOwner
+	}
+
+	notifyKey(name)
+
+	return nil
+}
+
+func tryAcquireLock(name string, ttl time.Duration) (Lock, bool, error) {
+	var lock Lock
+	acquired := false
+
+	err := updateTyped(name, TypeLock, func() *lockValue {
+		return &lockValue{}
+	}, func(l *lockValue) (bool, error) {
+		if l.Owner != "" {
+			return false, errUnchanged
+		}
+
+		fence, err := nextFence()
+		if err != nil {
+			return false, err
+		}
+
+		l.Owner, l.Fence = randomToken(), fence
+		lock, acquired = Lock{Owner: l.Owner, Fence: l.Fence}, true
+		setExpiration(globals.GetConfig(), name, time.Now().Add(ttl).UnixMilli())
+
+		return false, nil
+	})
+	if err != nil || !acquired {
+		return Lock{}, false, err
+	}
+
+	return lock, true, nil
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+func nextFence() (uint64, error) {
+	fencing.mu.Lock()
+	defer fencing.mu.Unlock()
+
+	if fencing.next >= fencing.reserved {
+		reserved := fencing.next + fenceReserve
+		path := globals.GetConfig().Store.Folder + "/" + FencingDataFile
+		if _, err := writeJSONFile(path, fencingState{Reserved: reserved}); err != nil {
+			return 0, err
+		}
+		fencing.reserved = reserved
+	}
+
+	fencing.next++
+
+	return fencing.next, nil
+}
+
+func loadFencing(fileName string) {
+	byteValue, stale, err := readFile(fileName)
+	if err != nil {
+		log.Fatal("Error loading fencing counter:", err)
+	}
+
+	var state fencingState
+	if len(byteValue) > 0 {
+		if err := json.Unmarshal(byteValue, &state); err != nil {
+			log.Fatal("Error loading fencing counter:", err)
+		}
+	}
+
+	// The reservation is only rewritten every fenceReserve locks, so re-seal
+	// it now rather than leave it under a key that may be dropped.
+	if stale {
+		path := globals.GetConfig().Store.Folder + "/" + fileName
+		if _, err := writeJSONFile(path, state); err != nil {
+			log.Fatal("Error writing fencing counter:", err)
+		}
+	}
+
+	fencing.mu.Lock()
+	fencing.next, fencing.reserved = state.Reserved, state.Reserved
+	fencing.mu.Unlock()
+}
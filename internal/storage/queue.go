@@ -0,0 +1,322 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/timingwheel"
+)
+
+const defaultMaxAttempts = 5
+
+var (
+	ErrNoSuchJob       = errors.New("no such reserved job")
+	ErrInvalidQueueArg = errors.New("visibility timeout and max attempts must be positive")
+)
+
+type Job struct {
+	ID          string
+	Payload     []byte
+	Attempts    int
+	MaxAttempts int
+}
+
+type QueueStats struct {
+	Ready    int
+	Delayed  int
+	Reserved int
+	Dead     int
+}
+
+type queueJob struct {
+	Job
+	runAt    int64
+	reserved bool
+}
+
+// queueValue tracks when each live job is next due (its run-at time, or the
+// end of its visibility timeout while reserved) on a timing wheel, the same
+// structure the expiration container uses for key TTLs. Jobs that come due
+// are moved to ready in the order the wheel hands them back.
+type queueValue struct {
+	seq   uint64
+	jobs  map[string]*queueJob
+	wheel *timingwheel.Wheel[string]
+	ready []string
+	dead  []*queueJob
+}
+
+type queueSnapshot struct {
+	Seq  uint64        `json:"seq"`
+	Jobs []jobSnapshot `json:"jobs"`
+	Dead []jobSnapshot `json:"dead,omitempty"`
+}
+
+type jobSnapshot struct {
+	ID          string `json:"id"`
+	Payload     []byte `json:"payload"`
+	Attempts    int    `json:"attempts"`
+	MaxAttempts int    `json:"max_attempts"`
+	RunAt       int64  `json:"run_at,omitempty"`
+	Reserved    bool   `json:"reserved,omitempty"`
+}
+
+func init() {
+	registerType(TypeQueue, "queue", func(raw json.RawMessage) (typedValue, error) {
+		var snap queueSnapshot
+		if err := json.Unmarshal(raw, &snap); err != nil {
+			return nil, err
+		}
+
+		q := newQueue()
+		q.seq = snap.Seq
+		for _, js := range snap.Jobs {
+			q.schedule(&queueJob{Job: js.job(), runAt: js.RunAt, reserved: js.Reserved})
+		}
+		for _, js := range snap.Dead {
+			q.dead = append(q.dead, &queueJob{Job: js.job()})
+		}
+		return q, nil
+	})
+}
+
+func newQueue() *queueValue {
+	return &queueValue{
+		jobs:  make(map[string]*queueJob),
+		wheel: timingwheel.New[string](expirationTick, time.Now()),
+	}
+}
+
+func (q *queueValue) valueType() ValueType { return TypeQueue }
+
+func (q *queueValue) snapshot() any {
+	snap := queueSnapshot{Seq: q.seq, Jobs: make([]jobSnapshot, 0, len(q.jobs))}
+
+	for _, j := range q.jobs {
+		snap.Jobs = append(snap.Jobs, jobSnapshot{
+			ID: j.ID, Payload: j.Payload, Attempts: j.Attempts, MaxAttempts: j.MaxAttempts,
+			RunAt: j.runAt, Reserved: j.reserved,
+		})
+	}
+	// Restoring in due order keeps ready jobs roughly first-in first-out.
+	sort.Slice(snap.Jobs, func(i, k int) bool {
+		a, b := snap.Jobs[i], snap.Jobs[k]
+		if a.RunAt != b.RunAt {
+			return a.RunAt < b.RunAt
+		}
+		return len(a.ID) < len(b.ID) || (len(a.ID) == len(b.ID) && a.ID < b.ID)
+	})
+
+	for _, j := range q.dead {
+		snap.Dead = append(snap.Dead, jobSnapshot{ID: j.ID, Payload: j.Payload, Attempts: j.Attempts, MaxAttempts: j.MaxAttempts})
+	}
+
+	return snap
+}
+
+func (js jobSnapshot) job() Job {
+	return Job{ID: js.ID, Payload: js.Payload, Attempts: js.Attempts, MaxAttempts: js.MaxAttempts}
+}
+
+func (q *queueValue) schedule(j *queueJob) {
+	q.jobs[j.ID] = j
+
+	// The wheel rounds deadlines up to the next tick, so jobs that are
+	// already due skip it.
+	if j.runAt <= time.Now().UnixMilli() {
+		q.wheel.Remove(j.ID)
+		q.ready = append(q.ready, j.ID)
+		return
+	}
+
+	q.wheel.Schedule(j.ID, wallDeadline(j.runAt))
+}
+
+func (q *queueValue) advance(now time.Time) {
+	q.ready = append(q.ready, q.wheel.Advance(now, 0)...)
+}
+
+func (q *queueValue) kill(j *queueJob) {
+	q.wheel.Remove(j.ID)
+	delete(q.jobs, j.ID)
+	j.reserved = false
+	q.dead = append(q.dead, j)
+}
+
+// reservedJob returns the job only while its visibility timeout is running;
+// once it lapses the job is up for redelivery and the old worker lost it.
+func (q *queueValue) reservedJob(id string, now int64) (*queueJob, error) {
+	j, ok := q.jobs[id]
+	if !ok || !j.reserved || j.runAt <= now {
+		return nil, ErrNoSuchJob
+	}
+
+	return j, nil
+}
+
+func Enqueue(queue string, payload []byte, runAt time.Time, maxAttempts int) (string, error) {
+	if maxAttempts < 0 {
+		return "", ErrInvalidQueueArg
+	}
+	if maxAttempts == 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	if err := checkSizeLimits(globals.GetConfig(), queue, payload); err != nil {
+		return "", err
+	}
+
+	var id string
+	err := updateTyped(queue, TypeQueue, newQueue, func(q *queueValue) (bool, error) {
+		q.seq++
+		id = strconv.FormatUint(q.seq, 10)
+		q.schedule(&queueJob{
+			Job:   Job{ID: id, Payload: append([]byte(nil), payload...), MaxAttempts: maxAttempts},
+			runAt: runAt.UnixMilli(),
+		})
+		return false, nil
+	})
+
+	return id, err
+}
+
+func Reserve(queue string, visibility time.Duration) (Job, bool, error) {
+	if visibility <= 0 {
+		return Job{}, false, ErrInvalidQueueArg
+	}
+
+	var job Job
+	reserved, killed := false, false
+
+	err := updateTyped(queue, TypeQueue, nil, func(q *queueValue) (bool, error) {
+		now := time.Now()
+		q.advance(now)
+
+		for len(q.ready) > 0 && !reserved {
+			j, ok := q.jobs[q.ready[0]]
+			q.ready = q.ready[1:]
+			if !ok {
+				continue
+			}
+			if j.Attempts >= j.MaxAttempts {
+				q.kill(j)
+				killed = true
+				continue
+			}
+
+			j.Attempts++
+			j.reserved = true
+			j.runAt = now.Add(visibility).UnixMilli()
+			q.schedule(j)
+
+			job, reserved = j.Job, true
+		}
+		if !reserved && !killed {
+			return false, errUnchanged
+		}
+		return false, nil
+	})
+
+	return job, reserved, err
+}
+
+func Ack(queue string, id string) error {
+	return updateQueueJob(queue, id, func(q *queueValue, j *queueJob, now int64) {
+		q.wheel.Remove(id)
+		delete(q.jobs, id)
+	})
+}
+
+func Nack(queue string, id string, delay time.Duration) error {
+	return updateQueueJob(queue, id, func(q *queueValue, j *queueJob, now int64) {
+		if j.Attempts >= j.MaxAttempts {
+			q.kill(j)
+			return
+		}
+
+		j.reserved = false
+		j.runAt = now + delay.Milliseconds()
+		q.schedule(j)
+	})
+}
+
+func Touch(queue string, id string, visibility time.Duration) error {
+	if visibility <= 0 {
+		return ErrInvalidQueueArg
+	}
+
+	return updateQueueJob(queue, id, func(q *queueValue, j *queueJob, now int64) {
+		j.runAt = now + visibility.Milliseconds()
+		q.schedule(j)
+	})
+}
+
+func updateQueueJob(queue string, id string, fn func(q *queueValue, j *queueJob, now int64)) error {
+	found := false
+
+	err := updateTyped(queue, TypeQueue, nil, func(q *queueValue) (bool, error) {
+		now := time.Now().UnixMilli()
+
+		j, err := q.reservedJob(id, now)
+		if err != nil {
+			return false, err
+		}
+
+		fn(q, j, now)
+		found = true
+		return false, nil
+	})
+	if err == nil && !found {
+		err = ErrNoSuchJob
+	}
+
+	return err
+}
+
+func QueueInfo(queue string) (QueueStats, error) {
+	var stats QueueStats
+
+	err := updateTyped(queue, TypeQueue, nil, func(q *queueValue) (bool, error) {
+		now := time.Now()
+		q.advance(now)
+
+		due := make(map[string]bool, len(q.ready))
+		for _, id := range q.ready {
+			due[id] = true
+		}
+
+		for id, j := range q.jobs {
+			switch {
+			case due[id]:
+				stats.Ready++
+			case j.reserved:
+				stats.Reserved++
+			default:
+				stats.Delayed++
+			}
+		}
+		stats.Dead = len(q.dead)
+
+		// Moving due jobs to ready does not change what is persisted.
+		return false, errUnchanged
+	})
+
+	return stats, err
+}
+
+func DeadJobs(queue string) ([]Job, error) {
+	var out []Job
+
+	_, err := viewTyped(queue, TypeQueue, func(q *queueValue) error {
+		out = make([]Job, len(q.dead))
+		for i, j := range q.dead {
+			out[i] = j.Job
+		}
+		return nil
+	})
+
+	return out, err
+}
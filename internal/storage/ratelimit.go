@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/globals"
+)
+
+var ErrInvalidRateLimit = errors.New("capacity and refill rate must be positive and cost must not exceed capacity")
+
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int64
+	RetryAfter time.Duration
+}
+
+type rateLimitValue struct {
+	Tokens  float64 `json:"tokens"`
+	Updated int64   `json:"updated"`
+}
+
+func init() {
+	registerType(TypeRateLimit, "ratelimit", func(raw json.RawMessage) (typedValue, error) {
+		var r rateLimitValue
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil, err
+		}
+		return &r, nil
+	})
+}
+
+func (r *rateLimitValue) valueType() ValueType { return TypeRateLimit }
+func (r *rateLimitValue) snapshot() any        { return r }
+
+func RateLimit(key string, capacity int64, refillPerSec float64, cost int64) (RateLimitResult, error) {
+	if capacity <= 0 || cost < 0 || cost > capacity || !(refillPerSec > 0) || math.IsInf(refillPerSec, 0) {
+		return RateLimitResult{}, ErrInvalidRateLimit
+	}
+	if err := checkSizeLimits(globals.GetConfig(), key, nil); err != nil {
+		return RateLimitResult{}, err
+	}
+
+	var res RateLimitResult
+
+	err := updateTyped(key, TypeRateLimit, func() *rateLimitValue {
+		return &rateLimitValue{Tokens: float64(capacity), Updated: time.Now().UnixMilli()}
+	}, func(r *rateLimitValue) (bool, error) {
+		now := time.Now().UnixMilli()
+		if elapsed := now - r.Updated; elapsed > 0 {
+			r.Tokens += float64(elapsed) / 1000 * refillPerSec
+		}
+		r.Tokens = math.Min(r.Tokens, float64(capacity))
+		r.Updated = now
+
+		if r.Tokens >= float64(cost) {
+			r.Tokens -= float64(cost)
+			res.Allowed = true
+		} else {
+			missing := float64(cost) - r.Tokens
+			res.RetryAfter = time.Duration(math.Ceil(missing/refillPerSec*1000)) * time.Millisecond
+		}
+		res.Remaining = int64(math.Floor(r.Tokens))
+
+		// A full bucket behaves exactly like a missing one, so idle buckets
+		// can go. Set under the shard lock so concurrent callers cannot
+		// apply their deadlines out of order.
+		fullAt := now + int64(math.Ceil((float64(capacity)-r.Tokens)/refillPerSec*1000))
+		setExpiration(globals.GetConfig(), key, max(fullAt, now+1))
+		return false, nil
+	})
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	return res, nil
+}
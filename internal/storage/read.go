@@ -6,56 +6,73 @@ import (
 	"os"
 	"strconv"
 
+	"github.com/taymour/elysiandb/internal/encryption"
 	"github.com/taymour/elysiandb/internal/globals"
 	"github.com/taymour/elysiandb/internal/log"
 )
 
 func ReadFromDB(fileName string) (map[string][]byte, error) {
-	cfg := globals.GetConfig()
+	data, _, err := readStoreFile(fileName)
+	return data, err
+}
+
+func ReadExpirationsFromDB(fileName string) (map[int64][]string, error) {
+	data, _, err := readExpirationsFile(fileName)
+	return data, err
+}
 
-	file, err := os.Open(cfg.Store.Folder + "/" + fileName)
+func readStoreFile(fileName string) (map[string][]byte, bool, error) {
+	byteValue, stale, err := readFile(fileName)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	defer file.Close()
 	log.Info("Successfully Opened "+fileName, " for reading.")
 
 	data := make(map[string][]byte)
 
-	byteValue, _ := io.ReadAll(file)
-
 	if len(byteValue) == 0 {
-		return data, nil
+		return data, stale, nil
 	}
 
 	if err := json.Unmarshal(byteValue, &data); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	return data, nil
+	return data, stale, nil
 }
 
-func ReadExpirationsFromDB(fileName string) (map[int64][]string, error) {
-	cfg := globals.GetConfig()
-
-	f, err := os.Open(cfg.Store.Folder + "/" + fileName)
+func readTypedFile(fileName string) (map[string]typedRecord, bool, error) {
+	byteValue, stale, err := readFile(fileName)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	defer f.Close()
 
-	bytes, err := io.ReadAll(f)
+	data := make(map[string]typedRecord)
+
+	if len(byteValue) == 0 {
+		return data, stale, nil
+	}
+
+	if err := json.Unmarshal(byteValue, &data); err != nil {
+		return nil, false, err
+	}
+
+	return data, stale, nil
+}
+
+func readExpirationsFile(fileName string) (map[int64][]string, bool, error) {
+	bytes, stale, err := readFile(fileName)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	if len(bytes) == 0 {
-		return make(map[int64][]string), nil
+		return make(map[int64][]string), stale, nil
 	}
 
 	var raw map[string][]string
 	if err := json.Unmarshal(bytes, &raw); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	out := make(map[int64][]string, len(raw))
@@ -68,5 +85,22 @@ func ReadExpirationsFromDB(fileName string) (map[int64][]string, error) {
 		out[ts] = cp
 	}
 
-	return out, nil
+	return out, stale, nil
+}
+
+func readFile(fileName string) ([]byte, bool, error) {
+	cfg := globals.GetConfig()
+
+	f, err := os.Open(cfg.Store.Folder + "/" + fileName)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	raw, err := io.ReadAll(f)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return encryption.Open(raw)
 }
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/taymour/elysiandb/internal/globals"
+)
+
+type setValue struct {
+	members map[string]struct{}
+}
+
+func init() {
+	registerType(TypeSet, "set", func(raw json.RawMessage) (typedValue, error) {
+		var members []string
+		if err := json.Unmarshal(raw, &members); err != nil {
+			return nil, err
+		}
+
+		s := newSet()
+		for _, m := range members {
+			s.members[m] = struct{}{}
+		}
+		return s, nil
+	})
+}
+
+func newSet() *setValue {
+	return &setValue{members: make(map[string]struct{})}
+}
+
+func (s *setValue) valueType() ValueType { return TypeSet }
+func (s *setValue) snapshot() any        { return sortedMembers(s.members) }
+
+func SAdd(key string, members []string) (int, error) {
+	cfg := globals.GetConfig()
+	for _, m := range members {
+		if err := checkSizeLimits(cfg, key, []byte(m)); err != nil {
+			return 0, err
+		}
+	}
+
+	added := 0
+	err := updateTyped(key, TypeSet, newSet, func(s *setValue) (bool, error) {
+		for _, m := range members {
+			if _, ok := s.members[m]; !ok {
+				s.members[m] = struct{}{}
+				added++
+			}
+		}
+		if added == 0 && len(s.members) > 0 {
+			return false, errUnchanged
+		}
+		return len(s.members) == 0, nil
+	})
+
+	return added, err
+}
+
+func SRem(key string, members []string) (int, error) {
+	removed := 0
+
+	err := updateTyped(key, TypeSet, nil, func(s *setValue) (bool, error) {
+		for _, m := range members {
+			if _, ok := s.members[m]; ok {
+				delete(s.members, m)
+				removed++
+			}
+		}
+		if removed == 0 {
+			return false, errUnchanged
+		}
+		return len(s.members) == 0, nil
+	})
+
+	return removed, err
+}
+
+func SMembers(key string) ([]string, bool, error) {
+	var out []string
+
+	found, err := viewTyped(key, TypeSet, func(s *setValue) error {
+		out = sortedMembers(s.members)
+		return nil
+	})
+
+	return out, found, err
+}
+
+func SIsMember(key string, member string) (bool, error) {
+	var ok bool
+
+	_, err := viewTyped(key, TypeSet, func(s *setValue) error {
+		_, ok = s.members[member]
+		return nil
+	})
+
+	return ok, err
+}
+
+func SInter(keys []string) ([]string, error) {
+	var result map[string]struct{}
+
+	for _, k := range keys {
+		next := make(map[string]struct{})
+		_, err := viewTyped(k, TypeSet, func(s *setValue) error {
+			for m := range s.members {
+				if _, ok := result[m]; ok || result == nil {
+					next[m] = struct{}{}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result = next
+		if len(result) == 0 {
+			break
+		}
+	}
+
+	return sortedMembers(result), nil
+}
+
+func SUnion(keys []string) ([]string, error) {
+	result := make(map[string]struct{})
+
+	for _, k := range keys {
+		_, err := viewTyped(k, TypeSet, func(s *setValue) error {
+			for m := range s.members {
+				result[m] = struct{}{}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sortedMembers(result), nil
+}
+
+func sortedMembers(members map[string]struct{}) []string {
+	out := make([]string, 0, len(members))
+	for m := range members {
+		out = append(out, m)
+	}
+	sort.Strings(out)
+
+	return out
+}
@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/log"
+	"github.com/taymour/elysiandb/internal/stat"
+)
+
+const SoftTTLDataFile = "elysiandb.soft.json"
+
+type PutOptions struct {
+	TTL     time.Duration
+	SoftTTL time.Duration
+	Tags    []string
+}
+
+// softIndex holds the unix-ms time after which a string value is served as
+// stale. Unlike the hard TTL nothing is removed when it passes; it is only
+// reset by the next write of the key.
+type softIndex struct {
+	mu    sync.RWMutex
+	at    map[string]int64
+	saved atomic.Bool
+}
+
+func PutKeyValueWithOptions(key string, value []byte, opts PutOptions) error {
+	cfg := globals.GetConfig()
+
+	if err := checkSizeLimits(cfg, key, value); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	softAt := int64(0)
+	if opts.SoftTTL > 0 {
+		softAt = now.Add(opts.SoftTTL).UnixMilli()
+	}
+
+	expireAt := int64(0)
+	if opts.TTL > 0 {
+		expireAt = now.Add(opts.TTL).UnixMilli()
+	}
+
+	inserted, hadTTL := mainStore.putWith(key, value, opts.Tags, softAt, expireAt)
+
+	if cfg.Stats.Enabled {
+		if inserted {
+			stat.Stats.IncrementKeysCount()
+		}
+		switch {
+		case expireAt > 0 && !hadTTL:
+			stat.Stats.IncrementExpirationKeysCount()
+		case expireAt == 0 && hadTTL:
+			stat.Stats.DecrementExpirationKeysCount()
+		}
+	}
+
+	return nil
+}
+
+func GetByKeyWithStale(key string) ([]byte, bool, error) {
+	if KeyHasExpired(key) {
+		expireKey(key)
+		return nil, false, fmt.Errorf("key not found: %s", key)
+	}
+
+	if val, softAt, ok := mainStore.getWithSoft(key); ok {
+		return val, softAt > 0 && time.Now().UnixMilli() >= softAt, nil
+	}
+	if mainStore.has(key) {
+		return nil, false, ErrWrongType
+	}
+	return nil, false, fmt.Errorf("key not found: %s", key)
+}
+
+func newSoftIndex() *softIndex {
+	s := &softIndex{at: make(map[string]int64)}
+	s.saved.Store(true)
+
+	return s
+}
+
+func loadSoftTTLs(fileName string, s *Store) *softIndex {
+	idx := newSoftIndex()
+
+	byteValue, stale, err := readFile(fileName)
+	if err != nil {
+		log.Fatal("Error loading soft TTLs:", err)
+	}
+
+	persisted := make(map[string]int64)
+	if len(byteValue) > 0 {
+		if err := json.Unmarshal(byteValue, &persisted); err != nil {
+			log.Fatal("Error loading soft TTLs:", err)
+		}
+	}
+
+	for key, at := range persisted {
+		if _, ok := s.get(key); ok {
+			idx.set(key, at)
+		}
+	}
+
+	idx.saved.Store(!stale)
+
+	return idx
+}
+
+func writeSoftTTLsToFile(cfg *configuration.Config, fileName string, idx *softIndex) (int, error) {
+	if idx.saved.Swap(true) {
+		return 0, nil
+	}
+
+	idx.mu.RLock()
+	at := make(map[string]int64, len(idx.at))
+	for k, ts := range idx.at {
+		at[k] = ts
+	}
+	idx.mu.RUnlock()
+
+	n, err := writeJSONFile(cfg.Store.Folder+"/"+fileName, at)
+	if err != nil {
+		idx.saved.Store(false)
+	}
+
+	return n, err
+}
+
+func (idx *softIndex) set(key string, at int64) {
+	if idx == nil {
+		return
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if at <= 0 {
+		if _, ok := idx.at[key]; ok {
+			delete(idx.at, key)
+			idx.saved.Store(false)
+		}
+		return
+	}
+
+	idx.at[key] = at
+	idx.saved.Store(false)
+}
+
+func (idx *softIndex) forget(key string) {
+	idx.set(key, 0)
+}
+
+func (idx *softIndex) get(key string) int64 {
+	if idx == nil {
+		return 0
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return idx.at[key]
+}
+
+func (idx *softIndex) clear() {
+	if idx == nil {
+		return
+	}
+
+	idx.mu.Lock()
+	idx.at = make(map[string]int64)
+	idx.saved.Store(false)
+	idx.mu.Unlock()
+}
@@ -3,9 +3,12 @@ package storage
 import (
 	"sync"
 	"sync/atomic"
+	"time"
 
 	xxhash "github.com/cespare/xxhash/v2"
 	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/jsondoc"
+	"github.com/taymour/elysiandb/internal/timingwheel"
 )
 
 const (
@@ -13,22 +16,19 @@ const (
 	ExpirationDataFile = "elysiandb.expiration.json"
 )
 
-type ExpirationContainer struct {
-	Buckets map[int64]*ExpirationBucket
-	index   map[string]int64
-	mu      sync.RWMutex
-	saved   atomic.Bool
-}
+const expirationTick = 10 * time.Millisecond
 
-type ExpirationBucket struct {
-	Keys []string
-	mu   sync.RWMutex
+type ExpirationContainer struct {
+	index map[string]int64
+	wheel *timingwheel.Wheel[string]
+	mu    sync.RWMutex
+	saved atomic.Bool
 }
 
 func newExpirationContainer() *ExpirationContainer {
 	c := &ExpirationContainer{
-		Buckets: make(map[int64]*ExpirationBucket),
-		index:   make(map[string]int64),
+		index: make(map[string]int64),
+		wheel: timingwheel.New[string](expirationTick, time.Now()),
 	}
 
 	c.saved.Store(true)
@@ -36,42 +36,20 @@ func newExpirationContainer() *ExpirationContainer {
 	return c
 }
 
-func (s *ExpirationContainer) CountTotalKeys() uint64 {
-	total := uint64(0)
-	s.mu.RLock()
-	for _, b := range s.Buckets {
-		b.mu.RLock()
-		total += uint64(len(b.Keys))
-		b.mu.RUnlock()
-	}
+func (c *ExpirationContainer) CountTotalKeys() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	s.mu.RUnlock()
-	return total
+	return uint64(len(c.index))
 }
 
 func (c *ExpirationContainer) put(ts int64, keys []string) {
-	for _, k := range keys {
-		c.del(k)
-	}
+	deadline := wallDeadline(ts)
 
 	c.mu.Lock()
-	b := c.Buckets[ts]
-	if b == nil {
-		b = &ExpirationBucket{}
-		c.Buckets[ts] = b
-	}
-	c.mu.Unlock()
-
-	b.mu.Lock()
-	b.Keys = append(b.Keys, keys...)
-	b.mu.Unlock()
-
-	c.mu.Lock()
-	if c.index == nil {
-		c.index = make(map[string]int64)
-	}
 	for _, k := range keys {
 		c.index[k] = ts
+		c.wheel.Schedule(k, deadline)
 	}
 	c.saved.Store(false)
 	c.mu.Unlock()
@@ -81,11 +59,8 @@ func (c *ExpirationContainer) ToMap() map[int64][]string {
 	result := make(map[int64][]string)
 
 	c.mu.RLock()
-	for k, v := range c.Buckets {
-		v.mu.RLock()
-		cp := append([]string(nil), v.Keys...)
-		v.mu.RUnlock()
-		result[k] = cp
+	for k, ts := range c.index {
+		result[ts] = append(result[ts], k)
 	}
 	c.mu.RUnlock()
 
@@ -98,52 +73,48 @@ func (c *ExpirationContainer) FromMap(data map[int64][]string) {
 	}
 }
 
-func (c *ExpirationContainer) del(key string) {
+func (c *ExpirationContainer) del(key string) bool {
+	c.mu.Lock()
+	_, ok := c.index[key]
+	if ok {
+		delete(c.index, key)
+		c.wheel.Remove(key)
+		c.saved.Store(false)
+	}
+	c.mu.Unlock()
+
+	return ok
+}
+
+func (c *ExpirationContainer) expired(key string, now int64) bool {
 	c.mu.RLock()
 	ts, ok := c.index[key]
 	c.mu.RUnlock()
-	if !ok {
-		return
-	}
 
-	var b *ExpirationBucket
-	c.mu.Lock()
-	b = c.Buckets[ts]
-	delete(c.index, key)
-	c.mu.Unlock()
-
-	if b != nil {
-		b.mu.Lock()
-		for i, k := range b.Keys {
-			if k == key {
-				b.Keys = append(b.Keys[:i], b.Keys[i+1:]...)
-				break
-			}
-		}
-		empty := len(b.Keys) == 0
-		b.mu.Unlock()
+	return ok && now >= ts
+}
 
-		if empty {
-			c.mu.Lock()
-			if c.Buckets[ts] == b {
-				delete(c.Buckets, ts)
-			}
-			c.mu.Unlock()
-		}
-	}
-	c.saved.Store(false)
+func (c *ExpirationContainer) due(now time.Time, max int) []string {
+	return c.wheel.Advance(now, max)
 }
 
 func (c *ExpirationContainer) reset() {
 	c.mu.Lock()
-	c.Buckets = make(map[int64]*ExpirationBucket)
 	c.index = make(map[string]int64)
+	c.wheel.Reset()
+	c.saved.Store(false)
 	c.mu.Unlock()
 }
 
+func wallDeadline(ts int64) time.Time {
+	now := time.Now()
+	return now.Add(time.Duration(ts-now.UnixMilli()) * time.Millisecond)
+}
+
 type shard struct {
-	mu sync.RWMutex
-	m  map[string][]byte
+	mu    sync.RWMutex
+	m     map[string][]byte
+	typed map[string]typedValue
 }
 
 type Store struct {
@@ -151,6 +122,10 @@ type Store struct {
 	saved      atomic.Bool
 	shardMask  uint64
 	shardCount int
+	expired    func(key string, now int64) bool
+	indexes    *indexRegistry
+	tags       *tagIndex
+	soft       *softIndex
 }
 
 func NewStore() *Store {
@@ -163,7 +138,7 @@ func NewStore() *Store {
 	}
 
 	for i := 0; i < n; i++ {
-		s.shards[i] = &shard{m: make(map[string][]byte)}
+		s.shards[i] = &shard{m: make(map[string][]byte), typed: make(map[string]typedValue)}
 	}
 
 	s.saved.Store(true)
@@ -176,7 +151,7 @@ func (s *Store) CountTotalKeys() uint64 {
 	for i := 0; i < s.shardCount; i++ {
 		sh := s.shards[i]
 		sh.mu.RLock()
-		total += uint64(len(sh.m))
+		total += uint64(len(sh.m) + len(sh.typed))
 		sh.mu.RUnlock()
 	}
 
@@ -188,8 +163,12 @@ func (s *Store) reset() {
 		sh := s.shards[i]
 		sh.mu.Lock()
 		sh.m = make(map[string][]byte)
+		sh.typed = make(map[string]typedValue)
 		sh.mu.Unlock()
 	}
+	s.indexes.clear()
+	s.tags.clear()
+	s.soft.clear()
 	s.saved.Store(false)
 }
 
@@ -210,30 +189,108 @@ func (s *Store) get(key string) ([]byte, bool) {
 	return v, true
 }
 
-func (s *Store) put(key string, value []byte) {
+func (s *Store) getWithSoft(key string) ([]byte, int64, bool) {
+	sh := s.shards[s.shardIndex(key)]
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+
+	v, ok := sh.m[key]
+	if !ok {
+		return nil, 0, false
+	}
+
+	return v, s.soft.get(key), true
+}
+
+// putWith replaces the value and everything attached to it in one step under
+// the shard lock, so a concurrent lazy expiry cannot remove the new value on
+// the strength of the old deadline. A zero expireAt drops any deadline.
+func (s *Store) putWith(key string, value []byte, tags []string, softAt int64, expireAt int64) (inserted bool, hadTTL bool) {
 	buf := make([]byte, len(value))
 	copy(buf, value)
 
 	sh := s.shards[s.shardIndex(key)]
 	sh.mu.Lock()
+	_, existed := sh.m[key]
+	if !existed {
+		_, existed = sh.typed[key]
+	}
+	hadTTL = expirationContainer.del(key)
+	if expireAt > 0 {
+		expirationContainer.put(expireAt, []string{key})
+	}
 	sh.m[key] = buf
+	delete(sh.typed, key)
+	s.indexes.observe(key, func() (any, bool) {
+		root, err := jsondoc.Decode(buf)
+		return root, err == nil
+	})
+	s.tags.set(key, tags)
+	s.soft.set(key, softAt)
 	sh.mu.Unlock()
 	s.saved.Store(false)
+
+	notifyKey(key)
+
+	return !existed, hadTTL
 }
 
-func (s *Store) del(key string) {
+func (s *Store) has(key string) bool {
 	sh := s.shards[s.shardIndex(key)]
-	sh.mu.Lock()
+	sh.mu.RLock()
+	_, ok := sh.m[key]
+	if !ok {
+		_, ok = sh.typed[key]
+	}
+	sh.mu.RUnlock()
+
+	return ok
+}
+
+func (s *Store) delLocked(sh *shard, key string) bool {
+	_, existed := sh.m[key]
+	if !existed {
+		_, existed = sh.typed[key]
+	}
+
 	delete(sh.m, key)
-	sh.mu.Unlock()
+	delete(sh.typed, key)
+	s.indexes.forget(key)
+	s.tags.forget(key)
+	s.soft.forget(key)
 	s.saved.Store(false)
+
+	return existed
+}
+
+func (s *Store) IterateKeys(fn func(k string)) {
+	now := time.Now().UnixMilli()
+	for i := 0; i < s.shardCount; i++ {
+		sh := s.shards[i]
+		sh.mu.RLock()
+		for k := range sh.m {
+			if s.expired == nil || !s.expired(k, now) {
+				fn(k)
+			}
+		}
+		for k := range sh.typed {
+			if s.expired == nil || !s.expired(k, now) {
+				fn(k)
+			}
+		}
+		sh.mu.RUnlock()
+	}
 }
 
 func (s *Store) Iterate(fn func(k string, v []byte)) {
+	now := time.Now().UnixMilli()
 	for i := 0; i < s.shardCount; i++ {
 		sh := s.shards[i]
 		sh.mu.RLock()
 		for k, v := range sh.m {
+			if s.expired != nil && s.expired(k, now) {
+				continue
+			}
 			c := make([]byte, len(v))
 			copy(c, v)
 			fn(k, c)
@@ -0,0 +1,720 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/globals"
+)
+
+var (
+	ErrInvalidStreamID  = errors.New("invalid stream ID")
+	ErrStreamIDTooSmall = errors.New("stream ID must be greater than the last entry ID")
+	ErrNoSuchStream     = errors.New("no such stream")
+	ErrNoGroup          = errors.New("NOGROUP no such consumer group")
+	ErrGroupExists      = errors.New("BUSYGROUP consumer group already exists")
+)
+
+type StreamID struct {
+	Ms  uint64
+	Seq uint64
+}
+
+func (id StreamID) String() string {
+	return strconv.FormatUint(id.Ms, 10) + "-" + strconv.FormatUint(id.Seq, 10)
+}
+
+func (id StreamID) Less(o StreamID) bool {
+	return id.Ms < o.Ms || (id.Ms == o.Ms && id.Seq < o.Seq)
+}
+
+func ParseStreamID(s string) (StreamID, error) {
+	return parseStreamID(s, 0)
+}
+
+func parseStreamID(s string, defaultSeq uint64) (StreamID, error) {
+	msPart, seqPart, hasSeq := strings.Cut(s, "-")
+
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return StreamID{}, ErrInvalidStreamID
+	}
+
+	seq := defaultSeq
+	if hasSeq {
+		if seq, err = strconv.ParseUint(seqPart, 10, 64); err != nil {
+			return StreamID{}, ErrInvalidStreamID
+		}
+	}
+
+	return StreamID{Ms: ms, Seq: seq}, nil
+}
+
+type StreamEntry struct {
+	ID     StreamID
+	Fields []HashField
+}
+
+type StreamRead struct {
+	Key     string
+	Entries []StreamEntry
+}
+
+type StreamTrim struct {
+	MaxLen   int
+	ByLength bool
+	MaxAge   time.Duration
+}
+
+type PendingEntry struct {
+	ID         StreamID
+	Consumer   string
+	Idle       time.Duration
+	Deliveries int
+}
+
+type pendingEntry struct {
+	consumer    string
+	deliveredAt int64
+	deliveries  int
+}
+
+type consumerGroup struct {
+	lastDelivered StreamID
+	pending       map[StreamID]*pendingEntry
+}
+
+type streamValue struct {
+	entries []StreamEntry
+	lastID  StreamID
+	groups  map[string]*consumerGroup
+}
+
+type streamSnapshot struct {
+	LastID  string                   `json:"last_id"`
+	Entries []streamEntrySnapshot    `json:"entries"`
+	Groups  map[string]groupSnapshot `json:"groups,omitempty"`
+}
+
+type streamEntrySnapshot struct {
+	ID     string   `json:"id"`
+	Fields [][]byte `json:"fields"`
+}
+
+type groupSnapshot struct {
+	LastDelivered string            `json:"last_delivered"`
+	Pending       []pendingSnapshot `json:"pending"`
+}
+
+type pendingSnapshot struct {
+	ID          string `json:"id"`
+	Consumer    string `json:"consumer"`
+	DeliveredAt int64  `json:"delivered_at"`
+	Deliveries  int    `json:"deliveries"`
+}
+
+func init() {
+	registerType(TypeStream, "stream", func(raw json.RawMessage) (typedValue, error) {
+		var snap streamSnapshot
+		if err := json.Unmarshal(raw, &snap); err != nil {
+			return nil, err
+		}
+
+		return streamFromSnapshot(snap)
+	})
+}
+
+func newStream() *streamValue {
+	return &streamValue{groups: make(map[string]*consumerGroup)}
+}
+
+func (s *streamValue) valueType() ValueType { return TypeStream }
+
+func (s *streamValue) snapshot() any {
+	snap := streamSnapshot{
+		LastID:  s.lastID.String(),
+		Entries: make([]streamEntrySnapshot, len(s.entries)),
+		Groups:  make(map[string]groupSnapshot, len(s.groups)),
+	}
+
+	for i, e := range s.entries {
+		fields := make([][]byte, 0, len(e.Fields)*2)
+		for _, f := range e.Fields {
+			fields = append(fields, []byte(f.Field), f.Value)
+		}
+		snap.Entries[i] = streamEntrySnapshot{ID: e.ID.String(), Fields: fields}
+	}
+
+	for name, g := range s.groups {
+		gs := groupSnapshot{LastDelivered: g.lastDelivered.String(), Pending: make([]pendingSnapshot, 0, len(g.pending))}
+		for id, p := range g.pending {
+			gs.Pending = append(gs.Pending, pendingSnapshot{ID: id.String(), Consumer: p.consumer, DeliveredAt: p.deliveredAt, Deliveries: p.deliveries})
+		}
+		snap.Groups[name] = gs
+	}
+
+	return snap
+}
+
+func streamFromSnapshot(snap streamSnapshot) (*streamValue, error) {
+	s := newStream()
+
+	var err error
+	if s.lastID, err = ParseStreamID(snap.LastID); err != nil {
+		return nil, err
+	}
+
+	s.entries = make([]StreamEntry, len(snap.Entries))
+	for i, e := range snap.Entries {
+		id, err := ParseStreamID(e.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		fields := make([]HashField, 0, len(e.Fields)/2)
+		for j := 0; j+1 < len(e.Fields); j += 2 {
+			fields = append(fields, HashField{Field: string(e.Fields[j]), Value: e.Fields[j+1]})
+		}
+		s.entries[i] = StreamEntry{ID: id, Fields: fields}
+	}
+
+	for name, gs := range snap.Groups {
+		g := &consumerGroup{pending: make(map[StreamID]*pendingEntry, len(gs.Pending))}
+		if g.lastDelivered, err = ParseStreamID(gs.LastDelivered); err != nil {
+			return nil, err
+		}
+
+		for _, p := range gs.Pending {
+			id, err := ParseStreamID(p.ID)
+			if err != nil {
+				return nil, err
+			}
+			g.pending[id] = &pendingEntry{consumer: p.Consumer, deliveredAt: p.DeliveredAt, deliveries: p.Deliveries}
+		}
+		s.groups[name] = g
+	}
+
+	return s, nil
+}
+
+func (s *streamValue) after(id StreamID) int {
+	return sort.Search(len(s.entries), func(i int) bool { return id.Less(s.entries[i].ID) })
+}
+
+func (s *streamValue) find(id StreamID) (StreamEntry, bool) {
+	i := sort.Search(len(s.entries), func(i int) bool { return !s.entries[i].ID.Less(id) })
+	if i < len(s.entries) && s.entries[i].ID == id {
+		return s.entries[i], true
+	}
+
+	return StreamEntry{}, false
+}
+
+func (s *streamValue) nextID(requested string, now time.Time) (StreamID, error) {
+	if requested == "*" {
+		ms := uint64(now.UnixMilli())
+		if ms <= s.lastID.Ms {
+			if s.lastID.Seq == math.MaxUint64 {
+				return StreamID{Ms: s.lastID.Ms + 1}, nil
+			}
+			return StreamID{Ms: s.lastID.Ms, Seq: s.lastID.Seq + 1}, nil
+		}
+		return StreamID{Ms: ms}, nil
+	}
+
+	if msPart, ok := strings.CutSuffix(requested, "-*"); ok {
+		ms, err := strconv.ParseUint(msPart, 10, 64)
+		if err != nil {
+			return StreamID{}, ErrInvalidStreamID
+		}
+		id := StreamID{Ms: ms}
+		if ms == s.lastID.Ms {
+			id.Seq = s.lastID.Seq + 1
+		}
+		if !s.lastID.Less(id) {
+			return StreamID{}, ErrStreamIDTooSmall
+		}
+		return id, nil
+	}
+
+	id, err := ParseStreamID(requested)
+	if err != nil {
+		return StreamID{}, err
+	}
+	if !s.lastID.Less(id) {
+		return StreamID{}, ErrStreamIDTooSmall
+	}
+
+	return id, nil
+}
+
+func (s *streamValue) trim(t StreamTrim, now time.Time) int {
+	drop := 0
+
+	if t.ByLength && len(s.entries) > t.MaxLen {
+		drop = len(s.entries) - max(t.MaxLen, 0)
+	}
+
+	if t.MaxAge > 0 {
+		cutoff := StreamID{Ms: uint64(max(now.Add(-t.MaxAge).UnixMilli(), 0))}
+		drop = max(drop, sort.Search(len(s.entries), func(i int) bool { return !s.entries[i].ID.Less(cutoff) }))
+	}
+
+	if drop == 0 {
+		return 0
+	}
+
+	clear(s.entries[:drop])
+	s.entries = s.entries[drop:]
+
+	return drop
+}
+
+func copyEntry(e StreamEntry) StreamEntry {
+	fields := make([]HashField, len(e.Fields))
+	for i, f := range e.Fields {
+		fields[i] = HashField{Field: f.Field, Value: append([]byte{}, f.Value...)}
+	}
+
+	return StreamEntry{ID: e.ID, Fields: fields}
+}
+
+func XAdd(key string, id string, fields []HashField, trim StreamTrim) (StreamID, error) {
+	cfg := globals.GetConfig()
+	for _, f := range fields {
+		if err := checkSizeLimits(cfg, key, f.Value); err != nil {
+			return StreamID{}, err
+		}
+	}
+
+	var added StreamID
+	err := updateTyped(key, TypeStream, newStream, func(s *streamValue) (bool, error) {
+		now := time.Now()
+
+		next, err := s.nextID(id, now)
+		if err != nil {
+			return false, err
+		}
+
+		entry := StreamEntry{ID: next, Fields: make([]HashField, len(fields))}
+		for i, f := range fields {
+			entry.Fields[i] = HashField{Field: f.Field, Value: append([]byte{}, f.Value...)}
+		}
+
+		s.entries = append(s.entries, entry)
+		s.lastID = next
+		s.trim(trim, now)
+		added = next
+
+		notifyKey(key)
+
+		return false, nil
+	})
+
+	return added, err
+}
+
+func XTrim(key string, trim StreamTrim) (int, error) {
+	removed := 0
+
+	err := updateTyped(key, TypeStream, nil, func(s *streamValue) (bool, error) {
+		if removed = s.trim(trim, time.Now()); removed == 0 {
+			return false, errUnchanged
+		}
+		return false, nil
+	})
+
+	return removed, err
+}
+
+func XLen(key string) (int, error) {
+	n := 0
+
+	_, err := viewTyped(key, TypeStream, func(s *streamValue) error {
+		n = len(s.entries)
+		return nil
+	})
+
+	return n, err
+}
+
+func parseRangeBound(s string, start bool) (StreamID, bool, error) {
+	switch s {
+	case "-":
+		return StreamID{}, false, nil
+	case "+":
+		return StreamID{Ms: math.MaxUint64, Seq: math.MaxUint64}, false, nil
+	}
+
+	exclusive := strings.HasPrefix(s, "(")
+	if exclusive {
+		s = s[1:]
+	}
+
+	defaultSeq := uint64(0)
+	if !start {
+		defaultSeq = math.MaxUint64
+	}
+
+	id, err := parseStreamID(s, defaultSeq)
+
+	return id, exclusive, err
+}
+
+func XRange(key string, start, end string, count int) ([]StreamEntry, bool, error) {
+	from, fromExcl, err := parseRangeBound(start, true)
+	if err != nil {
+		return nil, false, err
+	}
+	to, toExcl, err := parseRangeBound(end, false)
+	if err != nil {
+		return nil, false, err
+	}
+
+	out := make([]StreamEntry, 0)
+	found, err := viewTyped(key, TypeStream, func(s *streamValue) error {
+		i := sort.Search(len(s.entries), func(i int) bool { return !s.entries[i].ID.Less(from) })
+		for ; i < len(s.entries); i++ {
+			e := s.entries[i]
+			if fromExcl && e.ID == from {
+				continue
+			}
+			if to.Less(e.ID) || (toExcl && e.ID == to) {
+				break
+			}
+			if count > 0 && len(out) == count {
+				break
+			}
+			out = append(out, copyEntry(e))
+		}
+		return nil
+	})
+
+	return out, found, err
+}
+
+func lastStreamID(key string) (StreamID, error) {
+	var id StreamID
+
+	_, err := viewTyped(key, TypeStream, func(s *streamValue) error {
+		id = s.lastID
+		return nil
+	})
+
+	return id, err
+}
+
+func XRead(keys []string, ids []string, count int, block bool, timeout time.Duration, done <-chan struct{}) ([]StreamRead, error) {
+	from := make([]StreamID, len(keys))
+	for i, raw := range ids {
+		var err error
+		if raw == "$" {
+			from[i], err = lastStreamID(keys[i])
+		} else {
+			from[i], err = ParseStreamID(raw)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	try := func() ([]StreamRead, bool, error) {
+		var out []StreamRead
+		for i, k := range keys {
+			var entries []StreamEntry
+			_, err := viewTyped(k, TypeStream, func(s *streamValue) error {
+				for j := s.after(from[i]); j < len(s.entries); j++ {
+					if count > 0 && len(entries) == count {
+						break
+					}
+					entries = append(entries, copyEntry(s.entries[j]))
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, false, err
+			}
+			if len(entries) > 0 {
+				out = append(out, StreamRead{Key: k, Entries: entries})
+			}
+		}
+		return out, len(out) > 0, nil
+	}
+
+	if !block {
+		out, _, err := try()
+		return out, err
+	}
+
+	out, _, err := waitUntilReady(keys, timeout, done, try)
+
+	return out, err
+}
+
+func XGroupCreate(key string, group string, id string, mkstream bool) error {
+	create := newStream
+	if !mkstream {
+		create = nil
+	}
+
+	found := false
+	err := updateTyped(key, TypeStream, create, func(s *streamValue) (bool, error) {
+		found = true
+
+		if _, ok := s.groups[group]; ok {
+			return false, ErrGroupExists
+		}
+
+		start := s.lastID
+		if id != "$" {
+			var err error
+			if start, err = ParseStreamID(id); err != nil {
+				return false, err
+			}
+		}
+
+		s.groups[group] = &consumerGroup{lastDelivered: start, pending: make(map[StreamID]*pendingEntry)}
+		return false, nil
+	})
+	if err == nil && !found {
+		return ErrNoSuchStream
+	}
+
+	return err
+}
+
+func XGroupDestroy(key string, group string) (bool, error) {
+	destroyed := false
+
+	err := updateTyped(key, TypeStream, nil, func(s *streamValue) (bool, error) {
+		if _, destroyed = s.groups[group]; !destroyed {
+			return false, errUnchanged
+		}
+		delete(s.groups, group)
+		return false, nil
+	})
+
+	return destroyed, err
+}
+
+func XReadGroup(group string, consumer string, keys []string, ids []string, count int, block bool, timeout time.Duration, done <-chan struct{}) ([]StreamRead, error) {
+	history := false
+	from := make([]StreamID, len(keys))
+	for i, raw := range ids {
+		if raw == ">" {
+			continue
+		}
+
+		id, err := ParseStreamID(raw)
+		if err != nil {
+			return nil, err
+		}
+		from[i], history = id, true
+	}
+
+	try := func() ([]StreamRead, bool, error) {
+		var out []StreamRead
+		for i, k := range keys {
+			var entries []StreamEntry
+			found := false
+
+			err := updateTyped(k, TypeStream, nil, func(s *streamValue) (bool, error) {
+				found = true
+
+				g, ok := s.groups[group]
+				if !ok {
+					return false, ErrNoGroup
+				}
+
+				if ids[i] != ">" {
+					entries = s.pendingFor(g, consumer, from[i], count)
+					return false, errUnchanged
+				}
+				if entries = s.deliverNew(g, consumer, count); len(entries) == 0 {
+					return false, errUnchanged
+				}
+				return false, nil
+			})
+			if err == nil && !found {
+				err = ErrNoGroup
+			}
+			if err != nil {
+				return nil, false, err
+			}
+
+			if len(entries) > 0 || ids[i] != ">" {
+				out = append(out, StreamRead{Key: k, Entries: entries})
+			}
+		}
+		return out, history || len(out) > 0, nil
+	}
+
+	if !block || history {
+		out, _, err := try()
+		return out, err
+	}
+
+	out, _, err := waitUntilReady(keys, timeout, done, try)
+
+	return out, err
+}
+
+func (s *streamValue) deliverNew(g *consumerGroup, consumer string, count int) []StreamEntry {
+	now := time.Now().UnixMilli()
+
+	var out []StreamEntry
+	for j := s.after(g.lastDelivered); j < len(s.entries); j++ {
+		if count > 0 && len(out) == count {
+			break
+		}
+
+		e := s.entries[j]
+		g.pending[e.ID] = &pendingEntry{consumer: consumer, deliveredAt: now, deliveries: 1}
+		g.lastDelivered = e.ID
+		out = append(out, copyEntry(e))
+	}
+
+	return out
+}
+
+func (s *streamValue) pendingFor(g *consumerGroup, consumer string, from StreamID, count int) []StreamEntry {
+	ids := make([]StreamID, 0)
+	for id, p := range g.pending {
+		if p.consumer == consumer && from.Less(id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Less(ids[j]) })
+
+	if count > 0 && len(ids) > count {
+		ids = ids[:count]
+	}
+
+	out := make([]StreamEntry, 0, len(ids))
+	for _, id := range ids {
+		if e, ok := s.find(id); ok {
+			out = append(out, copyEntry(e))
+		} else {
+			out = append(out, StreamEntry{ID: id})
+		}
+	}
+
+	return out
+}
+
+func XAck(key string, group string, ids []string) (int, error) {
+	parsed := make([]StreamID, len(ids))
+	for i, raw := range ids {
+		id, err := ParseStreamID(raw)
+		if err != nil {
+			return 0, err
+		}
+		parsed[i] = id
+	}
+
+	acked := 0
+	err := updateTyped(key, TypeStream, nil, func(s *streamValue) (bool, error) {
+		g, ok := s.groups[group]
+		if !ok {
+			return false, errUnchanged
+		}
+
+		for _, id := range parsed {
+			if _, ok := g.pending[id]; ok {
+				delete(g.pending, id)
+				acked++
+			}
+		}
+		if acked == 0 {
+			return false, errUnchanged
+		}
+		return false, nil
+	})
+
+	return acked, err
+}
+
+func XPending(key string, group string, consumer string) ([]PendingEntry, error) {
+	var out []PendingEntry
+
+	found, err := viewTyped(key, TypeStream, func(s *streamValue) error {
+		g, ok := s.groups[group]
+		if !ok {
+			return ErrNoGroup
+		}
+
+		now := time.Now().UnixMilli()
+		out = make([]PendingEntry, 0, len(g.pending))
+		for id, p := range g.pending {
+			if consumer != "" && p.consumer != consumer {
+				continue
+			}
+			out = append(out, PendingEntry{
+				ID:         id,
+				Consumer:   p.consumer,
+				Idle:       time.Duration(now-p.deliveredAt) * time.Millisecond,
+				Deliveries: p.deliveries,
+			})
+		}
+		return nil
+	})
+	if err == nil && !found {
+		return nil, ErrNoGroup
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID.Less(out[j].ID) })
+
+	return out, err
+}
+
+func XClaim(key string, group string, consumer string, minIdle time.Duration, ids []string) ([]StreamEntry, error) {
+	parsed := make([]StreamID, len(ids))
+	for i, raw := range ids {
+		id, err := ParseStreamID(raw)
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = id
+	}
+
+	var out []StreamEntry
+	found := false
+	err := updateTyped(key, TypeStream, nil, func(s *streamValue) (bool, error) {
+		found = true
+
+		g, ok := s.groups[group]
+		if !ok {
+			return false, ErrNoGroup
+		}
+
+		now := time.Now().UnixMilli()
+		for _, id := range parsed {
+			p, ok := g.pending[id]
+			if !ok || time.Duration(now-p.deliveredAt)*time.Millisecond < minIdle {
+				continue
+			}
+
+			e, ok := s.find(id)
+			if !ok {
+				delete(g.pending, id)
+				continue
+			}
+
+			p.consumer = consumer
+			p.deliveredAt = now
+			p.deliveries++
+			out = append(out, copyEntry(e))
+		}
+		return false, nil
+	})
+	if err == nil && !found {
+		return nil, ErrNoGroup
+	}
+
+	return out, err
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/log"
+)
+
+const TagDataFile = "elysiandb.tags.json"
+
+type tagIndex struct {
+	mu    sync.RWMutex
+	byTag map[string]map[string]struct{}
+	byKey map[string][]string
+	saved atomic.Bool
+}
+
+func ParseTags(s string) []string {
+	tags := make([]string, 0, strings.Count(s, ",")+1)
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" && !slices.Contains(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+
+	return tags
+}
+
+func InvalidateTag(tag string) int {
+	deleted := 0
+	for _, k := range mainStore.tags.keys(tag) {
+		if Exists(k) {
+			DeleteByKey(k)
+			deleted++
+		}
+	}
+
+	return deleted
+}
+
+func newTagIndex() *tagIndex {
+	t := &tagIndex{
+		byTag: make(map[string]map[string]struct{}),
+		byKey: make(map[string][]string),
+	}
+	t.saved.Store(true)
+
+	return t
+}
+
+func loadTags(fileName string, s *Store) *tagIndex {
+	t := newTagIndex()
+
+	byteValue, stale, err := readFile(fileName)
+	if err != nil {
+		log.Fatal("Error loading tags:", err)
+	}
+
+	persisted := make(map[string][]string)
+	if len(byteValue) > 0 {
+		if err := json.Unmarshal(byteValue, &persisted); err != nil {
+			log.Fatal("Error loading tags:", err)
+		}
+	}
+
+	for key, tags := range persisted {
+		if s.has(key) {
+			t.set(key, tags)
+		}
+	}
+
+	t.saved.Store(!stale)
+
+	return t
+}
+
+func writeTagsToFile(cfg *configuration.Config, fileName string, t *tagIndex) (int, error) {
+	if t.saved.Swap(true) {
+		return 0, nil
+	}
+
+	t.mu.RLock()
+	byKey := make(map[string][]string, len(t.byKey))
+	for k, tags := range t.byKey {
+		byKey[k] = tags
+	}
+	t.mu.RUnlock()
+
+	n, err := writeJSONFile(cfg.Store.Folder+"/"+fileName, byKey)
+	if err != nil {
+		t.saved.Store(false)
+	}
+
+	return n, err
+}
+
+func (t *tagIndex) set(key string, tags []string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.unlink(key)
+
+	if len(tags) == 0 {
+		return
+	}
+
+	t.byKey[key] = tags
+	t.saved.Store(false)
+	for _, tag := range tags {
+		keys, ok := t.byTag[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			t.byTag[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+func (t *tagIndex) forget(key string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	t.unlink(key)
+	t.mu.Unlock()
+}
+
+func (t *tagIndex) unlink(key string) {
+	old, ok := t.byKey[key]
+	if !ok {
+		return
+	}
+
+	for _, tag := range old {
+		delete(t.byTag[tag], key)
+		if len(t.byTag[tag]) == 0 {
+			delete(t.byTag, tag)
+		}
+	}
+	delete(t.byKey, key)
+	t.saved.Store(false)
+}
+
+func (t *tagIndex) clear() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	t.byTag = make(map[string]map[string]struct{})
+	t.byKey = make(map[string][]string)
+	t.saved.Store(false)
+	t.mu.Unlock()
+}
+
+func (t *tagIndex) keys(tag string) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	out := make([]string, 0, len(t.byTag[tag]))
+	for k := range t.byTag[tag] {
+		out = append(out, k)
+	}
+
+	return out
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/stat"
+)
+
+const (
+	legacySecondsThreshold = 1_000_000_000_000
+	expirationBatchSize    = 1000
+)
+
+func ExpireAt(key string, at time.Time) bool {
+	if !Exists(key) {
+		return false
+	}
+
+	ts := at.UnixMilli()
+	if ts <= time.Now().UnixMilli() {
+		DeleteByKey(key)
+		return true
+	}
+
+	setExpiration(globals.GetConfig(), key, ts)
+
+	return true
+}
+
+func Expire(key string, ttl time.Duration) bool {
+	return ExpireAt(key, time.Now().Add(ttl))
+}
+
+func Persist(key string) bool {
+	if !Exists(key) || !hasTTL(key) {
+		return false
+	}
+
+	expirationContainer.del(key)
+
+	if globals.GetConfig().Stats.Enabled {
+		stat.Stats.DecrementExpirationKeysCount()
+	}
+
+	return true
+}
+
+func GetTTL(key string) (ttl time.Duration, hasExpiry bool, exists bool) {
+	if !Exists(key) {
+		return 0, false, false
+	}
+
+	ts, ok := expiresAt(key)
+	if !ok {
+		return 0, false, true
+	}
+
+	remaining := time.Duration(ts-time.Now().UnixMilli()) * time.Millisecond
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return remaining, true, true
+}
+
+func Exists(key string) bool {
+	if KeyHasExpired(key) {
+		expireKey(key)
+		return false
+	}
+
+	return mainStore.has(key)
+}
+
+func expireKey(key string) {
+	if deleteKey(key, true) && globals.GetConfig().Stats.Enabled {
+		stat.Stats.AddExpiredKeys(1)
+	}
+}
+
+func setExpiration(cfg *configuration.Config, key string, ts int64) {
+	hadTTL := hasTTL(key)
+
+	expirationContainer.put(ts, []string{key})
+
+	if cfg.Stats.Enabled && !hadTTL {
+		stat.Stats.IncrementExpirationKeysCount()
+	}
+}
+
+func expiresAt(key string) (int64, bool) {
+	expirationContainer.mu.RLock()
+	ts, ok := expirationContainer.index[key]
+	expirationContainer.mu.RUnlock()
+
+	return ts, ok
+}
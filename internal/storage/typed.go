@@ -0,0 +1,233 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/stat"
+)
+
+const TypedDataFile = "elysiandb.typed.json"
+
+var (
+	ErrWrongType  = errors.New("WRONGTYPE operation against a key holding the wrong kind of value")
+	ErrNotInteger = errors.New("value is not an integer")
+	ErrNotFloat   = errors.New("value is not a valid finite float")
+	ErrOverflow   = errors.New("increment or decrement would overflow")
+
+	// errUnchanged is returned by an updateTyped callback that left the value
+	// as it was, so the store is not marked for another snapshot.
+	errUnchanged = errors.New("typed value unchanged")
+)
+
+type ValueType uint8
+
+const (
+	TypeString ValueType = iota
+	TypeHash
+	TypeList
+	TypeSet
+	TypeZSet
+	TypeStream
+	TypeJSON
+	TypeHLL
+	TypeBloom
+	TypeLock
+	TypeRateLimit
+	TypeQueue
+)
+
+type typedValue interface {
+	valueType() ValueType
+	snapshot() any
+}
+
+type typeCodec struct {
+	name   string
+	decode func(raw json.RawMessage) (typedValue, error)
+}
+
+var codecs = map[ValueType]typeCodec{}
+
+func registerType(t ValueType, name string, decode func(raw json.RawMessage) (typedValue, error)) {
+	codecs[t] = typeCodec{name: name, decode: decode}
+}
+
+func (t ValueType) String() string {
+	if t == TypeString {
+		return "string"
+	}
+	if c, ok := codecs[t]; ok {
+		return c.name
+	}
+
+	return "unknown"
+}
+
+type typedRecord struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+func TypeOf(key string) (ValueType, bool) {
+	if !Exists(key) {
+		return 0, false
+	}
+
+	sh := mainStore.shards[mainStore.shardIndex(key)]
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+
+	if v, ok := sh.typed[key]; ok {
+		return v.valueType(), true
+	}
+	if _, ok := sh.m[key]; ok {
+		return TypeString, true
+	}
+
+	return 0, false
+}
+
+func updateTyped[T typedValue](key string, t ValueType, create func() T, fn func(v T) (remove bool, err error)) error {
+	if KeyHasExpired(key) {
+		expireKey(key)
+	}
+
+	sh := mainStore.shards[mainStore.shardIndex(key)]
+	sh.mu.Lock()
+
+	if _, ok := sh.m[key]; ok {
+		sh.mu.Unlock()
+		return ErrWrongType
+	}
+
+	current, existed := sh.typed[key]
+	if existed && current.valueType() != t {
+		sh.mu.Unlock()
+		return ErrWrongType
+	}
+
+	var v T
+	if existed {
+		v = current.(T)
+	} else if create != nil {
+		v = create()
+	} else {
+		sh.mu.Unlock()
+		return nil
+	}
+
+	remove, err := fn(v)
+	if errors.Is(err, errUnchanged) || (err == nil && remove && !existed) {
+		sh.mu.Unlock()
+		return nil
+	}
+	if err != nil {
+		sh.mu.Unlock()
+		return err
+	}
+
+	// An emptied value goes the way of a DEL, dropping everything attached to
+	// the key and its deadline before another writer can recreate it.
+	var stored, hadTTL bool
+	if remove {
+		mainStore.delLocked(sh, key)
+		hadTTL = expirationContainer.del(key)
+	} else {
+		sh.typed[key] = v
+		stored = true
+	}
+	sh.mu.Unlock()
+
+	mainStore.saved.Store(false)
+
+	statsEnabled := globals.GetConfig().Stats.Enabled
+
+	if existed && !stored {
+		if statsEnabled {
+			stat.Stats.DecrementKeysCount()
+			if hadTTL {
+				stat.Stats.DecrementExpirationKeysCount()
+			}
+		}
+	}
+
+	if statsEnabled && !existed && stored {
+		stat.Stats.IncrementKeysCount()
+	}
+
+	return nil
+}
+
+func viewTyped[T typedValue](key string, t ValueType, fn func(v T) error) (bool, error) {
+	if KeyHasExpired(key) {
+		expireKey(key)
+		return false, nil
+	}
+
+	sh := mainStore.shards[mainStore.shardIndex(key)]
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+
+	if _, ok := sh.m[key]; ok {
+		return false, ErrWrongType
+	}
+
+	current, ok := sh.typed[key]
+	if !ok {
+		return false, nil
+	}
+	if current.valueType() != t {
+		return false, ErrWrongType
+	}
+
+	return true, fn(current.(T))
+}
+
+func (s *Store) typedToMap() (map[string]typedRecord, error) {
+	out := make(map[string]typedRecord)
+
+	for i := 0; i < s.shardCount; i++ {
+		sh := s.shards[i]
+		sh.mu.RLock()
+		for k, v := range sh.typed {
+			raw, err := json.Marshal(v.snapshot())
+			if err != nil {
+				sh.mu.RUnlock()
+				return nil, fmt.Errorf("encoding %s: %w", k, err)
+			}
+			out[k] = typedRecord{Type: v.valueType().String(), Value: raw}
+		}
+		sh.mu.RUnlock()
+	}
+
+	return out, nil
+}
+
+func (s *Store) typedFromMap(records map[string]typedRecord) error {
+	byName := make(map[string]typeCodec, len(codecs))
+	for _, c := range codecs {
+		byName[c.name] = c
+	}
+
+	for k, rec := range records {
+		codec, ok := byName[rec.Type]
+		if !ok {
+			return fmt.Errorf("key %s: unknown value type %q", k, rec.Type)
+		}
+
+		v, err := codec.decode(rec.Value)
+		if err != nil {
+			return fmt.Errorf("key %s: %w", k, err)
+		}
+
+		sh := s.shards[s.shardIndex(k)]
+		sh.mu.Lock()
+		sh.typed[k] = v
+		sh.mu.Unlock()
+	}
+
+	return nil
+}
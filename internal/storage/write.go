@@ -2,86 +2,171 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
+	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/encryption"
 	"github.com/taymour/elysiandb/internal/globals"
 	"github.com/taymour/elysiandb/internal/log"
+	"github.com/taymour/elysiandb/internal/stat"
 )
 
-func WriteToDB() {
+// writeMu serializes snapshots: the saver, SAVE and the final flush on
+// shutdown share the same temp files, and a flush that finds the store
+// already marked saved must not return before that write has landed.
+var writeMu sync.Mutex
+
+func WriteToDB() error {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
 	cfg := globals.GetConfig()
 
+	CleanAllPastKeys()
+
 	rootMu.RLock()
 	ms := mainStore
 	ec := expirationContainer
 	rootMu.RUnlock()
 
-	if err := writeStoreToFile(cfg, DataFile, ms); err != nil {
-		log.Error("Error writing main store to database:", err)
+	start := time.Now()
+
+	storeSize, storeErr := writeStoreToFile(cfg, DataFile, ms)
+	if storeErr != nil {
+		log.Error("Error writing main store to database:", storeErr)
 	}
 
-	if err := writeExpirationsToFile(cfg, ExpirationDataFile, ec); err != nil {
-		log.Error("Error writing expiration store to database:", err)
+	expSize, expErr := writeExpirationsToFile(cfg, ExpirationDataFile, ec)
+	if expErr != nil {
+		log.Error("Error writing expiration store to database:", expErr)
 	}
-}
 
-func writeExpirationsToFile(cfg *configuration.Config, fileName string, expirationContainer *ExpirationContainer) error {
-	if expirationContainer.saved.Load() {
-		return nil
+	indexSize, indexErr := writeIndexesToFile(cfg, IndexDataFile, ms.indexes)
+	if indexErr != nil {
+		log.Error("Error writing index definitions to database:", indexErr)
 	}
 
-	isSuccess := true
+	tagSize, tagErr := writeTagsToFile(cfg, TagDataFile, ms.tags)
+	if tagErr != nil {
+		log.Error("Error writing tags to database:", tagErr)
+	}
+
+	softSize, softErr := writeSoftTTLsToFile(cfg, SoftTTLDataFile, ms.soft)
+	if softErr != nil {
+		log.Error("Error writing soft TTLs to database:", softErr)
+	}
+
+	if err := errors.Join(storeErr, expErr, indexErr, tagErr, softErr); err != nil {
+		return err
+	}
+
+	written := storeSize + expSize + indexSize + tagSize + softSize
+	if cfg.Stats.Enabled && written > 0 {
+		stat.Stats.ObserveSnapshot(time.Since(start), int64(written))
+	}
+
+	return nil
+}
+
+func writeExpirationsToFile(cfg *configuration.Config, fileName string, expirationContainer *ExpirationContainer) (int, error) {
+	if expirationContainer.saved.Swap(true) {
+		return 0, nil
+	}
 
 	path := cfg.Store.Folder + "/" + fileName
 
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	expirationsAsMap := expirationContainer.ToMap()
+
+	n, err := writeJSONFile(path, expirationsAsMap)
 	if err != nil {
-		isSuccess = false
-		log.Error("Error opening file:", err)
+		expirationContainer.saved.Store(false)
 	}
-	defer file.Close()
 
-	encoder := json.NewEncoder(file)
+	return n, err
+}
 
-	expirationsAsMap := expirationContainer.ToMap()
+func writeStoreToFile(cfg *configuration.Config, fileName string, store *Store) (int, error) {
+	if store.saved.Swap(true) {
+		return 0, nil
+	}
 
-	if err := encoder.Encode(expirationsAsMap); err != nil {
-		isSuccess = false
-		log.Error("Error encoding JSON:", err)
+	path := cfg.Store.Folder + "/" + fileName
+
+	storeAsMap := store.ToMap()
+
+	n, err := writeJSONFile(path, storeAsMap)
+	if err != nil {
+		store.saved.Store(false)
+		return n, err
 	}
 
-	expirationContainer.saved.Store(isSuccess)
+	typed, err := store.typedToMap()
+	if err != nil {
+		store.saved.Store(false)
+		return n, err
+	}
 
-	return nil
+	m, err := writeJSONFile(cfg.Store.Folder+"/"+TypedDataFile, typed)
+	if err != nil {
+		store.saved.Store(false)
+	}
+
+	return n + m, err
 }
 
-func writeStoreToFile(cfg *configuration.Config, fileName string, store *Store) error {
-	if store.saved.Load() {
-		return nil
+func writeJSONFile(path string, v any) (int, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0, err
 	}
 
-	isSuccess := true
+	data, err = encryption.Seal(data)
+	if err != nil {
+		return 0, err
+	}
 
-	path := cfg.Store.Folder + "/" + fileName
+	if err := writeFileDurably(path, data); err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
 
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+func writeFileDurably(path string, data []byte) error {
+	tmp := path + ".tmp"
+
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
 	if err != nil {
-		isSuccess = false
-		log.Error("Error opening file:", err)
+		return err
 	}
-	defer file.Close()
 
-	encoder := json.NewEncoder(file)
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
 
-	storeAsMap := store.ToMap()
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
 
-	if err := encoder.Encode(storeAsMap); err != nil {
-		isSuccess = false
-		log.Error("Error encoding JSON:", err)
+	if err := f.Close(); err != nil {
+		return err
 	}
 
-	store.saved.Store(isSuccess)
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
 
-	return nil
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
 }
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/skiplist"
+)
+
+type zsetValue struct {
+	scores map[string]float64
+	list   *skiplist.List
+}
+
+func init() {
+	registerType(TypeZSet, "zset", func(raw json.RawMessage) (typedValue, error) {
+		var scores map[string]float64
+		if err := json.Unmarshal(raw, &scores); err != nil {
+			return nil, err
+		}
+
+		z := newZSet()
+		for m, s := range scores {
+			z.set(m, s)
+		}
+		return z, nil
+	})
+}
+
+func newZSet() *zsetValue {
+	return &zsetValue{scores: make(map[string]float64), list: skiplist.New()}
+}
+
+func (z *zsetValue) valueType() ValueType { return TypeZSet }
+func (z *zsetValue) snapshot() any        { return z.scores }
+
+func (z *zsetValue) set(member string, score float64) bool {
+	old, existed := z.scores[member]
+	if existed {
+		if old == score {
+			return false
+		}
+		z.list.Delete(member, old)
+	}
+
+	z.scores[member] = score
+	z.list.Insert(member, score)
+
+	return !existed
+}
+
+type ScoredMember = skiplist.Element
+
+type ScoreRange = skiplist.ScoreRange
+
+func ParseScoreBound(s string) (float64, bool, bool) {
+	exclusive := strings.HasPrefix(s, "(")
+	if exclusive {
+		s = s[1:]
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) {
+		return 0, false, false
+	}
+
+	return v, exclusive, true
+}
+
+func validScore(score float64) bool {
+	return !math.IsNaN(score) && !math.IsInf(score, 0)
+}
+
+func ZAdd(key string, members []ScoredMember) (int, error) {
+	cfg := globals.GetConfig()
+	for _, m := range members {
+		if !validScore(m.Score) {
+			return 0, ErrNotFloat
+		}
+		if err := checkSizeLimits(cfg, key, []byte(m.Member)); err != nil {
+			return 0, err
+		}
+	}
+
+	added := 0
+	err := updateTyped(key, TypeZSet, newZSet, func(z *zsetValue) (bool, error) {
+		for _, m := range members {
+			if z.set(m.Member, m.Score) {
+				added++
+			}
+		}
+		return len(z.scores) == 0, nil
+	})
+
+	return added, err
+}
+
+func ZIncrBy(key string, member string, delta float64) (float64, error) {
+	if err := checkSizeLimits(globals.GetConfig(), key, []byte(member)); err != nil {
+		return 0, err
+	}
+
+	var score float64
+	err := updateTyped(key, TypeZSet, newZSet, func(z *zsetValue) (bool, error) {
+		score = z.scores[member] + delta
+		if !validScore(score) {
+			return len(z.scores) == 0, ErrNotFloat
+		}
+
+		z.set(member, score)
+		return false, nil
+	})
+
+	return score, err
+}
+
+func ZRem(key string, members []string) (int, error) {
+	removed := 0
+
+	err := updateTyped(key, TypeZSet, nil, func(z *zsetValue) (bool, error) {
+		for _, m := range members {
+			if score, ok := z.scores[m]; ok {
+				z.list.Delete(m, score)
+				delete(z.scores, m)
+				removed++
+			}
+		}
+		if removed == 0 {
+			return false, errUnchanged
+		}
+		return len(z.scores) == 0, nil
+	})
+
+	return removed, err
+}
+
+func ZRange(key string, start, stop int) ([]ScoredMember, bool, error) {
+	var out []ScoredMember
+
+	found, err := viewTyped(key, TypeZSet, func(z *zsetValue) error {
+		n := z.list.Len()
+		if start < 0 {
+			start = max(n+start, 0)
+		}
+		if stop < 0 {
+			stop = n + stop
+		}
+		out = z.list.Range(start, stop)
+		return nil
+	})
+
+	if out == nil {
+		out = []ScoredMember{}
+	}
+
+	return out, found, err
+}
+
+func ZRangeByScore(key string, r ScoreRange, offset, count int) ([]ScoredMember, bool, error) {
+	var out []ScoredMember
+
+	found, err := viewTyped(key, TypeZSet, func(z *zsetValue) error {
+		out = z.list.RangeByScore(r, offset, count)
+		return nil
+	})
+
+	if out == nil {
+		out = []ScoredMember{}
+	}
+
+	return out, found, err
+}
+
+func ZRank(key string, member string) (int, float64, bool, error) {
+	var rank int
+	var score float64
+	var ok bool
+
+	_, err := viewTyped(key, TypeZSet, func(z *zsetValue) error {
+		if score, ok = z.scores[member]; ok {
+			rank, ok = z.list.Rank(member, score)
+		}
+		return nil
+	})
+
+	return rank, score, ok, err
+}
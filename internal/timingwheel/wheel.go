@@ -0,0 +1,264 @@
+package timingwheel
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	slotBits  = 8
+	slotCount = 1 << slotBits
+	slotMask  = slotCount - 1
+	levels    = 4
+)
+
+type entry[K comparable] struct {
+	key      K
+	deadline uint64
+	prev     *entry[K]
+	next     *entry[K]
+	list     *list[K]
+}
+
+type list[K comparable] struct {
+	root entry[K]
+	len  int
+	due  bool
+}
+
+func newList[K comparable]() *list[K] {
+	l := &list[K]{}
+	l.root.next = &l.root
+	l.root.prev = &l.root
+
+	return l
+}
+
+func (l *list[K]) push(e *entry[K]) {
+	e.prev = l.root.prev
+	e.next = &l.root
+	l.root.prev.next = e
+	l.root.prev = e
+	e.list = l
+	l.len++
+}
+
+func (l *list[K]) remove(e *entry[K]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev, e.next, e.list = nil, nil, nil
+	l.len--
+}
+
+func (l *list[K]) popFront() *entry[K] {
+	if l.len == 0 {
+		return nil
+	}
+
+	e := l.root.next
+	l.remove(e)
+
+	return e
+}
+
+type Wheel[K comparable] struct {
+	mu         sync.Mutex
+	tick       time.Duration
+	start      time.Time
+	current    uint64
+	slots      [levels][slotCount]*list[K]
+	overflow   *list[K]
+	ready      []*list[K]
+	readyCount int
+	entries    map[K]*entry[K]
+}
+
+func New[K comparable](tick time.Duration, start time.Time) *Wheel[K] {
+	w := &Wheel[K]{tick: tick, start: start}
+	w.init()
+
+	return w
+}
+
+func (w *Wheel[K]) init() {
+	for l := range w.slots {
+		for s := range w.slots[l] {
+			w.slots[l][s] = newList[K]()
+		}
+	}
+	w.overflow = newList[K]()
+	w.ready = nil
+	w.readyCount = 0
+	w.entries = make(map[K]*entry[K])
+}
+
+func (w *Wheel[K]) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.entries)
+}
+
+func (w *Wheel[K]) Schedule(key K, deadline time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if e, ok := w.entries[key]; ok {
+		w.unlink(e)
+	}
+
+	e := &entry[K]{key: key, deadline: w.ticksAt(deadline, true)}
+	w.entries[key] = e
+	w.place(e)
+}
+
+func (w *Wheel[K]) Remove(key K) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	e, ok := w.entries[key]
+	if !ok {
+		return false
+	}
+
+	w.unlink(e)
+
+	return true
+}
+
+func (w *Wheel[K]) Advance(now time.Time, max int) []K {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	target := w.ticksAt(now, false)
+
+	if len(w.entries) == w.readyCount {
+		if target >= w.current {
+			w.current = target + 1
+		}
+	} else {
+		for w.current <= target {
+			w.process(w.current)
+			w.current++
+		}
+	}
+
+	n := w.readyCount
+	if max > 0 && n > max {
+		n = max
+	}
+
+	out := make([]K, 0, n)
+	for len(out) < n {
+		l := w.ready[0]
+		e := l.popFront()
+		if e == nil {
+			w.ready[0] = nil
+			w.ready = w.ready[1:]
+			continue
+		}
+
+		w.readyCount--
+		delete(w.entries, e.key)
+		out = append(out, e.key)
+	}
+
+	for len(w.ready) > 0 && w.ready[0].len == 0 {
+		w.ready[0] = nil
+		w.ready = w.ready[1:]
+	}
+
+	return out
+}
+
+func (w *Wheel[K]) Reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.init()
+}
+
+func (w *Wheel[K]) unlink(e *entry[K]) {
+	if e.list.due {
+		w.readyCount--
+	}
+	e.list.remove(e)
+	delete(w.entries, e.key)
+}
+
+func (w *Wheel[K]) ticksAt(t time.Time, roundUp bool) uint64 {
+	d := t.Sub(w.start)
+	if d <= 0 {
+		return 0
+	}
+
+	ticks := uint64(d / w.tick)
+	if roundUp && d%w.tick != 0 {
+		ticks++
+	}
+
+	return ticks
+}
+
+func (w *Wheel[K]) place(e *entry[K]) {
+	if e.deadline < w.current {
+		w.pushReady(e)
+		return
+	}
+
+	diff := e.deadline - w.current
+	for l := 0; l < levels; l++ {
+		if diff < 1<<(slotBits*(l+1)) {
+			w.slots[l][(e.deadline>>(slotBits*l))&slotMask].push(e)
+			return
+		}
+	}
+
+	w.overflow.push(e)
+}
+
+func (w *Wheel[K]) pushReady(e *entry[K]) {
+	if len(w.ready) == 0 {
+		l := newList[K]()
+		l.due = true
+		w.ready = append(w.ready, l)
+	}
+
+	w.ready[len(w.ready)-1].push(e)
+	w.readyCount++
+}
+
+func (w *Wheel[K]) process(t uint64) {
+	for l := 1; l < levels; l++ {
+		if t&(1<<(slotBits*l)-1) != 0 {
+			break
+		}
+		w.cascade(&w.slots[l][(t>>(slotBits*l))&slotMask])
+	}
+
+	if t&(1<<(slotBits*levels)-1) == 0 {
+		w.cascade(&w.overflow)
+	}
+
+	slot := w.slots[0][t&slotMask]
+	if slot.len == 0 {
+		return
+	}
+
+	slot.due = true
+	w.ready = append(w.ready, slot)
+	w.readyCount += slot.len
+	w.slots[0][t&slotMask] = newList[K]()
+}
+
+func (w *Wheel[K]) cascade(slot **list[K]) {
+	l := *slot
+	if l.len == 0 {
+		return
+	}
+
+	*slot = newList[K]()
+	for e := l.popFront(); e != nil; e = l.popFront() {
+		w.place(e)
+	}
+}
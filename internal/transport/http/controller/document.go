@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+func GetDocumentController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	path := "$"
+	if ctx.QueryArgs().Has("path") {
+		path = string(ctx.QueryArgs().Peek("path"))
+	}
+
+	doc, found, err := storage.GetDocument(pathValue(ctx, "key"), path)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+	if !found {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	ctx.SetContentType("application/json")
+	_, _ = ctx.Write(doc)
+}
+
+func PutDocumentController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	path := "$"
+	if ctx.QueryArgs().Has("path") {
+		path = string(ctx.QueryArgs().Peek("path"))
+	}
+
+	if err := storage.SetDocumentPath(pathValue(ctx, "key"), path, ctx.PostBody()); err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusNoContent)
+}
+
+func PatchDocumentController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	doc, err := storage.PatchDocument(pathValue(ctx, "key"), ctx.PostBody(), patchKind(ctx))
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	ctx.SetContentType("application/json")
+	_, _ = ctx.Write(doc)
+}
+
+func patchKind(ctx *fasthttp.RequestCtx) storage.PatchKind {
+	contentType := ctx.Request.Header.ContentType()
+
+	switch {
+	case bytes.HasPrefix(contentType, []byte("application/json-patch+json")):
+		return storage.JSONPatch
+	case bytes.HasPrefix(contentType, []byte("application/merge-patch+json")):
+		return storage.MergePatch
+	}
+
+	if body := bytes.TrimSpace(ctx.PostBody()); len(body) > 0 && body[0] == '[' {
+		return storage.JSONPatch
+	}
+
+	return storage.MergePatch
+}
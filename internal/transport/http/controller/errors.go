@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/taymour/elysiandb/internal/bloom"
+	"github.com/taymour/elysiandb/internal/geo"
+	"github.com/taymour/elysiandb/internal/jsondoc"
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+func writeStorageError(ctx *fasthttp.RequestCtx, err error) {
+	switch {
+	case errors.Is(err, storage.ErrWrongType):
+		ctx.Error(err.Error(), http.StatusConflict)
+	case errors.Is(err, storage.ErrKeyTooLarge), errors.Is(err, storage.ErrValueTooLarge):
+		ctx.Error(err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, storage.ErrNotInteger), errors.Is(err, storage.ErrNotFloat), errors.Is(err, storage.ErrOverflow),
+		errors.Is(err, storage.ErrInvalidStreamID), errors.Is(err, storage.ErrStreamIDTooSmall):
+		ctx.Error(err.Error(), http.StatusBadRequest)
+	case errors.Is(err, storage.ErrInvalidIndex), errors.Is(err, storage.ErrInvalidIndexQuery),
+		errors.Is(err, bloom.ErrInvalidParams), errors.Is(err, geo.ErrInvalidCoordinates),
+		errors.Is(err, storage.ErrInvalidRateLimit), errors.Is(err, storage.ErrInvalidQueueArg):
+		ctx.Error(err.Error(), http.StatusBadRequest)
+	case errors.Is(err, storage.ErrNotLockOwner):
+		ctx.Error(err.Error(), http.StatusForbidden)
+	case errors.Is(err, storage.ErrNoSuchStream), errors.Is(err, storage.ErrNoGroup), errors.Is(err, storage.ErrNoSuchIndex),
+		errors.Is(err, storage.ErrNoSuchMember), errors.Is(err, storage.ErrNoSuchJob):
+		ctx.Error(err.Error(), http.StatusNotFound)
+	case errors.Is(err, jsondoc.ErrInvalidJSON), errors.Is(err, jsondoc.ErrInvalidPath), errors.Is(err, jsondoc.ErrInvalidPatch):
+		ctx.Error(err.Error(), http.StatusBadRequest)
+	case errors.Is(err, jsondoc.ErrPathNotFound):
+		ctx.Error(err.Error(), http.StatusNotFound)
+	case errors.Is(err, storage.ErrGroupExists), errors.Is(err, storage.ErrIndexExists), errors.Is(err, storage.ErrKeyExists),
+		errors.Is(err, jsondoc.ErrTestFailed), errors.Is(err, storage.ErrInvalidLease):
+		ctx.Error(err.Error(), http.StatusConflict)
+	default:
+		ctx.Error(err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(ctx *fasthttp.RequestCtx, v any) {
+	jsonData, err := json.Marshal(v)
+	if err != nil {
+		ctx.Error(err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ctx.SetContentType("application/json")
+	_, _ = ctx.Write(jsonData)
+}
+
+func pathValue(ctx *fasthttp.RequestCtx, name string) string {
+	v, _ := ctx.UserValue(name).(string)
+	if dec, err := url.PathUnescape(v); err == nil {
+		v = dec
+	}
+
+	return v
+}
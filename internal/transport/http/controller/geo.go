@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/taymour/elysiandb/internal/geo"
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type geoPointBody struct {
+	Member string  `json:"member"`
+	Lon    float64 `json:"lon"`
+	Lat    float64 `json:"lat"`
+}
+
+type geoMatchBody struct {
+	Member   string  `json:"member"`
+	Distance float64 `json:"distance"`
+	Lon      float64 `json:"lon"`
+	Lat      float64 `json:"lat"`
+}
+
+func PutGeoController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	var body []geoPointBody
+	if err := json.Unmarshal(ctx.PostBody(), &body); err != nil || len(body) == 0 {
+		ctx.Error(`body must be a non-empty JSON array of {"member","lon","lat"}`, http.StatusBadRequest)
+		return
+	}
+
+	points := make([]storage.GeoPoint, len(body))
+	for i, p := range body {
+		points[i] = storage.GeoPoint{Member: p.Member, Lon: p.Lon, Lat: p.Lat}
+	}
+
+	added, err := storage.GeoAdd(pathValue(ctx, "key"), points)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]int{"added": added})
+}
+
+func GeoPosController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	raw := string(ctx.QueryArgs().Peek("members"))
+	if raw == "" {
+		ctx.Error("members query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	points, err := storage.GeoPos(pathValue(ctx, "key"), strings.Split(raw, ","))
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	out := make([]*geoPointBody, len(points))
+	for i, p := range points {
+		if p != nil {
+			out[i] = &geoPointBody{Member: p.Member, Lon: p.Lon, Lat: p.Lat}
+		}
+	}
+
+	writeJSON(ctx, out)
+}
+
+func GeoDistController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	args := ctx.QueryArgs()
+
+	unitName, unit, ok := geoUnitArg(ctx)
+	if !ok {
+		return
+	}
+
+	dist, err := storage.GeoDist(pathValue(ctx, "key"), string(args.Peek("from")), string(args.Peek("to")))
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]any{"distance": dist / unit, "unit": unitName})
+}
+
+func GeoSearchController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	args := ctx.QueryArgs()
+
+	_, unit, ok := geoUnitArg(ctx)
+	if !ok {
+		return
+	}
+
+	var q storage.GeoQuery
+	if args.Has("member") {
+		q.Member = string(args.Peek("member"))
+	} else {
+		lon, errLon := strconv.ParseFloat(string(args.Peek("lon")), 64)
+		lat, errLat := strconv.ParseFloat(string(args.Peek("lat")), 64)
+		if errLon != nil || errLat != nil {
+			ctx.Error("member or lon and lat query parameters are required", http.StatusBadRequest)
+			return
+		}
+		q.Lon, q.Lat = lon, lat
+	}
+
+	if args.Has("radius") {
+		radius, err := strconv.ParseFloat(string(args.Peek("radius")), 64)
+		if err != nil || radius < 0 {
+			ctx.Error("radius must be a non-negative number", http.StatusBadRequest)
+			return
+		}
+		q.Radius = radius * unit
+	} else {
+		width, errW := strconv.ParseFloat(string(args.Peek("width")), 64)
+		height, errH := strconv.ParseFloat(string(args.Peek("height")), 64)
+		if errW != nil || errH != nil || width <= 0 || height <= 0 {
+			ctx.Error("radius or width and height query parameters are required", http.StatusBadRequest)
+			return
+		}
+		q.Width, q.Height = width*unit, height*unit
+	}
+
+	switch strings.ToLower(string(args.Peek("order"))) {
+	case "", "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		ctx.Error("order must be asc or desc", http.StatusBadRequest)
+		return
+	}
+
+	if q.Count, ok = intQueryArg(ctx, "count", 0); !ok {
+		return
+	}
+
+	matches, err := storage.GeoSearch(pathValue(ctx, "key"), q)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	out := make([]geoMatchBody, len(matches))
+	for i, m := range matches {
+		out[i] = geoMatchBody{Member: m.Member, Distance: m.Dist / unit, Lon: m.Lon, Lat: m.Lat}
+	}
+
+	writeJSON(ctx, out)
+}
+
+func geoUnitArg(ctx *fasthttp.RequestCtx) (string, float64, bool) {
+	name := "m"
+	if ctx.QueryArgs().Has("unit") {
+		name = strings.ToLower(string(ctx.QueryArgs().Peek("unit")))
+	}
+
+	unit, ok := geo.UnitFactor(name)
+	if !ok {
+		ctx.Error("unit must be one of m, km, mi, ft", http.StatusBadRequest)
+		return "", 0, false
+	}
+
+	return name, unit, true
+}
@@ -2,8 +2,11 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/taymour/elysiandb/internal/globals"
 	"github.com/taymour/elysiandb/internal/stat"
@@ -13,8 +16,9 @@ import (
 )
 
 type getEntry struct {
-	Key string  `json:"key"`
-	Val *string `json:"value"`
+	Key   string  `json:"key"`
+	Val   *string `json:"value"`
+	Lease string  `json:"lease,omitempty"`
 }
 
 func GetKeyController(ctx *fasthttp.RequestCtx) {
@@ -40,32 +44,37 @@ func GetKeyController(ctx *fasthttp.RequestCtx) {
 
 func handleSingleKey(key string, ctx *fasthttp.RequestCtx) {
 	cfg := globals.GetConfig()
-	if storage.KeyHasExpired(key) {
-		storage.DeleteByKey(key)
+	data, stale, err := storage.GetByKeyWithStale(key)
+	if errors.Is(err, storage.ErrWrongType) {
+		ctx.Error(err.Error(), http.StatusConflict)
+		return
+	}
 
-		if cfg.Stats.Enabled {
-			stat.Stats.IncrementMisses()
+	var lease string
+	if (err != nil || stale) && ctx.QueryArgs().Has("lease_ms") {
+		res, ok := requestLease(key, ctx)
+		if !ok {
+			return
 		}
+		if res.Found {
+			data, stale, err = res.Value, res.Stale, nil
+		}
+		lease = res.Token
+	}
 
-		jsonData, _ := json.Marshal(getEntry{
-			Key: key,
-			Val: nil,
-		})
-		_, _ = ctx.Write(jsonData)
-		ctx.SetStatusCode(http.StatusNotFound)
-
-		return
+	if lease != "" {
+		ctx.Response.Header.Set("X-Elysian-Lease", lease)
 	}
 
-	data, err := storage.GetByKey(key)
 	if err != nil {
 		if cfg.Stats.Enabled {
 			stat.Stats.IncrementMisses()
 		}
 
 		jsonData, _ := json.Marshal(getEntry{
-			Key: key,
-			Val: nil,
+			Key:   key,
+			Val:   nil,
+			Lease: lease,
 		})
 		_, _ = ctx.Write(jsonData)
 		ctx.SetStatusCode(http.StatusNotFound)
@@ -77,15 +86,41 @@ func handleSingleKey(key string, ctx *fasthttp.RequestCtx) {
 		stat.Stats.IncrementHits()
 	}
 
+	if stale {
+		ctx.Response.Header.Set("X-Elysian-Stale", "1")
+	}
+
 	valStr := string(data)
 	jsonData, _ := json.Marshal(getEntry{
-		Key: key,
-		Val: &valStr,
+		Key:   key,
+		Val:   &valStr,
+		Lease: lease,
 	})
 
 	_, _ = ctx.Write(jsonData)
 }
 
+func requestLease(key string, ctx *fasthttp.RequestCtx) (storage.LeaseResult, bool) {
+	ms, err := strconv.ParseInt(string(ctx.QueryArgs().Peek("lease_ms")), 10, 64)
+	if err != nil || ms <= 0 {
+		ctx.Error("lease_ms must be a positive integer", http.StatusBadRequest)
+		return storage.LeaseResult{}, false
+	}
+
+	wait, timeout, ok := longPollArgs(ctx)
+	if !ok {
+		return storage.LeaseResult{}, false
+	}
+
+	res, err := storage.GetLease(key, time.Duration(ms)*time.Millisecond, wait, timeout, ctx.Done())
+	if err != nil {
+		writeStorageError(ctx, err)
+		return storage.LeaseResult{}, false
+	}
+
+	return res, true
+}
+
 func handleWildcardKey(key string, ctx *fasthttp.RequestCtx) {
 	cfg := globals.GetConfig()
 	var results = make([]multiGetEntry, 0)
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/stat"
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type hashFieldEntry struct {
+	Field string  `json:"field"`
+	Val   *string `json:"value"`
+}
+
+func GetHashController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	key := pathValue(ctx, "key")
+
+	if ctx.QueryArgs().Has("fields") {
+		fields := strings.Split(string(ctx.QueryArgs().Peek("fields")), ",")
+		values, err := storage.HMGet(key, fields)
+		if err != nil {
+			writeStorageError(ctx, err)
+			return
+		}
+
+		out := make(map[string]*string, len(fields))
+		for i, f := range fields {
+			if values[i] != nil {
+				v := string(values[i])
+				out[f] = &v
+			} else {
+				out[f] = nil
+			}
+		}
+		writeJSON(ctx, out)
+		return
+	}
+
+	fields, found, err := storage.HGetAll(key)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+	if !found {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	out := make(map[string]string, len(fields))
+	for _, f := range fields {
+		out[f.Field] = string(f.Value)
+	}
+	writeJSON(ctx, out)
+}
+
+func PutHashController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	key := pathValue(ctx, "key")
+
+	var body map[string]string
+	if err := json.Unmarshal(ctx.PostBody(), &body); err != nil {
+		ctx.Error("body must be a JSON object of string fields", http.StatusBadRequest)
+		return
+	}
+
+	pairs := make([]storage.HashField, 0, len(body))
+	for f, v := range body {
+		pairs = append(pairs, storage.HashField{Field: f, Value: []byte(v)})
+	}
+
+	added, err := storage.HSet(key, pairs)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]int{"added": added})
+}
+
+func GetHashFieldController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	key, field := pathValue(ctx, "key"), pathValue(ctx, "field")
+
+	v, found, err := storage.HGet(key, field)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	if !found {
+		writeJSON(ctx, hashFieldEntry{Field: field})
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	val := string(v)
+	writeJSON(ctx, hashFieldEntry{Field: field, Val: &val})
+}
+
+func PutHashFieldController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	key, field := pathValue(ctx, "key"), pathValue(ctx, "field")
+
+	if _, err := storage.HSet(key, []storage.HashField{{Field: field, Value: ctx.PostBody()}}); err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusNoContent)
+}
+
+func DeleteHashFieldController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	key, field := pathValue(ctx, "key"), pathValue(ctx, "field")
+
+	removed, err := storage.HDel(key, []string{field})
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+	if removed == 0 {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusNoContent)
+}
+
+func IncrHashFieldController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	key, field := pathValue(ctx, "key"), pathValue(ctx, "field")
+
+	delta := int64(1)
+	if ctx.QueryArgs().Has("by") {
+		n, err := strconv.ParseInt(string(ctx.QueryArgs().Peek("by")), 10, 64)
+		if err != nil {
+			ctx.Error("by must be an integer", http.StatusBadRequest)
+			return
+		}
+		delta = n
+	}
+
+	n, err := storage.HIncrBy(key, field, delta)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]int64{"value": n})
+}
+
+func countHTTPRequest() {
+	if globals.GetConfig().Stats.Enabled {
+		stat.Stats.IncrementTotalRequests()
+	}
+}
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/stat"
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+func HeadKeyController(ctx *fasthttp.RequestCtx) {
+	if globals.GetConfig().Stats.Enabled {
+		stat.Stats.IncrementTotalRequests()
+	}
+
+	key := ctx.UserValue("key").(string)
+	if dec, err := url.PathUnescape(key); err == nil {
+		key = dec
+	}
+
+	if !storage.Exists(key) {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusOK)
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type indexMatchBody struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+func ListIndexesController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	writeJSON(ctx, storage.Indexes())
+}
+
+func CreateIndexController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	var def storage.IndexDefinition
+	if err := json.Unmarshal(ctx.PostBody(), &def); err != nil {
+		ctx.Error("body must be a JSON object with name, pattern and path", http.StatusBadRequest)
+		return
+	}
+
+	if err := storage.CreateIndex(def); err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusCreated)
+}
+
+func DeleteIndexController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	if !storage.DropIndex(pathValue(ctx, "name")) {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusNoContent)
+}
+
+func QueryController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	args := ctx.QueryArgs()
+
+	name := string(args.Peek("index"))
+	if name == "" {
+		ctx.Error("index query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	limit, ok := intQueryArg(ctx, "limit", 0)
+	if !ok {
+		return
+	}
+
+	q := storage.IndexQuery{Type: string(args.Peek("type")), Limit: limit}
+	if args.Has("eq") {
+		q.Min, q.HasMin = string(args.Peek("eq")), true
+		q.Max, q.HasMax = q.Min, true
+	}
+	if args.Has("gt") || args.Has("gte") {
+		q.MinExclusive = args.Has("gt")
+		q.Min, q.HasMin = string(args.Peek(boundArg(q.MinExclusive, "gt", "gte"))), true
+	}
+	if args.Has("lt") || args.Has("lte") {
+		q.MaxExclusive = args.Has("lt")
+		q.Max, q.HasMax = string(args.Peek(boundArg(q.MaxExclusive, "lt", "lte"))), true
+	}
+
+	matches, err := storage.QueryIndex(name, q)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	out := make([]indexMatchBody, len(matches))
+	for i, m := range matches {
+		out[i] = indexMatchBody{Key: m.Key, Value: m.Value}
+	}
+
+	writeJSON(ctx, out)
+}
+
+func boundArg(exclusive bool, strict, inclusive string) string {
+	if exclusive {
+		return strict
+	}
+
+	return inclusive
+}
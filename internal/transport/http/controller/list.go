@@ -0,0 +1,238 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+const BlockingUserValue = "blocking"
+
+type poppedEntry struct {
+	Key   string  `json:"key"`
+	Value *string `json:"value"`
+}
+
+func GetListController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	key := pathValue(ctx, "key")
+
+	start, ok := intQueryArg(ctx, "start", 0)
+	if !ok {
+		return
+	}
+	stop, ok := intQueryArg(ctx, "stop", -1)
+	if !ok {
+		return
+	}
+
+	values, found, err := storage.LRange(key, start, stop)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+	if !found {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	writeJSON(ctx, stringValues(values))
+}
+
+func ListLengthController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	n, err := storage.LLen(pathValue(ctx, "key"))
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]int{"length": n})
+}
+
+func LPushController(ctx *fasthttp.RequestCtx) {
+	pushController(ctx, true)
+}
+
+func RPushController(ctx *fasthttp.RequestCtx) {
+	pushController(ctx, false)
+}
+
+func LPopController(ctx *fasthttp.RequestCtx) {
+	popController(ctx, true)
+}
+
+func RPopController(ctx *fasthttp.RequestCtx) {
+	popController(ctx, false)
+}
+
+func TrimListController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	start, ok := intQueryArg(ctx, "start", 0)
+	if !ok {
+		return
+	}
+	stop, ok := intQueryArg(ctx, "stop", -1)
+	if !ok {
+		return
+	}
+
+	if err := storage.LTrim(pathValue(ctx, "key"), start, stop); err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusNoContent)
+}
+
+func pushController(ctx *fasthttp.RequestCtx, left bool) {
+	countHTTPRequest()
+	key := pathValue(ctx, "key")
+
+	var body []string
+	if err := json.Unmarshal(ctx.PostBody(), &body); err != nil || len(body) == 0 {
+		ctx.Error("body must be a non-empty JSON array of strings", http.StatusBadRequest)
+		return
+	}
+
+	values := make([][]byte, len(body))
+	for i, v := range body {
+		values[i] = []byte(v)
+	}
+
+	var n int
+	var err error
+	if left {
+		n, err = storage.LPush(key, values)
+	} else {
+		n, err = storage.RPush(key, values)
+	}
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]int{"length": n})
+}
+
+func popController(ctx *fasthttp.RequestCtx, left bool) {
+	countHTTPRequest()
+	key := pathValue(ctx, "key")
+	args := ctx.QueryArgs()
+
+	block, timeout, ok := longPollArgs(ctx)
+	if !ok {
+		return
+	}
+	if block {
+		waitPop(ctx, key, timeout, left)
+		return
+	}
+
+	count, ok := intQueryArg(ctx, "count", 1)
+	if !ok {
+		return
+	}
+	if count < 1 {
+		ctx.Error("count must be positive", http.StatusBadRequest)
+		return
+	}
+
+	var values [][]byte
+	var err error
+	if left {
+		values, err = storage.LPop(key, count)
+	} else {
+		values, err = storage.RPop(key, count)
+	}
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	if args.Has("count") {
+		if len(values) == 0 {
+			ctx.SetStatusCode(http.StatusNotFound)
+		}
+		writeJSON(ctx, map[string]any{"key": key, "values": stringValues(values)})
+		return
+	}
+
+	if len(values) == 0 {
+		writeJSON(ctx, poppedEntry{Key: key})
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	v := string(values[0])
+	writeJSON(ctx, poppedEntry{Key: key, Value: &v})
+}
+
+func waitPop(ctx *fasthttp.RequestCtx, key string, timeout time.Duration, left bool) {
+	var value []byte
+	var popped bool
+	var err error
+	if left {
+		_, value, popped, err = storage.BLPop([]string{key}, timeout, ctx.Done())
+	} else {
+		_, value, popped, err = storage.BRPop([]string{key}, timeout, ctx.Done())
+	}
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	if !popped {
+		writeJSON(ctx, poppedEntry{Key: key})
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	v := string(value)
+	writeJSON(ctx, poppedEntry{Key: key, Value: &v})
+}
+
+func longPollArgs(ctx *fasthttp.RequestCtx) (bool, time.Duration, bool) {
+	if !ctx.QueryArgs().Has("timeout_ms") {
+		return false, 0, true
+	}
+
+	ms, err := strconv.ParseInt(string(ctx.QueryArgs().Peek("timeout_ms")), 10, 64)
+	if err != nil || ms < 0 {
+		ctx.Error("timeout_ms must be a non-negative integer", http.StatusBadRequest)
+		return false, 0, false
+	}
+
+	ctx.SetUserValue(BlockingUserValue, true)
+
+	return true, time.Duration(ms) * time.Millisecond, true
+}
+
+func intQueryArg(ctx *fasthttp.RequestCtx, name string, def int) (int, bool) {
+	if !ctx.QueryArgs().Has(name) {
+		return def, true
+	}
+
+	n, err := strconv.Atoi(string(ctx.QueryArgs().Peek(name)))
+	if err != nil {
+		ctx.Error(name+" must be an integer", http.StatusBadRequest)
+		return 0, false
+	}
+
+	return n, true
+}
+
+func stringValues(values [][]byte) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = string(v)
+	}
+
+	return out
+}
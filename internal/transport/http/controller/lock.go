@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type lockBody struct {
+	Owner string `json:"owner,omitempty"`
+	Fence uint64 `json:"fence"`
+	TTLMs int64  `json:"ttl_ms"`
+}
+
+func AcquireLockController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	ttl, ok := lockTTLArg(ctx)
+	if !ok {
+		return
+	}
+	wait, timeout, ok := longPollArgs(ctx)
+	if !ok {
+		return
+	}
+
+	lock, acquired, err := storage.AcquireLock(pathValue(ctx, "name"), ttl, wait, timeout, ctx.Done())
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+	if !acquired {
+		ctx.Error("lock is held by another owner", http.StatusConflict)
+		return
+	}
+
+	writeJSON(ctx, lockBody{Owner: lock.Owner, Fence: lock.Fence, TTLMs: ttl.Milliseconds()})
+}
+
+func RenewLockController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	ttl, ok := lockTTLArg(ctx)
+	if !ok {
+		return
+	}
+
+	lock, err := storage.RenewLock(pathValue(ctx, "name"), string(ctx.QueryArgs().Peek("owner")), ttl)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, lockBody{Fence: lock.Fence, TTLMs: ttl.Milliseconds()})
+}
+
+func ReleaseLockController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	if err := storage.ReleaseLock(pathValue(ctx, "name"), string(ctx.QueryArgs().Peek("owner"))); err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusNoContent)
+}
+
+func lockTTLArg(ctx *fasthttp.RequestCtx) (time.Duration, bool) {
+	ms, err := strconv.ParseInt(string(ctx.QueryArgs().Peek("ttl_ms")), 10, 64)
+	if err != nil || ms <= 0 {
+		ctx.Error("ttl_ms must be a positive integer", http.StatusBadRequest)
+		return 0, false
+	}
+
+	return time.Duration(ms) * time.Millisecond, true
+}
@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/stat"
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+func MetricsController(ctx *fasthttp.RequestCtx) {
+	if !globals.GetConfig().Stats.Enabled {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	storage.ExpireDueKeys(statsExpiryBatch)
+
+	ctx.SetContentType("application/openmetrics-text; version=1.0.0; charset=utf-8")
+	_, _ = ctx.Write([]byte(stat.Stats.ToOpenMetrics()))
+}
@@ -73,17 +73,6 @@ func handleMGETSingleKey(key string, results *[]multiGetEntry, seen map[string]s
 
 	cfg := globals.GetConfig()
 
-	if storage.KeyHasExpired(key) {
-		storage.DeleteByKey(key)
-		*results = append(*results, multiGetEntry{Key: key, Val: nil})
-
-		if cfg.Stats.Enabled {
-			stat.Stats.IncrementMisses()
-		}
-
-		return
-	}
-
 	data, err := storage.GetByKey(key)
 	if err != nil {
 		*results = append(*results, multiGetEntry{Key: key, Val: nil})
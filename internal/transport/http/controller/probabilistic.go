@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+func AddHLLController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	var elements []string
+	if err := json.Unmarshal(ctx.PostBody(), &elements); err != nil {
+		ctx.Error("body must be a JSON array of strings", http.StatusBadRequest)
+		return
+	}
+
+	changed, err := storage.PFAdd(pathValue(ctx, "key"), elements)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]bool{"changed": changed})
+}
+
+func CountHLLController(ctx *fasthttp.RequestCtx) {
+	hllCountController(ctx, []string{pathValue(ctx, "key")})
+}
+
+func CountHLLUnionController(ctx *fasthttp.RequestCtx) {
+	raw := string(ctx.QueryArgs().Peek("keys"))
+	if raw == "" {
+		countHTTPRequest()
+		ctx.Error("keys query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	hllCountController(ctx, strings.Split(raw, ","))
+}
+
+func MergeHLLController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	raw := string(ctx.QueryArgs().Peek("keys"))
+	if raw == "" {
+		ctx.Error("keys query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := storage.PFMerge(pathValue(ctx, "key"), strings.Split(raw, ",")); err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusNoContent)
+}
+
+func hllCountController(ctx *fasthttp.RequestCtx, keys []string) {
+	countHTTPRequest()
+
+	n, err := storage.PFCount(keys)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]uint64{"count": n})
+}
+
+func ReserveBloomController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	args := ctx.QueryArgs()
+
+	errorRate, err := strconv.ParseFloat(string(args.Peek("error_rate")), 64)
+	if err != nil {
+		ctx.Error("error_rate must be a number", http.StatusBadRequest)
+		return
+	}
+	capacity, err := strconv.ParseUint(string(args.Peek("capacity")), 10, 64)
+	if err != nil {
+		ctx.Error("capacity must be a positive integer", http.StatusBadRequest)
+		return
+	}
+
+	if err := storage.BFReserve(pathValue(ctx, "key"), errorRate, capacity); err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusCreated)
+}
+
+func AddBloomItemController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	added, err := storage.BFAdd(pathValue(ctx, "key"), pathValue(ctx, "item"))
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]bool{"added": added})
+}
+
+func GetBloomItemController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	item := pathValue(ctx, "item")
+
+	exists, err := storage.BFExists(pathValue(ctx, "key"), item)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]any{"item": item, "exists": exists})
+}
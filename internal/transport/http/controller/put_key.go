@@ -1,7 +1,9 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/taymour/elysiandb/internal/globals"
 	"github.com/taymour/elysiandb/internal/stat"
@@ -15,19 +17,46 @@ func PutKeyController(ctx *fasthttp.RequestCtx) {
 	}
 
 	key := ctx.UserValue("key").(string)
-	ttl := ctx.QueryArgs().GetUintOrZero("ttl")
+	ttl := time.Duration(ctx.QueryArgs().GetUintOrZero("ttl")) * time.Second
+	if ctx.QueryArgs().Has("ttl_ms") {
+		ttl = time.Duration(ctx.QueryArgs().GetUintOrZero("ttl_ms")) * time.Millisecond
+	}
+	softTTL := time.Duration(ctx.QueryArgs().GetUintOrZero("soft_ttl")) * time.Second
+	if ctx.QueryArgs().Has("soft_ttl_ms") {
+		softTTL = time.Duration(ctx.QueryArgs().GetUintOrZero("soft_ttl_ms")) * time.Millisecond
+	}
 
 	body := ctx.PostBody()
 	buf := make([]byte, len(body))
 	copy(buf, body)
 
+	var tags []string
+	if ctx.QueryArgs().Has("tags") {
+		tags = storage.ParseTags(string(ctx.QueryArgs().Peek("tags")))
+	}
+
+	opts := storage.PutOptions{TTL: ttl, SoftTTL: softTTL, Tags: tags}
+
 	var err error
-	if ttl > 0 {
-		err = storage.PutKeyValueWithTTL(key, buf, ttl)
+	if ctx.QueryArgs().Has("lease") {
+		err = storage.PutKeyValueWithLease(key, buf, opts, string(ctx.QueryArgs().Peek("lease")))
+	} else if tags != nil || softTTL > 0 {
+		err = storage.PutKeyValueWithOptions(key, buf, opts)
+	} else if ttl > 0 {
+		err = storage.PutKeyValueWithTTLDuration(key, buf, ttl)
 	} else {
 		err = storage.PutKeyValue(key, buf)
 	}
 
+	if errors.Is(err, storage.ErrKeyTooLarge) || errors.Is(err, storage.ErrValueTooLarge) {
+		ctx.Error(err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if errors.Is(err, storage.ErrInvalidLease) {
+		ctx.Error(err.Error(), http.StatusConflict)
+		return
+	}
+
 	if err != nil {
 		ctx.Error("Failed to store key-value pair", http.StatusBadRequest)
 		return
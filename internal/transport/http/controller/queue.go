@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type jobBody struct {
+	ID          string `json:"id"`
+	Payload     string `json:"payload"`
+	Attempts    int    `json:"attempts"`
+	MaxAttempts int    `json:"max_attempts"`
+}
+
+type queueStatsBody struct {
+	Ready    int `json:"ready"`
+	Delayed  int `json:"delayed"`
+	Reserved int `json:"reserved"`
+	Dead     int `json:"dead"`
+}
+
+func EnqueueController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	args := ctx.QueryArgs()
+
+	delay, ok := durationQueryArg(ctx, "delay_ms")
+	if !ok {
+		return
+	}
+	runAt := time.Now().Add(delay)
+	if args.Has("run_at_ms") {
+		ms, err := strconv.ParseInt(string(args.Peek("run_at_ms")), 10, 64)
+		if err != nil {
+			ctx.Error("run_at_ms must be a unix timestamp in milliseconds", http.StatusBadRequest)
+			return
+		}
+		runAt = time.UnixMilli(ms)
+	}
+
+	attempts, ok := intQueryArg(ctx, "max_attempts", 0)
+	if !ok {
+		return
+	}
+
+	id, err := storage.Enqueue(pathValue(ctx, "name"), ctx.PostBody(), runAt, attempts)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusCreated)
+	writeJSON(ctx, map[string]string{"id": id})
+}
+
+func QueueInfoController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	stats, err := storage.QueueInfo(pathValue(ctx, "name"))
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, queueStatsBody{Ready: stats.Ready, Delayed: stats.Delayed, Reserved: stats.Reserved, Dead: stats.Dead})
+}
+
+func ReserveJobController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	visibility, ok := durationQueryArg(ctx, "visibility_ms")
+	if !ok {
+		return
+	}
+
+	job, reserved, err := storage.Reserve(pathValue(ctx, "name"), visibility)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+	if !reserved {
+		ctx.SetStatusCode(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(ctx, newJobBody(job))
+}
+
+func AckJobController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	writeQueueResult(ctx, storage.Ack(pathValue(ctx, "name"), pathValue(ctx, "id")))
+}
+
+func NackJobController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	delay, ok := durationQueryArg(ctx, "delay_ms")
+	if !ok {
+		return
+	}
+
+	writeQueueResult(ctx, storage.Nack(pathValue(ctx, "name"), pathValue(ctx, "id"), delay))
+}
+
+func TouchJobController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	visibility, ok := durationQueryArg(ctx, "visibility_ms")
+	if !ok {
+		return
+	}
+
+	writeQueueResult(ctx, storage.Touch(pathValue(ctx, "name"), pathValue(ctx, "id"), visibility))
+}
+
+func DeadJobsController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	jobs, err := storage.DeadJobs(pathValue(ctx, "name"))
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	out := make([]jobBody, len(jobs))
+	for i, j := range jobs {
+		out[i] = newJobBody(j)
+	}
+
+	writeJSON(ctx, out)
+}
+
+func writeQueueResult(ctx *fasthttp.RequestCtx, err error) {
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusNoContent)
+}
+
+func newJobBody(j storage.Job) jobBody {
+	return jobBody{ID: j.ID, Payload: string(j.Payload), Attempts: j.Attempts, MaxAttempts: j.MaxAttempts}
+}
+
+func durationQueryArg(ctx *fasthttp.RequestCtx, name string) (time.Duration, bool) {
+	if !ctx.QueryArgs().Has(name) {
+		return 0, true
+	}
+
+	ms, err := strconv.ParseInt(string(ctx.QueryArgs().Peek(name)), 10, 64)
+	if err != nil || ms < 0 {
+		ctx.Error(name+" must be a non-negative integer", http.StatusBadRequest)
+		return 0, false
+	}
+
+	return time.Duration(ms) * time.Millisecond, true
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type rateLimitBody struct {
+	Allowed      bool  `json:"allowed"`
+	Remaining    int64 `json:"remaining"`
+	RetryAfterMs int64 `json:"retry_after_ms"`
+}
+
+func RateLimitController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	args := ctx.QueryArgs()
+
+	capacity, err := strconv.ParseInt(string(args.Peek("capacity")), 10, 64)
+	if err != nil {
+		ctx.Error("capacity must be a positive integer", http.StatusBadRequest)
+		return
+	}
+	refill, err := strconv.ParseFloat(string(args.Peek("refill_per_sec")), 64)
+	if err != nil {
+		ctx.Error("refill_per_sec must be a number", http.StatusBadRequest)
+		return
+	}
+	cost, ok := intQueryArg(ctx, "cost", 1)
+	if !ok {
+		return
+	}
+
+	res, err := storage.RateLimit(pathValue(ctx, "key"), capacity, refill, int64(cost))
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	if !res.Allowed {
+		retryAfter := (res.RetryAfter.Milliseconds() + 999) / 1000
+		ctx.Response.Header.Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+		ctx.SetStatusCode(http.StatusTooManyRequests)
+	}
+
+	writeJSON(ctx, rateLimitBody{Allowed: res.Allowed, Remaining: res.Remaining, RetryAfterMs: res.RetryAfter.Milliseconds()})
+}
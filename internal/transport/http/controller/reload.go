@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/taymour/elysiandb/internal/reload"
+	"github.com/valyala/fasthttp"
+)
+
+func ReloadConfigController(ctx *fasthttp.RequestCtx) {
+	res, err := reload.Reload()
+	if err != nil {
+		ctx.Error(err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(res)
+	if err != nil {
+		ctx.Error(err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ctx.SetContentType("application/json")
+	_, _ = ctx.Write(jsonData)
+}
@@ -15,7 +15,11 @@ func SaveController(ctx *fasthttp.RequestCtx) {
 	}
 
 	ctx.SetContentType("application/json")
-	ctx.SetStatusCode(http.StatusNoContent)
 
-	storage.WriteToDB()
+	if err := storage.WriteToDB(); err != nil {
+		ctx.Error("Failed to save database", http.StatusInternalServerError)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusNoContent)
 }
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+func GetSetController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	members, found, err := storage.SMembers(pathValue(ctx, "key"))
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+	if !found {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	writeJSON(ctx, members)
+}
+
+func PutSetController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	var members []string
+	if err := json.Unmarshal(ctx.PostBody(), &members); err != nil {
+		ctx.Error("body must be a JSON array of strings", http.StatusBadRequest)
+		return
+	}
+
+	added, err := storage.SAdd(pathValue(ctx, "key"), members)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]int{"added": added})
+}
+
+func GetSetMemberController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	member := pathValue(ctx, "member")
+
+	ok, err := storage.SIsMember(pathValue(ctx, "key"), member)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]any{"member": member, "exists": ok})
+}
+
+func DeleteSetMemberController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	removed, err := storage.SRem(pathValue(ctx, "key"), []string{pathValue(ctx, "member")})
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+	if removed == 0 {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusNoContent)
+}
+
+func SetInterController(ctx *fasthttp.RequestCtx) {
+	setCombineController(ctx, storage.SInter)
+}
+
+func SetUnionController(ctx *fasthttp.RequestCtx) {
+	setCombineController(ctx, storage.SUnion)
+}
+
+func setCombineController(ctx *fasthttp.RequestCtx, combine func(keys []string) ([]string, error)) {
+	countHTTPRequest()
+
+	raw := string(ctx.QueryArgs().Peek("keys"))
+	if raw == "" {
+		ctx.Error("keys query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	members, err := combine(strings.Split(raw, ","))
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, members)
+}
@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/taymour/elysiandb/internal/stat"
+	"github.com/valyala/fasthttp"
+)
+
+func SlowlogController(ctx *fasthttp.RequestCtx) {
+	count := -1
+	if ctx.QueryArgs().Has("count") {
+		count = ctx.QueryArgs().GetUintOrZero("count")
+	}
+
+	jsonData, err := json.Marshal(stat.Slowlog.Get(count))
+	if err != nil {
+		ctx.Error(err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ctx.SetContentType("application/json")
+	_, _ = ctx.Write(jsonData)
+}
+
+func ResetSlowlogController(ctx *fasthttp.RequestCtx) {
+	stat.Slowlog.Reset()
+	ctx.SetStatusCode(http.StatusNoContent)
+}
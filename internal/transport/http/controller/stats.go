@@ -1,11 +1,26 @@
 package controller
 
 import (
+	"net/http"
+
+	"github.com/taymour/elysiandb/internal/globals"
 	"github.com/taymour/elysiandb/internal/stat"
+	"github.com/taymour/elysiandb/internal/storage"
 	"github.com/valyala/fasthttp"
 )
 
+// statsExpiryBatch bounds the expiry work a scrape does before reading the
+// counters; a larger backlog is left to the background sweeper.
+const statsExpiryBatch = 1000
+
 func StatsController(ctx *fasthttp.RequestCtx) {
+	if !globals.GetConfig().Stats.Enabled {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	storage.ExpireDueKeys(statsExpiryBatch)
+
 	ctx.SetContentType("application/json")
 	_, _ = ctx.Write([]byte(stat.Stats.ToJson()))
 }
@@ -0,0 +1,329 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type streamEntryBody struct {
+	ID     string            `json:"id"`
+	Fields map[string]string `json:"fields"`
+}
+
+type pendingEntryBody struct {
+	ID         string `json:"id"`
+	Consumer   string `json:"consumer"`
+	IdleMs     int64  `json:"idle_ms"`
+	Deliveries int    `json:"deliveries"`
+}
+
+func AddStreamEntryController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	var body map[string]string
+	if err := json.Unmarshal(ctx.PostBody(), &body); err != nil || len(body) == 0 {
+		ctx.Error("body must be a non-empty JSON object of string fields", http.StatusBadRequest)
+		return
+	}
+
+	trim, ok := streamTrimArgs(ctx)
+	if !ok {
+		return
+	}
+
+	names := make([]string, 0, len(body))
+	for f := range body {
+		names = append(names, f)
+	}
+	sort.Strings(names)
+
+	fields := make([]storage.HashField, len(names))
+	for i, f := range names {
+		fields[i] = storage.HashField{Field: f, Value: []byte(body[f])}
+	}
+
+	id := "*"
+	if ctx.QueryArgs().Has("id") {
+		id = string(ctx.QueryArgs().Peek("id"))
+	}
+
+	added, err := storage.XAdd(pathValue(ctx, "key"), id, fields, trim)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]string{"id": added.String()})
+}
+
+func GetStreamController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	args := ctx.QueryArgs()
+
+	start, end := "-", "+"
+	if args.Has("start") {
+		start = string(args.Peek("start"))
+	}
+	if args.Has("end") {
+		end = string(args.Peek("end"))
+	}
+
+	count, ok := intQueryArg(ctx, "count", 0)
+	if !ok {
+		return
+	}
+
+	entries, found, err := storage.XRange(pathValue(ctx, "key"), start, end, count)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+	if !found {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	writeJSON(ctx, streamEntriesBody(entries))
+}
+
+func StreamLengthController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	n, err := storage.XLen(pathValue(ctx, "key"))
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]int{"length": n})
+}
+
+func TrimStreamController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	trim, ok := streamTrimArgs(ctx)
+	if !ok {
+		return
+	}
+	if !trim.ByLength && trim.MaxAge == 0 {
+		ctx.Error("maxlen or maxage_ms is required", http.StatusBadRequest)
+		return
+	}
+
+	removed, err := storage.XTrim(pathValue(ctx, "key"), trim)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]int{"removed": removed})
+}
+
+func ReadStreamController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	key := pathValue(ctx, "key")
+
+	after := "$"
+	if ctx.QueryArgs().Has("after") {
+		after = string(ctx.QueryArgs().Peek("after"))
+	}
+
+	count, ok := intQueryArg(ctx, "count", 0)
+	if !ok {
+		return
+	}
+	block, timeout, ok := longPollArgs(ctx)
+	if !ok {
+		return
+	}
+
+	reads, err := storage.XRead([]string{key}, []string{after}, count, block, timeout, ctx.Done())
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, streamEntriesBody(firstRead(reads)))
+}
+
+func CreateStreamGroupController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	id := "$"
+	if ctx.QueryArgs().Has("id") {
+		id = string(ctx.QueryArgs().Peek("id"))
+	}
+	mkstream := ctx.QueryArgs().GetBool("mkstream")
+
+	if err := storage.XGroupCreate(pathValue(ctx, "key"), pathValue(ctx, "group"), id, mkstream); err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusCreated)
+}
+
+func DeleteStreamGroupController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	destroyed, err := storage.XGroupDestroy(pathValue(ctx, "key"), pathValue(ctx, "group"))
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+	if !destroyed {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusNoContent)
+}
+
+func ReadStreamGroupController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	key := pathValue(ctx, "key")
+
+	consumer := string(ctx.QueryArgs().Peek("consumer"))
+	if consumer == "" {
+		ctx.Error("consumer query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	id := ">"
+	if ctx.QueryArgs().Has("id") {
+		id = string(ctx.QueryArgs().Peek("id"))
+	}
+
+	count, ok := intQueryArg(ctx, "count", 0)
+	if !ok {
+		return
+	}
+	block, timeout, ok := longPollArgs(ctx)
+	if !ok {
+		return
+	}
+
+	reads, err := storage.XReadGroup(pathValue(ctx, "group"), consumer, []string{key}, []string{id}, count, block, timeout, ctx.Done())
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, streamEntriesBody(firstRead(reads)))
+}
+
+func AckStreamGroupController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	var ids []string
+	if err := json.Unmarshal(ctx.PostBody(), &ids); err != nil {
+		ctx.Error("body must be a JSON array of entry IDs", http.StatusBadRequest)
+		return
+	}
+
+	acked, err := storage.XAck(pathValue(ctx, "key"), pathValue(ctx, "group"), ids)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]int{"acked": acked})
+}
+
+func PendingStreamGroupController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	pending, err := storage.XPending(pathValue(ctx, "key"), pathValue(ctx, "group"), string(ctx.QueryArgs().Peek("consumer")))
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	out := make([]pendingEntryBody, len(pending))
+	for i, p := range pending {
+		out[i] = pendingEntryBody{ID: p.ID.String(), Consumer: p.Consumer, IdleMs: p.Idle.Milliseconds(), Deliveries: p.Deliveries}
+	}
+
+	writeJSON(ctx, out)
+}
+
+func ClaimStreamGroupController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	consumer := string(ctx.QueryArgs().Peek("consumer"))
+	if consumer == "" {
+		ctx.Error("consumer query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	minIdle, ok := intQueryArg(ctx, "min_idle_ms", 0)
+	if !ok {
+		return
+	}
+
+	var ids []string
+	if err := json.Unmarshal(ctx.PostBody(), &ids); err != nil {
+		ctx.Error("body must be a JSON array of entry IDs", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := storage.XClaim(pathValue(ctx, "key"), pathValue(ctx, "group"), consumer, time.Duration(minIdle)*time.Millisecond, ids)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, streamEntriesBody(entries))
+}
+
+func streamTrimArgs(ctx *fasthttp.RequestCtx) (storage.StreamTrim, bool) {
+	var trim storage.StreamTrim
+	args := ctx.QueryArgs()
+
+	if args.Has("maxlen") {
+		n, err := strconv.Atoi(string(args.Peek("maxlen")))
+		if err != nil || n < 0 {
+			ctx.Error("maxlen must be a non-negative integer", http.StatusBadRequest)
+			return trim, false
+		}
+		trim.MaxLen, trim.ByLength = n, true
+	}
+
+	if args.Has("maxage_ms") {
+		ms, err := strconv.ParseInt(string(args.Peek("maxage_ms")), 10, 64)
+		if err != nil || ms < 0 {
+			ctx.Error("maxage_ms must be a non-negative integer", http.StatusBadRequest)
+			return trim, false
+		}
+		trim.MaxAge = time.Duration(ms) * time.Millisecond
+	}
+
+	return trim, true
+}
+
+func firstRead(reads []storage.StreamRead) []storage.StreamEntry {
+	if len(reads) == 0 {
+		return nil
+	}
+
+	return reads[0].Entries
+}
+
+func streamEntriesBody(entries []storage.StreamEntry) []streamEntryBody {
+	out := make([]streamEntryBody, len(entries))
+	for i, e := range entries {
+		fields := make(map[string]string, len(e.Fields))
+		for _, f := range e.Fields {
+			fields[f.Field] = string(f.Value)
+		}
+		out[i] = streamEntryBody{ID: e.ID.String(), Fields: fields}
+	}
+
+	return out
+}
@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+func InvalidateTagController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	writeJSON(ctx, map[string]int{"deleted": storage.InvalidateTag(pathValue(ctx, "tag"))})
+}
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/stat"
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type ttlEntry struct {
+	Key   string `json:"key"`
+	TTLMs int64  `json:"ttl_ms"`
+}
+
+func GetTTLController(ctx *fasthttp.RequestCtx) {
+	key := ttlKey(ctx)
+
+	ttl, hasExpiry, exists := storage.GetTTL(key)
+	if !exists {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	entry := ttlEntry{Key: key, TTLMs: -1}
+	if hasExpiry {
+		entry.TTLMs = ttl.Milliseconds()
+	}
+
+	jsonData, _ := json.Marshal(entry)
+	ctx.SetContentType("application/json")
+	_, _ = ctx.Write(jsonData)
+}
+
+func PutTTLController(ctx *fasthttp.RequestCtx) {
+	key := ttlKey(ctx)
+	args := ctx.QueryArgs()
+
+	var ok bool
+	switch {
+	case args.Has("ttl_ms"):
+		n, err := strconv.ParseInt(string(args.Peek("ttl_ms")), 10, 64)
+		if err != nil {
+			ctx.Error("ttl_ms must be an integer", http.StatusBadRequest)
+			return
+		}
+		ok = storage.Expire(key, time.Duration(n)*time.Millisecond)
+	case args.Has("ttl"):
+		n, err := strconv.ParseInt(string(args.Peek("ttl")), 10, 64)
+		if err != nil {
+			ctx.Error("ttl must be an integer", http.StatusBadRequest)
+			return
+		}
+		ok = storage.Expire(key, time.Duration(n)*time.Second)
+	case args.Has("at_ms"):
+		n, err := strconv.ParseInt(string(args.Peek("at_ms")), 10, 64)
+		if err != nil {
+			ctx.Error("at_ms must be a unix timestamp in milliseconds", http.StatusBadRequest)
+			return
+		}
+		ok = storage.ExpireAt(key, time.UnixMilli(n))
+	case args.Has("at"):
+		n, err := strconv.ParseInt(string(args.Peek("at")), 10, 64)
+		if err != nil {
+			ctx.Error("at must be a unix timestamp in seconds", http.StatusBadRequest)
+			return
+		}
+		ok = storage.ExpireAt(key, time.Unix(n, 0))
+	default:
+		ctx.Error("one of ttl, ttl_ms, at or at_ms is required", http.StatusBadRequest)
+		return
+	}
+
+	if !ok {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusNoContent)
+}
+
+func DeleteTTLController(ctx *fasthttp.RequestCtx) {
+	key := ttlKey(ctx)
+
+	if _, _, exists := storage.GetTTL(key); !exists {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	storage.Persist(key)
+	ctx.SetStatusCode(http.StatusNoContent)
+}
+
+func ttlKey(ctx *fasthttp.RequestCtx) string {
+	if globals.GetConfig().Stats.Enabled {
+		stat.Stats.IncrementTotalRequests()
+	}
+
+	key := ctx.UserValue("key").(string)
+	if dec, err := url.PathUnescape(key); err == nil {
+		key = dec
+	}
+
+	return key
+}
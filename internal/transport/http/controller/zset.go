@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type scoredEntry struct {
+	Member string  `json:"member"`
+	Score  float64 `json:"score"`
+}
+
+func GetZSetController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	key := pathValue(ctx, "key")
+	args := ctx.QueryArgs()
+
+	var members []storage.ScoredMember
+	var found bool
+	var err error
+
+	if args.Has("min") || args.Has("max") {
+		r := storage.ScoreRange{Min: math.Inf(-1), Max: math.Inf(1)}
+		var ok bool
+		if args.Has("min") {
+			if r.Min, r.MinExclusive, ok = storage.ParseScoreBound(string(args.Peek("min"))); !ok {
+				ctx.Error("min must be a number, optionally prefixed with (", http.StatusBadRequest)
+				return
+			}
+		}
+		if args.Has("max") {
+			if r.Max, r.MaxExclusive, ok = storage.ParseScoreBound(string(args.Peek("max"))); !ok {
+				ctx.Error("max must be a number, optionally prefixed with (", http.StatusBadRequest)
+				return
+			}
+		}
+
+		offset, ok := intQueryArg(ctx, "offset", 0)
+		if !ok {
+			return
+		}
+		count, ok := intQueryArg(ctx, "count", -1)
+		if !ok {
+			return
+		}
+
+		members, found, err = storage.ZRangeByScore(key, r, max(offset, 0), count)
+	} else {
+		start, ok := intQueryArg(ctx, "start", 0)
+		if !ok {
+			return
+		}
+		stop, ok := intQueryArg(ctx, "stop", -1)
+		if !ok {
+			return
+		}
+
+		members, found, err = storage.ZRange(key, start, stop)
+	}
+
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+	if !found {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	out := make([]scoredEntry, len(members))
+	for i, m := range members {
+		out[i] = scoredEntry{Member: m.Member, Score: m.Score}
+	}
+	writeJSON(ctx, out)
+}
+
+func PutZSetController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	var body map[string]float64
+	if err := json.Unmarshal(ctx.PostBody(), &body); err != nil {
+		ctx.Error("body must be a JSON object of member scores", http.StatusBadRequest)
+		return
+	}
+
+	members := make([]storage.ScoredMember, 0, len(body))
+	for m, s := range body {
+		members = append(members, storage.ScoredMember{Member: m, Score: s})
+	}
+
+	added, err := storage.ZAdd(pathValue(ctx, "key"), members)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]int{"added": added})
+}
+
+func IncrZSetMemberController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	delta := 1.0
+	if ctx.QueryArgs().Has("by") {
+		v, err := strconv.ParseFloat(string(ctx.QueryArgs().Peek("by")), 64)
+		if err != nil {
+			ctx.Error("by must be a number", http.StatusBadRequest)
+			return
+		}
+		delta = v
+	}
+
+	score, err := storage.ZIncrBy(pathValue(ctx, "key"), pathValue(ctx, "member"), delta)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+
+	writeJSON(ctx, map[string]float64{"score": score})
+}
+
+func ZSetRankController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+	member := pathValue(ctx, "member")
+
+	rank, score, found, err := storage.ZRank(pathValue(ctx, "key"), member)
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+	if !found {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	writeJSON(ctx, map[string]any{"member": member, "rank": rank, "score": score})
+}
+
+func DeleteZSetMemberController(ctx *fasthttp.RequestCtx) {
+	countHTTPRequest()
+
+	removed, err := storage.ZRem(pathValue(ctx, "key"), []string{pathValue(ctx, "member")})
+	if err != nil {
+		writeStorageError(ctx, err)
+		return
+	}
+	if removed == 0 {
+		ctx.SetStatusCode(http.StatusNotFound)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusNoContent)
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/taymour/elysiandb/internal/transport/tcp/parsing"
+)
+
+func HandleJSONSet(query []byte) []byte {
+	countRequest()
+
+	k, rest := parsing.FirstWordBytes(query)
+	path, value := parsing.FirstWordBytes(rest)
+	if len(k) == 0 || len(path) == 0 || len(value) == 0 {
+		return usageReply("JSON.SET <key> <path> <json>")
+	}
+
+	if err := storage.SetDocumentPath(string(k), string(path), value); err != nil {
+		return errReply(err)
+	}
+
+	return []byte("OK")
+}
+
+func HandleJSONGet(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 1 || len(args) > 2 {
+		return usageReply("JSON.GET <key> [path]")
+	}
+
+	path := "$"
+	if len(args) == 2 {
+		path = args[1]
+	}
+
+	doc, found, err := storage.GetDocument(args[0], path)
+	if err != nil {
+		return errReply(err)
+	}
+	if !found {
+		return notFoundReply(args[0])
+	}
+
+	return doc
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/taymour/elysiandb/internal/transport/tcp/parsing"
+)
+
+func HandleExists(query []byte) []byte {
+	countRequest()
+
+	count := 0
+	for key, rest := parsing.FirstWordBytes(query); len(key) > 0; key, rest = parsing.FirstWordBytes(rest) {
+		if storage.Exists(string(key)) {
+			count++
+		}
+	}
+
+	return []byte(strconv.Itoa(count))
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/taymour/elysiandb/internal/transport/tcp/parsing"
+)
+
+func HandleExpire(query []byte, unit time.Duration) []byte {
+	countRequest()
+
+	key, n, ok := parseKeyAndInteger(query)
+	if !ok {
+		return []byte("ERR usage: <key> <amount>")
+	}
+
+	return boolReply(storage.Expire(key, time.Duration(n)*unit))
+}
+
+func HandleExpireAt(query []byte, unit time.Duration) []byte {
+	countRequest()
+
+	key, n, ok := parseKeyAndInteger(query)
+	if !ok {
+		return []byte("ERR usage: <key> <timestamp>")
+	}
+
+	return boolReply(storage.ExpireAt(key, time.UnixMilli(n*int64(unit/time.Millisecond))))
+}
+
+func HandleTTL(query []byte, unit time.Duration) []byte {
+	countRequest()
+
+	key, _ := parsing.FirstWordBytes(query)
+	if len(key) == 0 {
+		return []byte("ERR usage: <key>")
+	}
+
+	ttl, hasExpiry, exists := storage.GetTTL(string(key))
+	switch {
+	case !exists:
+		return []byte("-2")
+	case !hasExpiry:
+		return []byte("-1")
+	}
+
+	return []byte(strconv.FormatInt(int64((ttl+unit/2)/unit), 10))
+}
+
+func HandlePersist(query []byte) []byte {
+	countRequest()
+
+	key, _ := parsing.FirstWordBytes(query)
+	if len(key) == 0 {
+		return []byte("ERR usage: <key>")
+	}
+
+	return boolReply(storage.Persist(string(key)))
+}
+
+func parseKeyAndInteger(query []byte) (string, int64, bool) {
+	key, rest := parsing.FirstWordBytes(query)
+	arg, _ := parsing.FirstWordBytes(rest)
+	if len(key) == 0 || len(arg) == 0 {
+		return "", 0, false
+	}
+
+	n, err := strconv.ParseInt(string(arg), 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return string(key), n, true
+}
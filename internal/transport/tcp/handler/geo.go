@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/taymour/elysiandb/internal/geo"
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/taymour/elysiandb/internal/transport/tcp/parsing"
+)
+
+type geoSearchOptions struct {
+	query     storage.GeoQuery
+	unit      float64
+	withCoord bool
+	withDist  bool
+}
+
+func HandleGeoAdd(query []byte) []byte {
+	countRequest()
+
+	usage := "GEOADD <key> <longitude> <latitude> <member> [longitude latitude member ...]"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 4 || (len(args)-1)%3 != 0 {
+		return usageReply(usage)
+	}
+
+	points := make([]storage.GeoPoint, 0, (len(args)-1)/3)
+	for i := 1; i < len(args); i += 3 {
+		lon, lat, ok := parseLonLat(args[i], args[i+1])
+		if !ok {
+			return usageReply(usage)
+		}
+		points = append(points, storage.GeoPoint{Member: args[i+2], Lon: lon, Lat: lat})
+	}
+
+	added, err := storage.GeoAdd(args[0], points)
+	if err != nil {
+		return errReply(err)
+	}
+
+	return intReply(int64(added))
+}
+
+func HandleGeoPos(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 2 {
+		return usageReply("GEOPOS <key> <member> [member ...]")
+	}
+
+	points, err := storage.GeoPos(args[0], args[1:])
+	if err != nil {
+		return errReply(err)
+	}
+
+	lines := make([]string, len(points))
+	for i, p := range points {
+		if p == nil {
+			lines[i] = string(notFoundReply(args[i+1]))
+			continue
+		}
+		lines[i] = parsing.Quote(p.Member) + " " + formatCoord(p.Lon) + " " + formatCoord(p.Lat)
+	}
+
+	return joinLines(lines)
+}
+
+func HandleGeoDist(query []byte) []byte {
+	countRequest()
+
+	usage := "GEODIST <key> <member1> <member2> [m|km|mi|ft]"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 3 || len(args) > 4 {
+		return usageReply(usage)
+	}
+
+	unit := 1.0
+	if len(args) == 4 {
+		if unit, ok = geo.UnitFactor(args[3]); !ok {
+			return usageReply(usage)
+		}
+	}
+
+	dist, err := storage.GeoDist(args[0], args[1], args[2])
+	if err != nil {
+		return errReply(err)
+	}
+
+	return []byte(strconv.FormatFloat(dist/unit, 'f', 4, 64))
+}
+
+func HandleGeoSearch(query []byte) []byte {
+	countRequest()
+
+	usage := "GEOSEARCH <key> FROMMEMBER <member> | FROMLONLAT <longitude> <latitude> " +
+		"BYRADIUS <radius> <unit> | BYBOX <width> <height> <unit> [ASC|DESC] [COUNT n] [WITHCOORD] [WITHDIST]"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 1 {
+		return usageReply(usage)
+	}
+
+	opts, ok := parseGeoSearchOptions(args[1:])
+	if !ok {
+		return usageReply(usage)
+	}
+
+	matches, err := storage.GeoSearch(args[0], opts.query)
+	if err != nil {
+		return errReply(err)
+	}
+
+	lines := make([]string, len(matches))
+	for i, m := range matches {
+		line := parsing.Quote(m.Member)
+		if opts.withDist {
+			line += " " + strconv.FormatFloat(m.Dist/opts.unit, 'f', 4, 64)
+		}
+		if opts.withCoord {
+			line += " " + formatCoord(m.Lon) + " " + formatCoord(m.Lat)
+		}
+		lines[i] = line
+	}
+
+	return joinLines(lines)
+}
+
+func parseGeoSearchOptions(args []string) (geoSearchOptions, bool) {
+	var opts geoSearchOptions
+	from, by := false, false
+
+	for len(args) > 0 {
+		switch strings.ToUpper(args[0]) {
+		case "FROMMEMBER":
+			if from || len(args) < 2 {
+				return opts, false
+			}
+			opts.query.Member, from = args[1], true
+			args = args[2:]
+		case "FROMLONLAT":
+			if from || len(args) < 3 {
+				return opts, false
+			}
+			lon, lat, ok := parseLonLat(args[1], args[2])
+			if !ok {
+				return opts, false
+			}
+			opts.query.Lon, opts.query.Lat, from = lon, lat, true
+			args = args[3:]
+		case "BYRADIUS":
+			if by || len(args) < 3 {
+				return opts, false
+			}
+			radius, err := strconv.ParseFloat(args[1], 64)
+			unit, ok := geo.UnitFactor(args[2])
+			if err != nil || radius < 0 || !ok {
+				return opts, false
+			}
+			opts.query.Radius, opts.unit, by = radius*unit, unit, true
+			args = args[3:]
+		case "BYBOX":
+			if by || len(args) < 4 {
+				return opts, false
+			}
+			width, errW := strconv.ParseFloat(args[1], 64)
+			height, errH := strconv.ParseFloat(args[2], 64)
+			unit, ok := geo.UnitFactor(args[3])
+			if errW != nil || errH != nil || width <= 0 || height <= 0 || !ok {
+				return opts, false
+			}
+			opts.query.Width, opts.query.Height, opts.unit, by = width*unit, height*unit, unit, true
+			args = args[4:]
+		case "ASC":
+			opts.query.Desc = false
+			args = args[1:]
+		case "DESC":
+			opts.query.Desc = true
+			args = args[1:]
+		case "COUNT":
+			if len(args) < 2 {
+				return opts, false
+			}
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return opts, false
+			}
+			opts.query.Count = n
+			args = args[2:]
+		case "WITHCOORD":
+			opts.withCoord = true
+			args = args[1:]
+		case "WITHDIST":
+			opts.withDist = true
+			args = args[1:]
+		default:
+			return opts, false
+		}
+	}
+
+	return opts, from && by
+}
+
+func parseLonLat(lonArg, latArg string) (float64, float64, bool) {
+	lon, err := strconv.ParseFloat(lonArg, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	lat, err := strconv.ParseFloat(latArg, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return lon, lat, true
+}
+
+func formatCoord(v float64) string {
+	return strconv.FormatFloat(v, 'f', 6, 64)
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/taymour/elysiandb/internal/globals"
@@ -54,17 +55,10 @@ func handleSingleKey(query []byte) []byte {
 
 	key := string(query)
 
-	if storage.KeyHasExpired(key) {
-		storage.DeleteByKey(key)
-
-		if cfg.Stats.Enabled {
-			stat.Stats.IncrementMisses()
-		}
-
-		return []byte(fmt.Sprintf("%s=not found", key))
+	data, stale, err := storage.GetByKeyWithStale(key)
+	if errors.Is(err, storage.ErrWrongType) {
+		return errReply(err)
 	}
-
-	data, err := storage.GetByKey(key)
 	if err != nil {
 		if cfg.Stats.Enabled {
 			stat.Stats.IncrementMisses()
@@ -77,5 +71,9 @@ func handleSingleKey(query []byte) []byte {
 		stat.Stats.IncrementHits()
 	}
 
+	if stale {
+		return []byte(fmt.Sprintf("STALE %s=%s", key, data))
+	}
+
 	return []byte(fmt.Sprintf("%s=%s", key, data))
 }
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/taymour/elysiandb/internal/transport/tcp/parsing"
+)
+
+func HandleHSet(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 3 || len(args)%2 != 1 {
+		return usageReply("HSET <key> <field> <value> [field value ...]")
+	}
+
+	pairs := make([]storage.HashField, 0, len(args)/2)
+	for i := 1; i+1 < len(args); i += 2 {
+		pairs = append(pairs, storage.HashField{Field: args[i], Value: []byte(args[i+1])})
+	}
+
+	added, err := storage.HSet(args[0], pairs)
+	if err != nil {
+		return errReply(err)
+	}
+
+	return intReply(int64(added))
+}
+
+func HandleHGet(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 2 {
+		return usageReply("HGET <key> <field>")
+	}
+
+	v, found, err := storage.HGet(args[0], args[1])
+	if err != nil {
+		return errReply(err)
+	}
+	if !found {
+		return notFoundReply(args[1])
+	}
+
+	return v
+}
+
+func HandleHMGet(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 2 {
+		return usageReply("HMGET <key> <field> [field ...]")
+	}
+
+	values, err := storage.HMGet(args[0], args[1:])
+	if err != nil {
+		return errReply(err)
+	}
+
+	lines := make([][]byte, len(values))
+	for i, v := range values {
+		if v == nil {
+			lines[i] = notFoundReply(args[i+1])
+			continue
+		}
+		lines[i] = v
+	}
+
+	return parsing.JoinByteSlices(lines, []byte("\n"))
+}
+
+func HandleHDel(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 2 {
+		return usageReply("HDEL <key> <field> [field ...]")
+	}
+
+	removed, err := storage.HDel(args[0], args[1:])
+	if err != nil {
+		return errReply(err)
+	}
+
+	return intReply(int64(removed))
+}
+
+func HandleHGetAll(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 1 {
+		return usageReply("HGETALL <key>")
+	}
+
+	fields, found, err := storage.HGetAll(args[0])
+	if err != nil {
+		return errReply(err)
+	}
+	if !found {
+		return notFoundReply(args[0])
+	}
+
+	lines := make([][]byte, len(fields))
+	for i, f := range fields {
+		lines[i] = append([]byte(f.Field+"="), f.Value...)
+	}
+
+	return parsing.JoinByteSlices(lines, []byte("\n"))
+}
+
+func HandleHIncrBy(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 3 {
+		return usageReply("HINCRBY <key> <field> <delta>")
+	}
+
+	delta, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return errReply(storage.ErrNotInteger)
+	}
+
+	n, err := storage.HIncrBy(args[0], args[1], delta)
+	if err != nil {
+		return errReply(err)
+	}
+
+	return intReply(n)
+}
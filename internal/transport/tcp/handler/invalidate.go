@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func HandleInvalidate(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 1 {
+		return usageReply("INVALIDATE <tag>")
+	}
+
+	return intReply(int64(storage.InvalidateTag(args[0])))
+}
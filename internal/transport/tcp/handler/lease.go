@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"strings"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/stat"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func HandleGetLease(query []byte) []byte {
+	cfg := globals.GetConfig()
+	countRequest()
+
+	usage := "GETLEASE <key> <lease_ms> [WAIT <timeout_ms>]"
+
+	args, ok := parseArgs(query)
+	if !ok || (len(args) != 2 && len(args) != 4) {
+		return usageReply(usage)
+	}
+
+	ttl, ok := parseMillis(args[1], false)
+	if !ok {
+		return usageReply(usage)
+	}
+
+	wait, timeout := false, time.Duration(0)
+	if len(args) == 4 {
+		if !strings.EqualFold(args[2], "WAIT") {
+			return usageReply(usage)
+		}
+		if timeout, ok = parseMillis(args[3], true); !ok {
+			return usageReply(usage)
+		}
+		wait = true
+	}
+
+	res, err := storage.GetLease(args[0], ttl, wait, timeout, nil)
+	if err != nil {
+		return errReply(err)
+	}
+
+	if cfg.Stats.Enabled {
+		if res.Found {
+			stat.Stats.IncrementHits()
+		} else {
+			stat.Stats.IncrementMisses()
+		}
+	}
+
+	switch {
+	case res.Found:
+		reply := args[0] + "=" + string(res.Value)
+		if res.Token != "" {
+			reply = "LEASE " + res.Token + " " + reply
+		}
+		if res.Stale {
+			reply = "STALE " + reply
+		}
+		return []byte(reply)
+	case res.Token != "":
+		return []byte("LEASE " + res.Token)
+	}
+
+	return notFoundReply(args[0])
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/taymour/elysiandb/internal/transport/tcp/parsing"
+)
+
+func HandlePush(query []byte, left bool) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 2 {
+		return usageReply(listCommand("PUSH", left) + " <key> <value> [value ...]")
+	}
+
+	values := make([][]byte, len(args)-1)
+	for i, v := range args[1:] {
+		values[i] = []byte(v)
+	}
+
+	var n int
+	var err error
+	if left {
+		n, err = storage.LPush(args[0], values)
+	} else {
+		n, err = storage.RPush(args[0], values)
+	}
+	if err != nil {
+		return errReply(err)
+	}
+
+	return intReply(int64(n))
+}
+
+func HandlePop(query []byte, left bool) []byte {
+	countRequest()
+
+	usage := listCommand("POP", left) + " <key> [count]"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 1 || len(args) > 2 {
+		return usageReply(usage)
+	}
+
+	count := 1
+	if len(args) == 2 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return usageReply(usage)
+		}
+		count = n
+	}
+
+	var values [][]byte
+	var err error
+	if left {
+		values, err = storage.LPop(args[0], count)
+	} else {
+		values, err = storage.RPop(args[0], count)
+	}
+	if err != nil {
+		return errReply(err)
+	}
+	if len(values) == 0 {
+		return notFoundReply(args[0])
+	}
+
+	return parsing.JoinByteSlices(values, []byte("\n"))
+}
+
+func HandleBlockingPop(query []byte, left bool) []byte {
+	countRequest()
+
+	usage := "B" + listCommand("POP", left) + " <key> [key ...] <timeout>"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 2 {
+		return usageReply(usage)
+	}
+
+	seconds, err := strconv.ParseFloat(args[len(args)-1], 64)
+	if err != nil || seconds < 0 {
+		return usageReply(usage)
+	}
+	timeout := time.Duration(seconds * float64(time.Second))
+
+	var key string
+	var value []byte
+	var popped bool
+	if left {
+		key, value, popped, err = storage.BLPop(args[:len(args)-1], timeout, nil)
+	} else {
+		key, value, popped, err = storage.BRPop(args[:len(args)-1], timeout, nil)
+	}
+	if err != nil {
+		return errReply(err)
+	}
+	if !popped {
+		return []byte("TIMEOUT")
+	}
+
+	return append([]byte(key+"="), value...)
+}
+
+func HandleLRange(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 3 {
+		return usageReply("LRANGE <key> <start> <stop>")
+	}
+
+	start, stop, ok := parseRange(args[1], args[2])
+	if !ok {
+		return usageReply("LRANGE <key> <start> <stop>")
+	}
+
+	values, found, err := storage.LRange(args[0], start, stop)
+	if err != nil {
+		return errReply(err)
+	}
+	if !found {
+		return notFoundReply(args[0])
+	}
+
+	return parsing.JoinByteSlices(values, []byte("\n"))
+}
+
+func HandleLLen(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 1 {
+		return usageReply("LLEN <key>")
+	}
+
+	n, err := storage.LLen(args[0])
+	if err != nil {
+		return errReply(err)
+	}
+
+	return intReply(int64(n))
+}
+
+func HandleLTrim(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 3 {
+		return usageReply("LTRIM <key> <start> <stop>")
+	}
+
+	start, stop, ok := parseRange(args[1], args[2])
+	if !ok {
+		return usageReply("LTRIM <key> <start> <stop>")
+	}
+
+	if err := storage.LTrim(args[0], start, stop); err != nil {
+		return errReply(err)
+	}
+
+	return []byte("OK")
+}
+
+func listCommand(name string, left bool) string {
+	if left {
+		return "L" + name
+	}
+
+	return "R" + name
+}
+
+func parseRange(a, b string) (int, int, bool) {
+	start, err := strconv.Atoi(a)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	stop, err := strconv.Atoi(b)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return start, stop, true
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func HandleLock(query []byte) []byte {
+	countRequest()
+
+	usage := "LOCK <name> <ttl_ms> [WAIT <timeout_ms>]"
+
+	args, ok := parseArgs(query)
+	if !ok || (len(args) != 2 && len(args) != 4) {
+		return usageReply(usage)
+	}
+
+	ttl, ok := parseMillis(args[1], false)
+	if !ok {
+		return usageReply(usage)
+	}
+
+	wait, timeout := false, time.Duration(0)
+	if len(args) == 4 {
+		if !strings.EqualFold(args[2], "WAIT") {
+			return usageReply(usage)
+		}
+		if timeout, ok = parseMillis(args[3], true); !ok {
+			return usageReply(usage)
+		}
+		wait = true
+	}
+
+	lock, acquired, err := storage.AcquireLock(args[0], ttl, wait, timeout, nil)
+	if err != nil {
+		return errReply(err)
+	}
+	if !acquired {
+		return []byte("LOCKED")
+	}
+
+	return []byte(lock.Owner + " " + strconv.FormatUint(lock.Fence, 10))
+}
+
+func HandleRenew(query []byte) []byte {
+	countRequest()
+
+	usage := "RENEW <name> <owner> <ttl_ms>"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 3 {
+		return usageReply(usage)
+	}
+
+	ttl, ok := parseMillis(args[2], false)
+	if !ok {
+		return usageReply(usage)
+	}
+
+	lock, err := storage.RenewLock(args[0], args[1], ttl)
+	if err != nil {
+		return errReply(err)
+	}
+
+	return []byte(strconv.FormatUint(lock.Fence, 10))
+}
+
+func HandleUnlock(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 2 {
+		return usageReply("UNLOCK <name> <owner>")
+	}
+
+	if err := storage.ReleaseLock(args[0], args[1]); err != nil {
+		return errReply(err)
+	}
+
+	return []byte("OK")
+}
+
+func parseMillis(s string, allowZero bool) (time.Duration, bool) {
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || ms < 0 || (ms == 0 && !allowZero) {
+		return 0, false
+	}
+
+	return time.Duration(ms) * time.Millisecond, true
+}
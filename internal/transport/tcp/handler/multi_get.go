@@ -36,18 +36,6 @@ func HandleMultiGet(query []byte) []byte {
 
 func HandleMGETSingleKey(key string, i int, results *[][]byte) {
 	cfg := globals.GetConfig()
-	if storage.KeyHasExpired(key) {
-		storage.DeleteByKey(key)
-
-		if cfg.Stats.Enabled {
-			stat.Stats.IncrementMisses()
-		}
-
-		(*results)[i] = []byte(fmt.Sprintf("%s=not found", key))
-
-		return
-	}
-
 	data, err := storage.GetByKey(key)
 	if err != nil {
 		if cfg.Stats.Enabled {
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func HandlePFAdd(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 2 {
+		return usageReply("PFADD <key> <element> [element ...]")
+	}
+
+	changed, err := storage.PFAdd(args[0], args[1:])
+	if err != nil {
+		return errReply(err)
+	}
+
+	return boolReply(changed)
+}
+
+func HandlePFCount(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 1 {
+		return usageReply("PFCOUNT <key> [key ...]")
+	}
+
+	n, err := storage.PFCount(args)
+	if err != nil {
+		return errReply(err)
+	}
+
+	return intReply(int64(n))
+}
+
+func HandlePFMerge(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 2 {
+		return usageReply("PFMERGE <destkey> <sourcekey> [sourcekey ...]")
+	}
+
+	if err := storage.PFMerge(args[0], args[1:]); err != nil {
+		return errReply(err)
+	}
+
+	return []byte("OK")
+}
+
+func HandleBFReserve(query []byte) []byte {
+	countRequest()
+
+	usage := "BF.RESERVE <key> <error_rate> <capacity>"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 3 {
+		return usageReply(usage)
+	}
+
+	errorRate, err := strconv.ParseFloat(args[1], 64)
+	if err != nil {
+		return usageReply(usage)
+	}
+	capacity, err := strconv.ParseUint(args[2], 10, 64)
+	if err != nil {
+		return usageReply(usage)
+	}
+
+	if err := storage.BFReserve(args[0], errorRate, capacity); err != nil {
+		return errReply(err)
+	}
+
+	return []byte("OK")
+}
+
+func HandleBFAdd(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 2 {
+		return usageReply("BF.ADD <key> <item>")
+	}
+
+	added, err := storage.BFAdd(args[0], args[1])
+	if err != nil {
+		return errReply(err)
+	}
+
+	return boolReply(added)
+}
+
+func HandleBFExists(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 2 {
+		return usageReply("BF.EXISTS <key> <item>")
+	}
+
+	exists, err := storage.BFExists(args[0], args[1])
+	if err != nil {
+		return errReply(err)
+	}
+
+	return boolReply(exists)
+}
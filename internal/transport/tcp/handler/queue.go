@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/taymour/elysiandb/internal/transport/tcp/parsing"
+)
+
+func HandleEnqueue(query []byte) []byte {
+	countRequest()
+
+	usage := "ENQUEUE <queue> <payload> [DELAY <ms> | AT <unix_ms>] [ATTEMPTS <n>]"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 2 || len(args)%2 != 0 {
+		return usageReply(usage)
+	}
+
+	runAt, attempts := time.Now(), 0
+	for i := 2; i < len(args); i += 2 {
+		n, err := strconv.ParseInt(args[i+1], 10, 64)
+		if err != nil || n < 0 {
+			return usageReply(usage)
+		}
+
+		switch strings.ToUpper(args[i]) {
+		case "DELAY":
+			runAt = time.Now().Add(time.Duration(n) * time.Millisecond)
+		case "AT":
+			runAt = time.UnixMilli(n)
+		case "ATTEMPTS":
+			if n == 0 {
+				return usageReply(usage)
+			}
+			attempts = int(n)
+		default:
+			return usageReply(usage)
+		}
+	}
+
+	id, err := storage.Enqueue(args[0], []byte(args[1]), runAt, attempts)
+	if err != nil {
+		return errReply(err)
+	}
+
+	return []byte(id)
+}
+
+func HandleReserve(query []byte) []byte {
+	countRequest()
+
+	usage := "RESERVE <queue> <visibility_ms>"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 2 {
+		return usageReply(usage)
+	}
+
+	visibility, ok := parseMillis(args[1], false)
+	if !ok {
+		return usageReply(usage)
+	}
+
+	job, reserved, err := storage.Reserve(args[0], visibility)
+	if err != nil {
+		return errReply(err)
+	}
+	if !reserved {
+		return notFoundReply(args[0])
+	}
+
+	return []byte(formatJob(job))
+}
+
+func HandleAck(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 2 {
+		return usageReply("ACK <queue> <id>")
+	}
+
+	if err := storage.Ack(args[0], args[1]); err != nil {
+		return errReply(err)
+	}
+
+	return []byte("OK")
+}
+
+func HandleNack(query []byte) []byte {
+	countRequest()
+
+	usage := "NACK <queue> <id> [delay_ms]"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 2 || len(args) > 3 {
+		return usageReply(usage)
+	}
+
+	delay := time.Duration(0)
+	if len(args) == 3 {
+		if delay, ok = parseMillis(args[2], true); !ok {
+			return usageReply(usage)
+		}
+	}
+
+	if err := storage.Nack(args[0], args[1], delay); err != nil {
+		return errReply(err)
+	}
+
+	return []byte("OK")
+}
+
+func HandleTouch(query []byte) []byte {
+	countRequest()
+
+	usage := "TOUCH <queue> <id> <visibility_ms>"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 3 {
+		return usageReply(usage)
+	}
+
+	visibility, ok := parseMillis(args[2], false)
+	if !ok {
+		return usageReply(usage)
+	}
+
+	if err := storage.Touch(args[0], args[1], visibility); err != nil {
+		return errReply(err)
+	}
+
+	return []byte("OK")
+}
+
+func HandleQInfo(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 1 {
+		return usageReply("QINFO <queue>")
+	}
+
+	stats, err := storage.QueueInfo(args[0])
+	if err != nil {
+		return errReply(err)
+	}
+
+	return []byte("ready " + strconv.Itoa(stats.Ready) + " delayed " + strconv.Itoa(stats.Delayed) +
+		" reserved " + strconv.Itoa(stats.Reserved) + " dead " + strconv.Itoa(stats.Dead))
+}
+
+func HandleQDead(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 1 {
+		return usageReply("QDEAD <queue>")
+	}
+
+	jobs, err := storage.DeadJobs(args[0])
+	if err != nil {
+		return errReply(err)
+	}
+
+	lines := make([]string, len(jobs))
+	for i, j := range jobs {
+		lines[i] = formatJob(j)
+	}
+
+	return joinLines(lines)
+}
+
+func formatJob(j storage.Job) string {
+	return j.ID + " " + strconv.Itoa(j.Attempts) + " " + parsing.Quote(string(j.Payload))
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func HandleRateLimit(query []byte) []byte {
+	countRequest()
+
+	usage := "RATELIMIT <key> <capacity> <refill_per_sec> [cost]"
+
+	args, ok := parseArgs(query)
+	if !ok || (len(args) != 3 && len(args) != 4) {
+		return usageReply(usage)
+	}
+
+	capacity, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return usageReply(usage)
+	}
+	refill, err := strconv.ParseFloat(args[2], 64)
+	if err != nil {
+		return usageReply(usage)
+	}
+	cost := int64(1)
+	if len(args) == 4 {
+		if cost, err = strconv.ParseInt(args[3], 10, 64); err != nil {
+			return usageReply(usage)
+		}
+	}
+
+	res, err := storage.RateLimit(args[0], capacity, refill, cost)
+	if err != nil {
+		return errReply(err)
+	}
+
+	verdict := "ALLOWED "
+	if !res.Allowed {
+		verdict = "DENIED "
+	}
+
+	return []byte(verdict + strconv.FormatInt(res.Remaining, 10) + " " + strconv.FormatInt(res.RetryAfter.Milliseconds(), 10))
+}
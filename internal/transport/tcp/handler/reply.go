@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/log"
+	"github.com/taymour/elysiandb/internal/stat"
+	"github.com/taymour/elysiandb/internal/transport/tcp/parsing"
+)
+
+func boolReply(ok bool) []byte {
+	if ok {
+		return []byte("1")
+	}
+
+	return []byte("0")
+}
+
+func intReply(n int64) []byte {
+	return []byte(strconv.FormatInt(n, 10))
+}
+
+func errReply(err error) []byte {
+	return []byte("ERR " + err.Error())
+}
+
+func usageReply(usage string) []byte {
+	return []byte("ERR usage: " + usage)
+}
+
+func notFoundReply(name string) []byte {
+	return []byte(name + "=not found")
+}
+
+func parseArgs(query []byte) ([]string, bool) {
+	args, err := parsing.Args(query)
+	if err != nil {
+		log.Error("Invalid arguments:", err)
+		return nil, false
+	}
+
+	return args, true
+}
+
+func countRequest() {
+	if globals.GetConfig().Stats.Enabled {
+		stat.Stats.IncrementTotalRequests()
+	}
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/log"
 	"github.com/taymour/elysiandb/internal/stat"
 	"github.com/taymour/elysiandb/internal/storage"
 )
@@ -11,6 +12,10 @@ func HandleSave() []byte {
 		stat.Stats.IncrementTotalRequests()
 	}
 
-	storage.WriteToDB()
+	if err := storage.WriteToDB(); err != nil {
+		log.Error("Failed to save database:", err)
+		return []byte("ERR")
+	}
+
 	return []byte("OK")
 }
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/taymour/elysiandb/internal/transport/tcp/parsing"
+)
+
+func HandleSAdd(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 2 {
+		return usageReply("SADD <key> <member> [member ...]")
+	}
+
+	added, err := storage.SAdd(args[0], args[1:])
+	if err != nil {
+		return errReply(err)
+	}
+
+	return intReply(int64(added))
+}
+
+func HandleSRem(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 2 {
+		return usageReply("SREM <key> <member> [member ...]")
+	}
+
+	removed, err := storage.SRem(args[0], args[1:])
+	if err != nil {
+		return errReply(err)
+	}
+
+	return intReply(int64(removed))
+}
+
+func HandleSMembers(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 1 {
+		return usageReply("SMEMBERS <key>")
+	}
+
+	members, found, err := storage.SMembers(args[0])
+	if err != nil {
+		return errReply(err)
+	}
+	if !found {
+		return notFoundReply(args[0])
+	}
+
+	return joinLines(members)
+}
+
+func HandleSIsMember(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 2 {
+		return usageReply("SISMEMBER <key> <member>")
+	}
+
+	member, err := storage.SIsMember(args[0], args[1])
+	if err != nil {
+		return errReply(err)
+	}
+
+	return boolReply(member)
+}
+
+func HandleSInter(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 1 {
+		return usageReply("SINTER <key> [key ...]")
+	}
+
+	members, err := storage.SInter(args)
+	if err != nil {
+		return errReply(err)
+	}
+
+	return joinLines(members)
+}
+
+func HandleSUnion(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 1 {
+		return usageReply("SUNION <key> [key ...]")
+	}
+
+	members, err := storage.SUnion(args)
+	if err != nil {
+		return errReply(err)
+	}
+
+	return joinLines(members)
+}
+
+func joinLines(lines []string) []byte {
+	out := make([][]byte, len(lines))
+	for i, l := range lines {
+		out[i] = []byte(l)
+	}
+
+	return parsing.JoinByteSlices(out, []byte("\n"))
+}
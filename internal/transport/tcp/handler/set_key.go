@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"errors"
+	"time"
+
 	"github.com/taymour/elysiandb/internal/globals"
 	"github.com/taymour/elysiandb/internal/log"
 	"github.com/taymour/elysiandb/internal/stat"
@@ -8,7 +11,14 @@ import (
 	"github.com/taymour/elysiandb/internal/transport/tcp/parsing"
 )
 
-func HandleSet(query []byte, ttl int) []byte {
+type SetOptions struct {
+	TTL     time.Duration
+	SoftTTL time.Duration
+	Tags    []byte
+	Lease   []byte
+}
+
+func HandleSet(query []byte, opts SetOptions) []byte {
 	if globals.GetConfig().Stats.Enabled {
 		stat.Stats.IncrementTotalRequests()
 	}
@@ -19,13 +29,29 @@ func HandleSet(query []byte, ttl int) []byte {
 	val := make([]byte, len(v))
 	copy(val, v)
 
+	var tags []string
+	if opts.Tags != nil {
+		tags = storage.ParseTags(string(opts.Tags))
+	}
+
+	putOpts := storage.PutOptions{TTL: opts.TTL, SoftTTL: opts.SoftTTL, Tags: tags}
+
 	var err error
-	if ttl > 0 {
-		err = storage.PutKeyValueWithTTL(key, val, ttl)
-	} else {
+	switch {
+	case opts.Lease != nil:
+		err = storage.PutKeyValueWithLease(key, val, putOpts, string(opts.Lease))
+	case tags != nil || opts.SoftTTL > 0:
+		err = storage.PutKeyValueWithOptions(key, val, putOpts)
+	case opts.TTL > 0:
+		err = storage.PutKeyValueWithTTLDuration(key, val, opts.TTL)
+	default:
 		err = storage.PutKeyValue(key, val)
 	}
 
+	if errors.Is(err, storage.ErrKeyTooLarge) || errors.Is(err, storage.ErrValueTooLarge) || errors.Is(err, storage.ErrInvalidLease) {
+		return []byte("ERR " + err.Error())
+	}
+
 	if err != nil {
 		log.Error("Failed to store key-value pair:", err)
 		return []byte("ERR")
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/taymour/elysiandb/internal/stat"
+	"github.com/taymour/elysiandb/internal/transport/tcp/parsing"
+)
+
+func HandleSlowlog(query []byte) []byte {
+	sub, rest := parsing.FirstWordBytes(query)
+
+	switch {
+	case parsing.EqASCII(sub, []byte("GET")):
+		count := 10
+		if len(rest) > 0 {
+			n, err := parsing.ParseDecimalBytes(rest)
+			if err != nil {
+				return []byte("ERR")
+			}
+			count = n
+		}
+
+		entries := stat.Slowlog.Get(count)
+		if len(entries) == 0 {
+			return []byte("(empty)")
+		}
+
+		lines := make([][]byte, 0, len(entries))
+		for _, e := range entries {
+			lines = append(lines, []byte(fmt.Sprintf("%d %d %d %s %s %s",
+				e.ID, e.Timestamp.Unix(), e.DurationMicros, e.Protocol, e.Command, e.Key)))
+		}
+
+		return parsing.JoinByteSlices(lines, []byte("\n"))
+
+	case parsing.EqASCII(sub, []byte("LEN")):
+		return []byte(fmt.Sprintf("%d", stat.Slowlog.Len()))
+
+	case parsing.EqASCII(sub, []byte("RESET")):
+		stat.Slowlog.Reset()
+		return []byte("OK")
+	}
+
+	return []byte("ERR")
+}
@@ -0,0 +1,374 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/taymour/elysiandb/internal/transport/tcp/parsing"
+)
+
+type streamReadOptions struct {
+	count   int
+	block   bool
+	timeout time.Duration
+	keys    []string
+	ids     []string
+}
+
+func HandleXAdd(query []byte) []byte {
+	countRequest()
+
+	usage := "XADD <key> [MAXLEN n] [MAXAGE ms] <id|*> <field> <value> [field value ...]"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 4 {
+		return usageReply(usage)
+	}
+
+	key := args[0]
+	trim, rest, ok := parseStreamTrim(args[1:], true)
+	if !ok || len(rest) < 3 || len(rest)%2 != 1 {
+		return usageReply(usage)
+	}
+
+	fields := make([]storage.HashField, 0, len(rest)/2)
+	for i := 1; i+1 < len(rest); i += 2 {
+		fields = append(fields, storage.HashField{Field: rest[i], Value: []byte(rest[i+1])})
+	}
+
+	id, err := storage.XAdd(key, rest[0], fields, trim)
+	if err != nil {
+		return errReply(err)
+	}
+
+	return []byte(id.String())
+}
+
+func HandleXTrim(query []byte) []byte {
+	countRequest()
+
+	usage := "XTRIM <key> MAXLEN <n> | MAXAGE <ms>"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 3 {
+		return usageReply(usage)
+	}
+
+	trim, rest, ok := parseStreamTrim(args[1:], false)
+	if !ok || len(rest) != 0 {
+		return usageReply(usage)
+	}
+
+	removed, err := storage.XTrim(args[0], trim)
+	if err != nil {
+		return errReply(err)
+	}
+
+	return intReply(int64(removed))
+}
+
+func HandleXLen(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 1 {
+		return usageReply("XLEN <key>")
+	}
+
+	n, err := storage.XLen(args[0])
+	if err != nil {
+		return errReply(err)
+	}
+
+	return intReply(int64(n))
+}
+
+func HandleXRange(query []byte) []byte {
+	countRequest()
+
+	usage := "XRANGE <key> <start> <end> [COUNT n]"
+
+	args, ok := parseArgs(query)
+	if !ok || (len(args) != 3 && len(args) != 5) {
+		return usageReply(usage)
+	}
+
+	count := 0
+	if len(args) == 5 {
+		n, err := strconv.Atoi(args[4])
+		if !strings.EqualFold(args[3], "COUNT") || err != nil || n < 0 {
+			return usageReply(usage)
+		}
+		count = n
+	}
+
+	entries, found, err := storage.XRange(args[0], args[1], args[2], count)
+	if err != nil {
+		return errReply(err)
+	}
+	if !found {
+		return notFoundReply(args[0])
+	}
+
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = formatStreamEntry(e)
+	}
+
+	return joinLines(lines)
+}
+
+func HandleXRead(query []byte) []byte {
+	countRequest()
+
+	usage := "XREAD [COUNT n] [BLOCK ms] STREAMS <key> [key ...] <id> [id ...]"
+
+	args, ok := parseArgs(query)
+	if !ok {
+		return usageReply(usage)
+	}
+
+	opts, ok := parseStreamReadOptions(args)
+	if !ok {
+		return usageReply(usage)
+	}
+
+	reads, err := storage.XRead(opts.keys, opts.ids, opts.count, opts.block, opts.timeout, nil)
+	if err != nil {
+		return errReply(err)
+	}
+
+	return streamReadsReply(reads, opts.block)
+}
+
+func HandleXGroup(query []byte) []byte {
+	countRequest()
+
+	usage := "XGROUP CREATE <key> <group> <id|$> [MKSTREAM] | XGROUP DESTROY <key> <group>"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 3 {
+		return usageReply(usage)
+	}
+
+	switch strings.ToUpper(args[0]) {
+	case "CREATE":
+		if len(args) < 4 || len(args) > 5 {
+			return usageReply(usage)
+		}
+		mkstream := len(args) == 5
+		if mkstream && !strings.EqualFold(args[4], "MKSTREAM") {
+			return usageReply(usage)
+		}
+
+		if err := storage.XGroupCreate(args[1], args[2], args[3], mkstream); err != nil {
+			return errReply(err)
+		}
+		return []byte("OK")
+	case "DESTROY":
+		if len(args) != 3 {
+			return usageReply(usage)
+		}
+
+		destroyed, err := storage.XGroupDestroy(args[1], args[2])
+		if err != nil {
+			return errReply(err)
+		}
+		return boolReply(destroyed)
+	}
+
+	return usageReply(usage)
+}
+
+func HandleXReadGroup(query []byte) []byte {
+	countRequest()
+
+	usage := "XREADGROUP GROUP <group> <consumer> [COUNT n] [BLOCK ms] STREAMS <key> [key ...] <id|>> [id ...]"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 3 || !strings.EqualFold(args[0], "GROUP") {
+		return usageReply(usage)
+	}
+
+	opts, ok := parseStreamReadOptions(args[3:])
+	if !ok {
+		return usageReply(usage)
+	}
+
+	reads, err := storage.XReadGroup(args[1], args[2], opts.keys, opts.ids, opts.count, opts.block, opts.timeout, nil)
+	if err != nil {
+		return errReply(err)
+	}
+
+	return streamReadsReply(reads, opts.block)
+}
+
+func HandleXAck(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 3 {
+		return usageReply("XACK <key> <group> <id> [id ...]")
+	}
+
+	acked, err := storage.XAck(args[0], args[1], args[2:])
+	if err != nil {
+		return errReply(err)
+	}
+
+	return intReply(int64(acked))
+}
+
+func HandleXPending(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 2 || len(args) > 3 {
+		return usageReply("XPENDING <key> <group> [consumer]")
+	}
+
+	consumer := ""
+	if len(args) == 3 {
+		consumer = args[2]
+	}
+
+	pending, err := storage.XPending(args[0], args[1], consumer)
+	if err != nil {
+		return errReply(err)
+	}
+
+	lines := make([]string, len(pending))
+	for i, p := range pending {
+		lines[i] = p.ID.String() + " " + parsing.Quote(p.Consumer) + " " +
+			strconv.FormatInt(p.Idle.Milliseconds(), 10) + " " + strconv.Itoa(p.Deliveries)
+	}
+
+	return joinLines(lines)
+}
+
+func HandleXClaim(query []byte) []byte {
+	countRequest()
+
+	usage := "XCLAIM <key> <group> <consumer> <min-idle-ms> <id> [id ...]"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 5 {
+		return usageReply(usage)
+	}
+
+	minIdle, err := strconv.ParseInt(args[3], 10, 64)
+	if err != nil || minIdle < 0 {
+		return usageReply(usage)
+	}
+
+	entries, err := storage.XClaim(args[0], args[1], args[2], time.Duration(minIdle)*time.Millisecond, args[4:])
+	if err != nil {
+		return errReply(err)
+	}
+
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = formatStreamEntry(e)
+	}
+
+	return joinLines(lines)
+}
+
+func parseStreamTrim(args []string, optional bool) (storage.StreamTrim, []string, bool) {
+	var trim storage.StreamTrim
+
+	for len(args) >= 2 {
+		name := strings.ToUpper(args[0])
+		if name != "MAXLEN" && name != "MAXAGE" {
+			break
+		}
+
+		n, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || n < 0 {
+			return trim, nil, false
+		}
+
+		if name == "MAXLEN" {
+			trim.MaxLen, trim.ByLength = int(n), true
+		} else {
+			trim.MaxAge = time.Duration(n) * time.Millisecond
+		}
+		args = args[2:]
+	}
+
+	if !optional && !trim.ByLength && trim.MaxAge == 0 {
+		return trim, nil, false
+	}
+
+	return trim, args, true
+}
+
+func parseStreamReadOptions(args []string) (streamReadOptions, bool) {
+	var opts streamReadOptions
+
+	for len(args) > 0 {
+		switch strings.ToUpper(args[0]) {
+		case "COUNT":
+			if len(args) < 2 {
+				return opts, false
+			}
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 0 {
+				return opts, false
+			}
+			opts.count = n
+			args = args[2:]
+		case "BLOCK":
+			if len(args) < 2 {
+				return opts, false
+			}
+			ms, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil || ms < 0 {
+				return opts, false
+			}
+			opts.block, opts.timeout = true, time.Duration(ms)*time.Millisecond
+			args = args[2:]
+		case "STREAMS":
+			rest := args[1:]
+			if len(rest) == 0 || len(rest)%2 != 0 {
+				return opts, false
+			}
+			opts.keys, opts.ids = rest[:len(rest)/2], rest[len(rest)/2:]
+			return opts, true
+		default:
+			return opts, false
+		}
+	}
+
+	return opts, false
+}
+
+func formatStreamEntry(e storage.StreamEntry) string {
+	var b strings.Builder
+	b.WriteString(e.ID.String())
+	for _, f := range e.Fields {
+		b.WriteByte(' ')
+		b.WriteString(parsing.Quote(f.Field))
+		b.WriteByte(' ')
+		b.WriteString(parsing.Quote(string(f.Value)))
+	}
+
+	return b.String()
+}
+
+func streamReadsReply(reads []storage.StreamRead, block bool) []byte {
+	if len(reads) == 0 && block {
+		return []byte("TIMEOUT")
+	}
+
+	lines := make([]string, 0)
+	for _, r := range reads {
+		for _, e := range r.Entries {
+			lines = append(lines, parsing.Quote(r.Key)+" "+formatStreamEntry(e))
+		}
+	}
+
+	return joinLines(lines)
+}
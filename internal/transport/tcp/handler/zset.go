@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func HandleZAdd(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 3 || len(args)%2 != 1 {
+		return usageReply("ZADD <key> <score> <member> [score member ...]")
+	}
+
+	members := make([]storage.ScoredMember, 0, len(args)/2)
+	for i := 1; i+1 < len(args); i += 2 {
+		score, err := strconv.ParseFloat(args[i], 64)
+		if err != nil {
+			return errReply(storage.ErrNotFloat)
+		}
+		members = append(members, storage.ScoredMember{Member: args[i+1], Score: score})
+	}
+
+	added, err := storage.ZAdd(args[0], members)
+	if err != nil {
+		return errReply(err)
+	}
+
+	return intReply(int64(added))
+}
+
+func HandleZIncrBy(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 3 {
+		return usageReply("ZINCRBY <key> <delta> <member>")
+	}
+
+	delta, err := strconv.ParseFloat(args[1], 64)
+	if err != nil {
+		return errReply(storage.ErrNotFloat)
+	}
+
+	score, err := storage.ZIncrBy(args[0], args[2], delta)
+	if err != nil {
+		return errReply(err)
+	}
+
+	return []byte(formatScore(score))
+}
+
+func HandleZRem(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 2 {
+		return usageReply("ZREM <key> <member> [member ...]")
+	}
+
+	removed, err := storage.ZRem(args[0], args[1:])
+	if err != nil {
+		return errReply(err)
+	}
+
+	return intReply(int64(removed))
+}
+
+func HandleZRange(query []byte) []byte {
+	countRequest()
+
+	usage := "ZRANGE <key> <start> <stop> [WITHSCORES]"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 3 || len(args) > 4 {
+		return usageReply(usage)
+	}
+
+	withScores := len(args) == 4
+	if withScores && !strings.EqualFold(args[3], "WITHSCORES") {
+		return usageReply(usage)
+	}
+
+	start, stop, ok := parseRange(args[1], args[2])
+	if !ok {
+		return usageReply(usage)
+	}
+
+	members, found, err := storage.ZRange(args[0], start, stop)
+	if err != nil {
+		return errReply(err)
+	}
+	if !found {
+		return notFoundReply(args[0])
+	}
+
+	return scoredReply(members, withScores)
+}
+
+func HandleZRangeByScore(query []byte) []byte {
+	countRequest()
+
+	usage := "ZRANGEBYSCORE <key> <min> <max> [WITHSCORES] [LIMIT offset count]"
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) < 3 {
+		return usageReply(usage)
+	}
+
+	var r storage.ScoreRange
+	if r.Min, r.MinExclusive, ok = storage.ParseScoreBound(args[1]); !ok {
+		return errReply(storage.ErrNotFloat)
+	}
+	if r.Max, r.MaxExclusive, ok = storage.ParseScoreBound(args[2]); !ok {
+		return errReply(storage.ErrNotFloat)
+	}
+
+	withScores := false
+	offset, count := 0, -1
+	for rest := args[3:]; len(rest) > 0; {
+		switch {
+		case strings.EqualFold(rest[0], "WITHSCORES"):
+			withScores = true
+			rest = rest[1:]
+		case strings.EqualFold(rest[0], "LIMIT") && len(rest) >= 3:
+			o, err1 := strconv.Atoi(rest[1])
+			c, err2 := strconv.Atoi(rest[2])
+			if err1 != nil || err2 != nil || o < 0 {
+				return usageReply(usage)
+			}
+			offset, count = o, c
+			rest = rest[3:]
+		default:
+			return usageReply(usage)
+		}
+	}
+
+	members, found, err := storage.ZRangeByScore(args[0], r, offset, count)
+	if err != nil {
+		return errReply(err)
+	}
+	if !found {
+		return notFoundReply(args[0])
+	}
+
+	return scoredReply(members, withScores)
+}
+
+func HandleZRank(query []byte) []byte {
+	countRequest()
+
+	args, ok := parseArgs(query)
+	if !ok || len(args) != 2 {
+		return usageReply("ZRANK <key> <member>")
+	}
+
+	rank, _, found, err := storage.ZRank(args[0], args[1])
+	if err != nil {
+		return errReply(err)
+	}
+	if !found {
+		return notFoundReply(args[1])
+	}
+
+	return intReply(int64(rank))
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
+
+func scoredReply(members []storage.ScoredMember, withScores bool) []byte {
+	lines := make([]string, len(members))
+	for i, m := range members {
+		if withScores {
+			lines[i] = m.Member + "=" + formatScore(m.Score)
+		} else {
+			lines[i] = m.Member
+		}
+	}
+
+	return joinLines(lines)
+}
@@ -2,6 +2,7 @@ package parsing
 
 import (
 	"fmt"
+	"strings"
 )
 
 func FirstWordBytes(b []byte) (cmd, rest []byte) {
@@ -102,4 +103,94 @@ func JoinByteSlices(slices [][]byte, sep []byte) []byte {
 	}
 
 	return result
-}
\ No newline at end of file
+}
+
+func UpperASCII(b []byte, dst []byte) []byte {
+	dst = dst[:len(b)]
+	for i, c := range b {
+		if 'a' <= c && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		dst[i] = c
+	}
+
+	return dst
+}
+
+func Args(b []byte) ([]string, error) {
+	args := make([]string, 0, 4)
+
+	i := 0
+	for {
+		for i < len(b) && (b[i] == ' ' || b[i] == '\t' || b[i] == '\r' || b[i] == '\n') {
+			i++
+		}
+		if i >= len(b) {
+			return args, nil
+		}
+
+		if b[i] != '"' {
+			start := i
+			for i < len(b) && b[i] != ' ' && b[i] != '\t' && b[i] != '\r' && b[i] != '\n' {
+				i++
+			}
+			args = append(args, string(b[start:i]))
+			continue
+		}
+
+		i++
+		arg := make([]byte, 0, 16)
+		closed := false
+		for i < len(b) {
+			c := b[i]
+			i++
+			if c == '"' {
+				closed = true
+				break
+			}
+			if c == '\\' && i < len(b) {
+				switch b[i] {
+				case 'n':
+					c = '\n'
+				case 't':
+					c = '\t'
+				default:
+					c = b[i]
+				}
+				i++
+			}
+			arg = append(arg, c)
+		}
+
+		if !closed {
+			return nil, fmt.Errorf("unbalanced quotes")
+		}
+		args = append(args, string(arg))
+	}
+}
+
+func Quote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\r\n\"\\") {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
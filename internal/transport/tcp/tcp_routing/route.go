@@ -1,61 +1,472 @@
 package tcprouting
 
 import (
+	"bytes"
 	"net"
+	"time"
 
+	"github.com/taymour/elysiandb/internal/globals"
 	"github.com/taymour/elysiandb/internal/log"
+	"github.com/taymour/elysiandb/internal/stat"
 	"github.com/taymour/elysiandb/internal/transport/tcp/handler"
 	"github.com/taymour/elysiandb/internal/transport/tcp/parsing"
 )
 
-func RouteLine(line []byte, c net.Conn) []byte {
-	cmd, query := parsing.FirstWordBytes(line)
+type commandHandler func(query []byte, c net.Conn) []byte
 
-	switch {
-	case parsing.EqASCII(cmd, []byte("PING")):
+type command struct {
+	name     string
+	handle   commandHandler
+	blocking bool
+}
+
+const maxCommandLength = 32
+
+var commands = map[string]command{}
+
+func register(name string, h commandHandler) {
+	commands[name] = command{name: name, handle: h}
+}
+
+func registerBlocking(name string, h commandHandler) {
+	commands[name] = command{name: name, handle: h, blocking: true}
+}
+
+func init() {
+	register("PING", func(query []byte, c net.Conn) []byte {
 		return []byte("PONG")
+	})
 
-	case parsing.EqASCII(cmd, []byte("EXIT")):
+	register("EXIT", func(query []byte, c net.Conn) []byte {
 		_ = c.Close()
 		return []byte("Goodbye!")
+	})
 
-	case parsing.EqASCII(cmd, []byte("GET")):
+	register("GET", func(query []byte, c net.Conn) []byte {
 		return handler.HandleGet(query)
+	})
 
-	case parsing.EqASCII(cmd, []byte("MGET")):
+	register("MGET", func(query []byte, c net.Conn) []byte {
 		return handler.HandleMultiGet(query)
+	})
+
+	register("SET", func(query []byte, c net.Conn) []byte {
+		return handler.HandleSet(query, extractSetOptions(&query))
+	})
+
+	register("EXPIRE", func(query []byte, c net.Conn) []byte {
+		return handler.HandleExpire(query, time.Second)
+	})
+
+	register("PEXPIRE", func(query []byte, c net.Conn) []byte {
+		return handler.HandleExpire(query, time.Millisecond)
+	})
+
+	register("EXPIREAT", func(query []byte, c net.Conn) []byte {
+		return handler.HandleExpireAt(query, time.Second)
+	})
+
+	register("PEXPIREAT", func(query []byte, c net.Conn) []byte {
+		return handler.HandleExpireAt(query, time.Millisecond)
+	})
 
-	case parsing.EqASCII(cmd, []byte("SET")):
-		ttl := extractTTLFromQuery(&query)
-		return handler.HandleSet(query, ttl)
+	register("TTL", func(query []byte, c net.Conn) []byte {
+		return handler.HandleTTL(query, time.Second)
+	})
 
-	case parsing.EqASCII(cmd, []byte("DEL")):
+	register("PTTL", func(query []byte, c net.Conn) []byte {
+		return handler.HandleTTL(query, time.Millisecond)
+	})
+
+	register("EXISTS", func(query []byte, c net.Conn) []byte {
+		return handler.HandleExists(query)
+	})
+
+	register("PERSIST", func(query []byte, c net.Conn) []byte {
+		return handler.HandlePersist(query)
+	})
+
+	register("DEL", func(query []byte, c net.Conn) []byte {
 		return handler.HandleDelete(query)
+	})
+
+	register("HSET", func(query []byte, c net.Conn) []byte {
+		return handler.HandleHSet(query)
+	})
+
+	register("HGET", func(query []byte, c net.Conn) []byte {
+		return handler.HandleHGet(query)
+	})
+
+	register("HMGET", func(query []byte, c net.Conn) []byte {
+		return handler.HandleHMGet(query)
+	})
+
+	register("HDEL", func(query []byte, c net.Conn) []byte {
+		return handler.HandleHDel(query)
+	})
+
+	register("HGETALL", func(query []byte, c net.Conn) []byte {
+		return handler.HandleHGetAll(query)
+	})
+
+	register("HINCRBY", func(query []byte, c net.Conn) []byte {
+		return handler.HandleHIncrBy(query)
+	})
+
+	register("LPUSH", func(query []byte, c net.Conn) []byte {
+		return handler.HandlePush(query, true)
+	})
+
+	register("RPUSH", func(query []byte, c net.Conn) []byte {
+		return handler.HandlePush(query, false)
+	})
+
+	register("LPOP", func(query []byte, c net.Conn) []byte {
+		return handler.HandlePop(query, true)
+	})
+
+	register("RPOP", func(query []byte, c net.Conn) []byte {
+		return handler.HandlePop(query, false)
+	})
+
+	registerBlocking("BLPOP", func(query []byte, c net.Conn) []byte {
+		return handler.HandleBlockingPop(query, true)
+	})
+
+	registerBlocking("BRPOP", func(query []byte, c net.Conn) []byte {
+		return handler.HandleBlockingPop(query, false)
+	})
+
+	register("LRANGE", func(query []byte, c net.Conn) []byte {
+		return handler.HandleLRange(query)
+	})
+
+	register("LLEN", func(query []byte, c net.Conn) []byte {
+		return handler.HandleLLen(query)
+	})
+
+	register("LTRIM", func(query []byte, c net.Conn) []byte {
+		return handler.HandleLTrim(query)
+	})
+
+	register("SADD", func(query []byte, c net.Conn) []byte {
+		return handler.HandleSAdd(query)
+	})
+
+	register("SREM", func(query []byte, c net.Conn) []byte {
+		return handler.HandleSRem(query)
+	})
+
+	register("SMEMBERS", func(query []byte, c net.Conn) []byte {
+		return handler.HandleSMembers(query)
+	})
+
+	register("SISMEMBER", func(query []byte, c net.Conn) []byte {
+		return handler.HandleSIsMember(query)
+	})
+
+	register("SINTER", func(query []byte, c net.Conn) []byte {
+		return handler.HandleSInter(query)
+	})
+
+	register("SUNION", func(query []byte, c net.Conn) []byte {
+		return handler.HandleSUnion(query)
+	})
+
+	register("ZADD", func(query []byte, c net.Conn) []byte {
+		return handler.HandleZAdd(query)
+	})
+
+	register("ZINCRBY", func(query []byte, c net.Conn) []byte {
+		return handler.HandleZIncrBy(query)
+	})
+
+	register("ZRANGE", func(query []byte, c net.Conn) []byte {
+		return handler.HandleZRange(query)
+	})
+
+	register("ZRANGEBYSCORE", func(query []byte, c net.Conn) []byte {
+		return handler.HandleZRangeByScore(query)
+	})
+
+	register("ZRANK", func(query []byte, c net.Conn) []byte {
+		return handler.HandleZRank(query)
+	})
+
+	register("ZREM", func(query []byte, c net.Conn) []byte {
+		return handler.HandleZRem(query)
+	})
+
+	register("XADD", func(query []byte, c net.Conn) []byte {
+		return handler.HandleXAdd(query)
+	})
+
+	register("XTRIM", func(query []byte, c net.Conn) []byte {
+		return handler.HandleXTrim(query)
+	})
+
+	register("XLEN", func(query []byte, c net.Conn) []byte {
+		return handler.HandleXLen(query)
+	})
+
+	register("XRANGE", func(query []byte, c net.Conn) []byte {
+		return handler.HandleXRange(query)
+	})
+
+	registerBlocking("XREAD", func(query []byte, c net.Conn) []byte {
+		return handler.HandleXRead(query)
+	})
+
+	register("XGROUP", func(query []byte, c net.Conn) []byte {
+		return handler.HandleXGroup(query)
+	})
+
+	registerBlocking("XREADGROUP", func(query []byte, c net.Conn) []byte {
+		return handler.HandleXReadGroup(query)
+	})
+
+	register("XACK", func(query []byte, c net.Conn) []byte {
+		return handler.HandleXAck(query)
+	})
+
+	register("XPENDING", func(query []byte, c net.Conn) []byte {
+		return handler.HandleXPending(query)
+	})
+
+	register("XCLAIM", func(query []byte, c net.Conn) []byte {
+		return handler.HandleXClaim(query)
+	})
+
+	register("JSON.SET", func(query []byte, c net.Conn) []byte {
+		return handler.HandleJSONSet(query)
+	})
+
+	register("JSON.GET", func(query []byte, c net.Conn) []byte {
+		return handler.HandleJSONGet(query)
+	})
+
+	register("PFADD", func(query []byte, c net.Conn) []byte {
+		return handler.HandlePFAdd(query)
+	})
+
+	register("PFCOUNT", func(query []byte, c net.Conn) []byte {
+		return handler.HandlePFCount(query)
+	})
+
+	register("PFMERGE", func(query []byte, c net.Conn) []byte {
+		return handler.HandlePFMerge(query)
+	})
 
-	case parsing.EqASCII(cmd, []byte("RESET")):
+	register("BF.RESERVE", func(query []byte, c net.Conn) []byte {
+		return handler.HandleBFReserve(query)
+	})
+
+	register("BF.ADD", func(query []byte, c net.Conn) []byte {
+		return handler.HandleBFAdd(query)
+	})
+
+	register("BF.EXISTS", func(query []byte, c net.Conn) []byte {
+		return handler.HandleBFExists(query)
+	})
+
+	register("GEOADD", func(query []byte, c net.Conn) []byte {
+		return handler.HandleGeoAdd(query)
+	})
+
+	register("GEOPOS", func(query []byte, c net.Conn) []byte {
+		return handler.HandleGeoPos(query)
+	})
+
+	register("GEODIST", func(query []byte, c net.Conn) []byte {
+		return handler.HandleGeoDist(query)
+	})
+
+	register("GEOSEARCH", func(query []byte, c net.Conn) []byte {
+		return handler.HandleGeoSearch(query)
+	})
+
+	registerBlocking("LOCK", func(query []byte, c net.Conn) []byte {
+		return handler.HandleLock(query)
+	})
+
+	register("RENEW", func(query []byte, c net.Conn) []byte {
+		return handler.HandleRenew(query)
+	})
+
+	register("UNLOCK", func(query []byte, c net.Conn) []byte {
+		return handler.HandleUnlock(query)
+	})
+
+	register("RATELIMIT", func(query []byte, c net.Conn) []byte {
+		return handler.HandleRateLimit(query)
+	})
+
+	register("ENQUEUE", func(query []byte, c net.Conn) []byte {
+		return handler.HandleEnqueue(query)
+	})
+
+	register("RESERVE", func(query []byte, c net.Conn) []byte {
+		return handler.HandleReserve(query)
+	})
+
+	register("ACK", func(query []byte, c net.Conn) []byte {
+		return handler.HandleAck(query)
+	})
+
+	register("NACK", func(query []byte, c net.Conn) []byte {
+		return handler.HandleNack(query)
+	})
+
+	register("TOUCH", func(query []byte, c net.Conn) []byte {
+		return handler.HandleTouch(query)
+	})
+
+	register("QINFO", func(query []byte, c net.Conn) []byte {
+		return handler.HandleQInfo(query)
+	})
+
+	register("QDEAD", func(query []byte, c net.Conn) []byte {
+		return handler.HandleQDead(query)
+	})
+
+	registerBlocking("GETLEASE", func(query []byte, c net.Conn) []byte {
+		return handler.HandleGetLease(query)
+	})
+
+	register("INVALIDATE", func(query []byte, c net.Conn) []byte {
+		return handler.HandleInvalidate(query)
+	})
+
+	register("RESET", func(query []byte, c net.Conn) []byte {
 		return handler.HandleReset()
+	})
 
-	case parsing.EqASCII(cmd, []byte("SAVE")):
+	register("SAVE", func(query []byte, c net.Conn) []byte {
 		return handler.HandleSave()
+	})
+
+	register("SLOWLOG", func(query []byte, c net.Conn) []byte {
+		return handler.HandleSlowlog(query)
+	})
+}
+
+func RouteLine(line []byte, c net.Conn) []byte {
+	cmd, query := parsing.FirstWordBytes(line)
+
+	var buf [maxCommandLength]byte
+	entry, ok := lookup(cmd, buf[:])
+	if !ok {
+		log.Error("Unknown command:", string(cmd))
+		return []byte("ERR")
+	}
+
+	statsEnabled := globals.GetConfig().Stats.Enabled
+	if !statsEnabled && !stat.Slowlog.Enabled() {
+		return entry.handle(query, c)
 	}
 
-	log.Error("Unknown command:", string(cmd))
+	start := time.Now()
+	resp := entry.handle(query, c)
+	elapsed := time.Since(start)
 
-	return []byte("ERR")
+	if statsEnabled {
+		stat.Stats.ObserveCommand(stat.ProtocolTCP, entry.name, elapsed)
+	}
+
+	if !entry.blocking && stat.Slowlog.IsSlow(elapsed) {
+		stat.Slowlog.Add(stat.ProtocolTCP, entry.name, slowlogKey(query), start, elapsed)
+	}
+
+	return resp
+}
+
+func slowlogKey(query []byte) string {
+	for {
+		word, rest := parsing.FirstWordBytes(query)
+		if bytes.IndexByte(word, '=') < 0 || len(rest) == 0 {
+			return string(word)
+		}
+		query = rest
+	}
+}
+
+func lookup(cmd []byte, buf []byte) (command, bool) {
+	if len(cmd) == 0 || len(cmd) > len(buf) {
+		return command{}, false
+	}
+
+	upper := parsing.UpperASCII(cmd, buf)
+	c, ok := commands[string(upper)]
+
+	return c, ok
+}
+
+func extractTTLFromQuery(query *[]byte) time.Duration {
+	return extractDurationFromQuery(query, "TTL=", "PX=")
+}
+
+func extractSoftTTLFromQuery(query *[]byte) time.Duration {
+	return extractDurationFromQuery(query, "SOFT=", "SOFTPX=")
+}
+
+func extractDurationFromQuery(query *[]byte, secPrefix string, msPrefix string) time.Duration {
+	param, rest := parsing.FirstWordBytes(*query)
+
+	var unit time.Duration
+	var digits []byte
+	switch {
+	case len(param) >= len(secPrefix) && parsing.EqASCII(param[:len(secPrefix)], []byte(secPrefix)):
+		unit, digits = time.Second, param[len(secPrefix):]
+	case len(param) >= len(msPrefix) && parsing.EqASCII(param[:len(msPrefix)], []byte(msPrefix)):
+		unit, digits = time.Millisecond, param[len(msPrefix):]
+	default:
+		return 0
+	}
+
+	d, err := parsing.ParseDecimalBytes(digits)
+	if err != nil || d < 0 {
+		return 0
+	}
+
+	*query = rest
+
+	return time.Duration(d) * unit
 }
 
-func extractTTLFromQuery(query *[]byte) int {
-	ttlParam, rest := parsing.FirstWordBytes(*query)
-	if parsing.EqASCII(ttlParam[:4], []byte("TTL=")) {
-		ttl, err := parsing.ParseDecimalBytes(ttlParam[4:])
-		if err != nil || ttl < 0 {
-			return 0
+func extractSetOptions(query *[]byte) handler.SetOptions {
+	var opts handler.SetOptions
+
+	for {
+		before := len(*query)
+		if ttl := extractTTLFromQuery(query); len(*query) != before {
+			opts.TTL = ttl
+			continue
+		}
+		if soft := extractSoftTTLFromQuery(query); len(*query) != before {
+			opts.SoftTTL = soft
+			continue
+		}
+		if tags, ok := extractOption(query, "TAGS="); ok {
+			opts.Tags = tags
+			continue
+		}
+		if lease, ok := extractOption(query, "LEASE="); ok {
+			opts.Lease = lease
+			continue
 		}
 
-		*query = rest
+		return opts
+	}
+}
 
-		return ttl
+func extractOption(query *[]byte, prefix string) ([]byte, bool) {
+	param, rest := parsing.FirstWordBytes(*query)
+	if len(param) < len(prefix) || !parsing.EqASCII(param[:len(prefix)], []byte(prefix)) {
+		return nil, false
 	}
 
-	return 0
+	*query = rest
+
+	return param[len(prefix):], true
 }
@@ -2,6 +2,7 @@ package e2e
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -624,3 +625,46 @@ func TestDELETESingle_HTTP(t *testing.T) {
 		t.Fatalf("unexpected 404 body: %+v", e)
 	}
 }
+
+func TestMetrics_OpenMetricsFormat(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.Header.SetMethod(fasthttp.MethodPut)
+	req.SetRequestURI("http://test/kv/foo")
+	req.SetBodyString("bar")
+	if err := client.Do(req, resp); err != nil {
+		t.Fatalf("PUT failed: %v", err)
+	}
+
+	req.Reset()
+	resp.Reset()
+	req.Header.SetMethod(fasthttp.MethodGet)
+	req.SetRequestURI("http://test/metrics")
+	if err := client.Do(req, resp); err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	if sc := resp.StatusCode(); sc != fasthttp.StatusOK {
+		t.Fatalf("expected 200 from /metrics, got %d", sc)
+	}
+	if ct := string(resp.Header.ContentType()); !strings.HasPrefix(ct, "application/openmetrics-text") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	body := string(resp.Body())
+	for _, want := range []string{
+		"# TYPE elysiandb_keys gauge",
+		`elysiandb_command_requests_total{protocol="http",command="PUT /kv/{key}"}`,
+		`elysiandb_request_duration_seconds_count{protocol="http"}`,
+		"# EOF",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("metrics body missing %q:\n%s", want, body)
+		}
+	}
+}
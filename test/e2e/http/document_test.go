@@ -0,0 +1,44 @@
+package e2e
+
+import (
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestDocument_HTTPRoutes(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	sc, _ := doRequest(t, client, fasthttp.MethodPut, "/doc/user:1", `{"name":"Ada","address":{"city":"London"}}`)
+	if sc != fasthttp.StatusNoContent {
+		t.Fatalf("PUT doc: expected 204, got %d", sc)
+	}
+
+	if sc, body := doRequest(t, client, fasthttp.MethodGet, "/doc/user:1?path=$.address.city", ""); sc != fasthttp.StatusOK || string(body) != `"London"` {
+		t.Fatalf("GET path: %d %s", sc, body)
+	}
+
+	sc, body := doRequest(t, client, fasthttp.MethodPatch, "/doc/user:1", `{"address":{"city":"Paris"},"name":null}`)
+	if sc != fasthttp.StatusOK || string(body) != `{"address":{"city":"Paris"}}` {
+		t.Fatalf("merge patch: %d %s", sc, body)
+	}
+
+	sc, body = doRequest(t, client, fasthttp.MethodPatch, "/doc/user:1", `[{"op":"add","path":"/tags","value":["a"]}]`)
+	if sc != fasthttp.StatusOK || string(body) != `{"address":{"city":"Paris"},"tags":["a"]}` {
+		t.Fatalf("json patch: %d %s", sc, body)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPatch, "/doc/user:1", `[{"op":"test","path":"/tags/0","value":"b"}]`); sc != fasthttp.StatusConflict {
+		t.Fatalf("failed test op: expected 409, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodGet, "/doc/user:1?path=$.zip", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("missing path: expected 404, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodGet, "/doc/missing", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("missing doc: expected 404, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPut, "/doc/bad", `{"a":`); sc != fasthttp.StatusBadRequest {
+		t.Fatalf("invalid JSON: expected 400, got %d", sc)
+	}
+}
@@ -0,0 +1,62 @@
+package e2e
+
+import (
+	"math"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestGeo_HTTPRoutes(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	sc, body := doRequest(t, client, fasthttp.MethodPut, "/geo/sicily",
+		`[{"member":"Palermo","lon":13.361389,"lat":38.115556},{"member":"Catania","lon":15.087269,"lat":37.502669}]`)
+	var added map[string]int
+	mustBodyJSON(t, body, &added)
+	if sc != fasthttp.StatusOK || added["added"] != 2 {
+		t.Fatalf("PUT geo: %d %s", sc, body)
+	}
+
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/geo/sicily/pos?members=Catania,Nowhere", "")
+	var points []*struct {
+		Member string  `json:"member"`
+		Lon    float64 `json:"lon"`
+	}
+	mustBodyJSON(t, body, &points)
+	if len(points) != 2 || points[0] == nil || math.Abs(points[0].Lon-15.087269) > 1e-5 || points[1] != nil {
+		t.Fatalf("pos: %s", body)
+	}
+
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/geo/sicily/dist?from=Palermo&to=Catania&unit=km", "")
+	var dist struct {
+		Distance float64 `json:"distance"`
+		Unit     string  `json:"unit"`
+	}
+	mustBodyJSON(t, body, &dist)
+	if math.Abs(dist.Distance-166.274) > 0.01 || dist.Unit != "km" {
+		t.Fatalf("dist: %s", body)
+	}
+
+	type match struct {
+		Member   string  `json:"member"`
+		Distance float64 `json:"distance"`
+	}
+	var matches []match
+	sc, body = doRequest(t, client, fasthttp.MethodGet, "/geo/sicily/search?lon=15&lat=37&radius=200&unit=km&order=desc", "")
+	mustBodyJSON(t, body, &matches)
+	if sc != fasthttp.StatusOK || len(matches) != 2 || matches[0].Member != "Palermo" || math.Abs(matches[1].Distance-56.44) > 0.01 {
+		t.Fatalf("search: %d %s", sc, body)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodGet, "/geo/sicily/search?member=Nowhere&radius=1", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("search from missing member: expected 404, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodGet, "/geo/sicily/search?lon=15&lat=37", ""); sc != fasthttp.StatusBadRequest {
+		t.Fatalf("search without shape: expected 400, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPut, "/geo/sicily", `[{"member":"x","lon":0,"lat":89}]`); sc != fasthttp.StatusBadRequest {
+		t.Fatalf("invalid coordinates: expected 400, got %d", sc)
+	}
+}
@@ -0,0 +1,74 @@
+package e2e
+
+import (
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestHash_HTTPRoutes(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	sc, body := doRequest(t, client, fasthttp.MethodPut, "/hash/user:1", `{"name":"Ada","visits":"1"}`)
+	if sc != fasthttp.StatusOK {
+		t.Fatalf("PUT hash: expected 200, got %d (%s)", sc, body)
+	}
+	var added map[string]int
+	mustBodyJSON(t, body, &added)
+	if added["added"] != 2 {
+		t.Fatalf("expected 2 added fields, got %v", added)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPut, "/hash/user:1/city", "Paris"); sc != fasthttp.StatusNoContent {
+		t.Fatalf("PUT field: expected 204, got %d", sc)
+	}
+
+	sc, body = doRequest(t, client, fasthttp.MethodGet, "/hash/user:1/city", "")
+	var field struct {
+		Field string  `json:"field"`
+		Value *string `json:"value"`
+	}
+	mustBodyJSON(t, body, &field)
+	if sc != fasthttp.StatusOK || field.Value == nil || *field.Value != "Paris" {
+		t.Fatalf("GET field: %d %s", sc, body)
+	}
+
+	sc, body = doRequest(t, client, fasthttp.MethodPost, "/hash/user:1/visits/incr?by=41", "")
+	var incr map[string]int64
+	mustBodyJSON(t, body, &incr)
+	if sc != fasthttp.StatusOK || incr["value"] != 42 {
+		t.Fatalf("incr: %d %s", sc, body)
+	}
+
+	sc, body = doRequest(t, client, fasthttp.MethodGet, "/hash/user:1", "")
+	var all map[string]string
+	mustBodyJSON(t, body, &all)
+	if sc != fasthttp.StatusOK || len(all) != 3 || all["visits"] != "42" || all["name"] != "Ada" {
+		t.Fatalf("GET hash: %d %s", sc, body)
+	}
+
+	sc, body = doRequest(t, client, fasthttp.MethodGet, "/hash/user:1?fields=name,nope", "")
+	var some map[string]*string
+	mustBodyJSON(t, body, &some)
+	if sc != fasthttp.StatusOK || some["name"] == nil || *some["name"] != "Ada" || some["nope"] != nil {
+		t.Fatalf("GET hash fields: %d %s", sc, body)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodGet, "/kv/user:1", ""); sc != fasthttp.StatusConflict {
+		t.Fatalf("GET /kv on hash: expected 409, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/hash/user:1/name/incr", ""); sc != fasthttp.StatusBadRequest {
+		t.Fatalf("incr on non-integer: expected 400, got %d", sc)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodDelete, "/hash/user:1/city", ""); sc != fasthttp.StatusNoContent {
+		t.Fatalf("DELETE field: expected 204, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodDelete, "/hash/user:1/city", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("DELETE missing field: expected 404, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodGet, "/hash/missing", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("GET missing hash: expected 404, got %d", sc)
+	}
+}
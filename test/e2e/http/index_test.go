@@ -0,0 +1,72 @@
+package e2e
+
+import (
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestIndex_HTTPRoutes(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	doRequest(t, client, fasthttp.MethodPut, "/kv/user:1", `{"email":"ada@example.com","age":36}`)
+	doRequest(t, client, fasthttp.MethodPut, "/doc/user:2", `{"email":"bob@example.com","age":25}`)
+
+	sc, _ := doRequest(t, client, fasthttp.MethodPost, "/indexes", `{"name":"by_email","pattern":"user:*","path":"$.email"}`)
+	if sc != fasthttp.StatusCreated {
+		t.Fatalf("create index: expected 201, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/indexes", `{"name":"by_email","pattern":"user:*","path":"$.email"}`); sc != fasthttp.StatusConflict {
+		t.Fatalf("duplicate index: expected 409, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/indexes", `{"name":"by_age","pattern":"user:*"}`); sc != fasthttp.StatusBadRequest {
+		t.Fatalf("incomplete index: expected 400, got %d", sc)
+	}
+	doRequest(t, client, fasthttp.MethodPost, "/indexes", `{"name":"by_age","pattern":"user:*","path":"$.age"}`)
+
+	type match struct {
+		Key   string         `json:"key"`
+		Value map[string]any `json:"value"`
+	}
+	var matches []match
+
+	sc, body := doRequest(t, client, fasthttp.MethodGet, "/query?index=by_email&eq=bob@example.com", "")
+	mustBodyJSON(t, body, &matches)
+	if sc != fasthttp.StatusOK || len(matches) != 1 || matches[0].Key != "user:2" || matches[0].Value["age"] != float64(25) {
+		t.Fatalf("eq query: %d %s", sc, body)
+	}
+
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/query?index=by_age&gt=25&lte=40", "")
+	mustBodyJSON(t, body, &matches)
+	if len(matches) != 1 || matches[0].Key != "user:1" {
+		t.Fatalf("range query: %s", body)
+	}
+
+	doRequest(t, client, fasthttp.MethodDelete, "/kv/user:1", "")
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/query?index=by_age", "")
+	mustBodyJSON(t, body, &matches)
+	if len(matches) != 1 || matches[0].Key != "user:2" {
+		t.Fatalf("after delete: %s", body)
+	}
+
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/indexes", "")
+	var defs []map[string]string
+	mustBodyJSON(t, body, &defs)
+	if len(defs) != 2 || defs[0]["name"] != "by_age" || defs[1]["path"] != "$.email" {
+		t.Fatalf("list indexes: %s", body)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodGet, "/query?index=by_age&eq=old&type=number", ""); sc != fasthttp.StatusBadRequest {
+		t.Fatalf("non-numeric number bound: %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodGet, "/query?index=nope&eq=1", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("unknown index: expected 404, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodDelete, "/indexes/by_age", ""); sc != fasthttp.StatusNoContent {
+		t.Fatalf("drop index: expected 204, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodDelete, "/indexes/by_age", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("drop missing index: expected 404, got %d", sc)
+	}
+}
@@ -0,0 +1,58 @@
+package e2e
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type leaseEntry struct {
+	Key   string  `json:"key"`
+	Value *string `json:"value"`
+	Lease string  `json:"lease"`
+}
+
+func TestLease_HTTPMissPath(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	var first leaseEntry
+	sc, body := doRequest(t, client, fasthttp.MethodGet, "/kv/hot?lease_ms=60000", "")
+	mustBodyJSON(t, body, &first)
+	if sc != fasthttp.StatusNotFound || first.Lease == "" || first.Value != nil {
+		t.Fatalf("lease: %d %s", sc, body)
+	}
+
+	var second leaseEntry
+	sc, body = doRequest(t, client, fasthttp.MethodGet, "/kv/hot?lease_ms=60000", "")
+	mustBodyJSON(t, body, &second)
+	if sc != fasthttp.StatusNotFound || second.Lease != "" {
+		t.Fatalf("second caller: %d %s", sc, body)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPut, "/kv/hot?lease=bogus", "v"); sc != fasthttp.StatusConflict {
+		t.Fatalf("fill with wrong token: expected 409, got %d", sc)
+	}
+
+	done := make(chan []byte, 1)
+	go func() {
+		_, body := doRequest(t, client, fasthttp.MethodGet, "/kv/hot?lease_ms=60000&timeout_ms=2000", "")
+		done <- body
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for storage.BlockedClients() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPut, "/kv/hot?lease="+first.Lease, "computed"); sc != fasthttp.StatusNoContent {
+		t.Fatalf("fill: expected 204, got %d", sc)
+	}
+
+	var waited leaseEntry
+	mustBodyJSON(t, <-done, &waited)
+	if waited.Value == nil || *waited.Value != "computed" {
+		t.Fatalf("waiter: %+v", waited)
+	}
+}
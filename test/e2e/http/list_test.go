@@ -0,0 +1,88 @@
+package e2e
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type poppedBody struct {
+	Key   string  `json:"key"`
+	Value *string `json:"value"`
+}
+
+func TestList_HTTPRoutes(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	sc, body := doRequest(t, client, fasthttp.MethodPost, "/list/jobs/rpush", `["a","b","c"]`)
+	var length map[string]int
+	mustBodyJSON(t, body, &length)
+	if sc != fasthttp.StatusOK || length["length"] != 3 {
+		t.Fatalf("rpush: %d %s", sc, body)
+	}
+
+	sc, body = doRequest(t, client, fasthttp.MethodGet, "/list/jobs?start=1", "")
+	var items []string
+	mustBodyJSON(t, body, &items)
+	if sc != fasthttp.StatusOK || len(items) != 2 || items[0] != "b" {
+		t.Fatalf("range: %d %s", sc, body)
+	}
+
+	sc, body = doRequest(t, client, fasthttp.MethodPost, "/list/jobs/lpop", "")
+	var popped poppedBody
+	mustBodyJSON(t, body, &popped)
+	if sc != fasthttp.StatusOK || popped.Value == nil || *popped.Value != "a" {
+		t.Fatalf("lpop: %d %s", sc, body)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/list/jobs/trim?start=0&stop=0", ""); sc != fasthttp.StatusNoContent {
+		t.Fatalf("trim: expected 204, got %d", sc)
+	}
+
+	sc, body = doRequest(t, client, fasthttp.MethodGet, "/list/jobs/len", "")
+	mustBodyJSON(t, body, &length)
+	if length["length"] != 1 {
+		t.Fatalf("len: %d %s", sc, body)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/list/jobs/rpush", `"x"`); sc != fasthttp.StatusBadRequest {
+		t.Fatalf("rpush with bad body: expected 400, got %d", sc)
+	}
+}
+
+func TestList_HTTPLongPoll(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	type result struct {
+		status int
+		body   []byte
+	}
+	done := make(chan result, 1)
+	go func() {
+		sc, body := doRequest(t, client, fasthttp.MethodPost, "/list/jobs/lpop?timeout_ms=2000", "")
+		done <- result{sc, body}
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for storage.BlockedClients() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	doRequest(t, client, fasthttp.MethodPost, "/list/jobs/rpush", `["job-1"]`)
+
+	res := <-done
+	var popped poppedBody
+	mustBodyJSON(t, res.body, &popped)
+	if res.status != fasthttp.StatusOK || popped.Value == nil || *popped.Value != "job-1" {
+		t.Fatalf("long poll: %d %s", res.status, res.body)
+	}
+
+	sc, body := doRequest(t, client, fasthttp.MethodPost, "/list/jobs/rpop?timeout_ms=20", "")
+	mustBodyJSON(t, body, &popped)
+	if sc != fasthttp.StatusNotFound || popped.Value != nil {
+		t.Fatalf("long poll timeout: %d %s", sc, body)
+	}
+}
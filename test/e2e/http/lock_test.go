@@ -0,0 +1,64 @@
+package e2e
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type lockResponse struct {
+	Owner string `json:"owner"`
+	Fence uint64 `json:"fence"`
+	TTLMs int64  `json:"ttl_ms"`
+}
+
+func TestLock_HTTPRoutes(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	var first lockResponse
+	sc, body := doRequest(t, client, fasthttp.MethodPost, "/locks/jobs?ttl_ms=60000", "")
+	mustBodyJSON(t, body, &first)
+	if sc != fasthttp.StatusOK || first.Owner == "" || first.Fence == 0 || first.TTLMs != 60000 {
+		t.Fatalf("acquire: %d %s", sc, body)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/locks/jobs?ttl_ms=60000", ""); sc != fasthttp.StatusConflict {
+		t.Fatalf("acquire held lock: expected 409, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/locks/jobs", ""); sc != fasthttp.StatusBadRequest {
+		t.Fatalf("acquire without ttl: expected 400, got %d", sc)
+	}
+
+	var renewed lockResponse
+	sc, body = doRequest(t, client, fasthttp.MethodPost, "/locks/jobs/renew?ttl_ms=30000&owner="+first.Owner, "")
+	mustBodyJSON(t, body, &renewed)
+	if sc != fasthttp.StatusOK || renewed.Fence != first.Fence {
+		t.Fatalf("renew: %d %s", sc, body)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodDelete, "/locks/jobs?owner=intruder", ""); sc != fasthttp.StatusForbidden {
+		t.Fatalf("release by non-owner: expected 403, got %d", sc)
+	}
+
+	done := make(chan []byte, 1)
+	go func() {
+		_, body := doRequest(t, client, fasthttp.MethodPost, "/locks/jobs?ttl_ms=60000&timeout_ms=2000", "")
+		done <- body
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for storage.BlockedClients() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodDelete, "/locks/jobs?owner="+first.Owner, ""); sc != fasthttp.StatusNoContent {
+		t.Fatalf("release: expected 204, got %d", sc)
+	}
+
+	var second lockResponse
+	mustBodyJSON(t, <-done, &second)
+	if second.Owner == first.Owner || second.Fence <= first.Fence {
+		t.Fatalf("blocked acquire: %+v after %+v", second, first)
+	}
+}
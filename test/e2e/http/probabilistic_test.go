@@ -0,0 +1,78 @@
+package e2e
+
+import (
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestHLL_HTTPRoutes(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	sc, body := doRequest(t, client, fasthttp.MethodPost, "/hll/mon", `["ada","bob","cyd"]`)
+	var changed map[string]bool
+	mustBodyJSON(t, body, &changed)
+	if sc != fasthttp.StatusOK || !changed["changed"] {
+		t.Fatalf("add: %d %s", sc, body)
+	}
+	doRequest(t, client, fasthttp.MethodPost, "/hll/tue", `["cyd","dan"]`)
+
+	var count map[string]uint64
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/hll/mon", "")
+	mustBodyJSON(t, body, &count)
+	if count["count"] != 3 {
+		t.Fatalf("count: %s", body)
+	}
+
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/hll/count?keys=mon,tue", "")
+	mustBodyJSON(t, body, &count)
+	if count["count"] != 4 {
+		t.Fatalf("union count: %s", body)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/hll/week/merge?keys=mon,tue", ""); sc != fasthttp.StatusNoContent {
+		t.Fatalf("merge: expected 204, got %d", sc)
+	}
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/hll/week", "")
+	mustBodyJSON(t, body, &count)
+	if count["count"] != 4 {
+		t.Fatalf("merged count: %s", body)
+	}
+}
+
+func TestBloom_HTTPRoutes(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPut, "/bloom/events?error_rate=0.001&capacity=1000", ""); sc != fasthttp.StatusCreated {
+		t.Fatalf("reserve: expected 201, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPut, "/bloom/events?error_rate=0.001&capacity=1000", ""); sc != fasthttp.StatusConflict {
+		t.Fatalf("reserve existing: expected 409, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPut, "/bloom/other?error_rate=0&capacity=10", ""); sc != fasthttp.StatusBadRequest {
+		t.Fatalf("reserve invalid: expected 400, got %d", sc)
+	}
+
+	var added map[string]bool
+	_, body := doRequest(t, client, fasthttp.MethodPost, "/bloom/events/evt-1", "")
+	mustBodyJSON(t, body, &added)
+	if !added["added"] {
+		t.Fatalf("add: %s", body)
+	}
+
+	var exists struct {
+		Exists bool `json:"exists"`
+	}
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/bloom/events/evt-1", "")
+	mustBodyJSON(t, body, &exists)
+	if !exists.Exists {
+		t.Fatalf("exists: %s", body)
+	}
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/bloom/events/evt-2", "")
+	mustBodyJSON(t, body, &exists)
+	if exists.Exists {
+		t.Fatalf("missing item: %s", body)
+	}
+}
@@ -0,0 +1,64 @@
+package e2e
+
+import (
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+type jobResponse struct {
+	ID          string `json:"id"`
+	Payload     string `json:"payload"`
+	Attempts    int    `json:"attempts"`
+	MaxAttempts int    `json:"max_attempts"`
+}
+
+func TestQueue_HTTPRoutes(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	var created map[string]string
+	sc, body := doRequest(t, client, fasthttp.MethodPost, "/queues/mail?max_attempts=1", `{"to":"ada"}`)
+	mustBodyJSON(t, body, &created)
+	if sc != fasthttp.StatusCreated || created["id"] != "1" {
+		t.Fatalf("enqueue: %d %s", sc, body)
+	}
+	doRequest(t, client, fasthttp.MethodPost, "/queues/mail?delay_ms=60000", `{"to":"bob"}`)
+
+	var stats map[string]int
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/queues/mail", "")
+	mustBodyJSON(t, body, &stats)
+	if stats["ready"] != 1 || stats["delayed"] != 1 {
+		t.Fatalf("stats: %s", body)
+	}
+
+	var job jobResponse
+	sc, body = doRequest(t, client, fasthttp.MethodPost, "/queues/mail/reserve?visibility_ms=60000", "")
+	mustBodyJSON(t, body, &job)
+	if sc != fasthttp.StatusOK || job.ID != "1" || job.Payload != `{"to":"ada"}` || job.Attempts != 1 || job.MaxAttempts != 1 {
+		t.Fatalf("reserve: %d %s", sc, body)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/queues/mail/reserve?visibility_ms=60000", ""); sc != fasthttp.StatusNoContent {
+		t.Fatalf("reserve empty: expected 204, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/queues/mail/reserve", ""); sc != fasthttp.StatusBadRequest {
+		t.Fatalf("reserve without visibility: expected 400, got %d", sc)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/queues/mail/jobs/1/touch?visibility_ms=60000", ""); sc != fasthttp.StatusNoContent {
+		t.Fatalf("touch: expected 204, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/queues/mail/jobs/1/nack", ""); sc != fasthttp.StatusNoContent {
+		t.Fatalf("nack: expected 204, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/queues/mail/jobs/1/ack", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("ack dead job: expected 404, got %d", sc)
+	}
+
+	var dead []jobResponse
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/queues/mail/dead", "")
+	mustBodyJSON(t, body, &dead)
+	if len(dead) != 1 || dead[0].ID != "1" {
+		t.Fatalf("dead: %s", body)
+	}
+}
@@ -0,0 +1,47 @@
+package e2e
+
+import (
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+type rateLimitResponse struct {
+	Allowed      bool  `json:"allowed"`
+	Remaining    int64 `json:"remaining"`
+	RetryAfterMs int64 `json:"retry_after_ms"`
+}
+
+func TestRateLimit_HTTPRoute(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	var res rateLimitResponse
+	sc, body := doRequest(t, client, fasthttp.MethodPost, "/ratelimit/api:ada?capacity=5&refill_per_sec=0.5&cost=5", "")
+	mustBodyJSON(t, body, &res)
+	if sc != fasthttp.StatusOK || !res.Allowed || res.Remaining != 0 {
+		t.Fatalf("allowed: %d %s", sc, body)
+	}
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI("http://test/ratelimit/api:ada?capacity=5&refill_per_sec=0.5")
+	req.Header.SetMethod(fasthttp.MethodPost)
+	if err := client.Do(req, resp); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	mustBodyJSON(t, resp.Body(), &res)
+	if resp.StatusCode() != fasthttp.StatusTooManyRequests || res.Allowed || res.RetryAfterMs <= 0 {
+		t.Fatalf("denied: %d %s", resp.StatusCode(), resp.Body())
+	}
+	if got := string(resp.Header.Peek("Retry-After")); got != "2" {
+		t.Fatalf("Retry-After = %q", got)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/ratelimit/api:ada?capacity=5", ""); sc != fasthttp.StatusBadRequest {
+		t.Fatalf("missing refill: expected 400, got %d", sc)
+	}
+}
@@ -31,7 +31,7 @@ func startTestServer(t *testing.T) (*fasthttp.Client, func()) {
 
 	r := router.New()
 	routing.RegisterRoutes(r)
-	srv := &fasthttp.Server{Handler: r.Handler}
+	srv := &fasthttp.Server{Handler: routing.Instrument(r.Handler)}
 
 	ln := fasthttputil.NewInmemoryListener()
 	go func() { _ = srv.Serve(ln) }()
@@ -46,3 +46,23 @@ func startTestServer(t *testing.T) (*fasthttp.Client, func()) {
 	}
 	return client, teardown
 }
+
+func doRequest(t *testing.T, client *fasthttp.Client, method string, uri string, body string) (int, []byte) {
+	t.Helper()
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.Header.SetMethod(method)
+	req.SetRequestURI("http://test" + uri)
+	if body != "" {
+		req.SetBodyString(body)
+	}
+	if err := client.Do(req, resp); err != nil {
+		t.Fatalf("%s %s failed: %v", method, uri, err)
+	}
+
+	return resp.StatusCode(), append([]byte(nil), resp.Body()...)
+}
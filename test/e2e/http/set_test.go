@@ -0,0 +1,100 @@
+package e2e
+
+import (
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestSet_HTTPRoutes(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	doRequest(t, client, fasthttp.MethodPut, "/set/a", `["go","db"]`)
+	sc, body := doRequest(t, client, fasthttp.MethodPut, "/set/b", `["db","kv"]`)
+	var added map[string]int
+	mustBodyJSON(t, body, &added)
+	if sc != fasthttp.StatusOK || added["added"] != 2 {
+		t.Fatalf("PUT set: %d %s", sc, body)
+	}
+
+	sc, body = doRequest(t, client, fasthttp.MethodGet, "/set/inter?keys=a,b", "")
+	var members []string
+	mustBodyJSON(t, body, &members)
+	if sc != fasthttp.StatusOK || len(members) != 1 || members[0] != "db" {
+		t.Fatalf("inter: %d %s", sc, body)
+	}
+
+	sc, body = doRequest(t, client, fasthttp.MethodGet, "/set/union?keys=a,b", "")
+	mustBodyJSON(t, body, &members)
+	if len(members) != 3 {
+		t.Fatalf("union: %d %s", sc, body)
+	}
+
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/set/a/go", "")
+	var member struct {
+		Exists bool `json:"exists"`
+	}
+	mustBodyJSON(t, body, &member)
+	if !member.Exists {
+		t.Fatalf("member: %s", body)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodDelete, "/set/a/go", ""); sc != fasthttp.StatusNoContent {
+		t.Fatalf("DELETE member: expected 204, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodDelete, "/set/a/go", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("DELETE missing member: expected 404, got %d", sc)
+	}
+}
+
+func TestZSet_HTTPRoutes(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	doRequest(t, client, fasthttp.MethodPut, "/zset/board", `{"ada":30,"bob":10,"cyd":20}`)
+
+	sc, body := doRequest(t, client, fasthttp.MethodPost, "/zset/board/bob/incr?by=25", "")
+	var incr map[string]float64
+	mustBodyJSON(t, body, &incr)
+	if sc != fasthttp.StatusOK || incr["score"] != 35 {
+		t.Fatalf("incr: %d %s", sc, body)
+	}
+
+	type entry struct {
+		Member string  `json:"member"`
+		Score  float64 `json:"score"`
+	}
+	var entries []entry
+
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/zset/board?start=-2", "")
+	mustBodyJSON(t, body, &entries)
+	if len(entries) != 2 || entries[0].Member != "ada" || entries[1].Score != 35 {
+		t.Fatalf("range: %s", body)
+	}
+
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/zset/board?min=(20&count=1", "")
+	mustBodyJSON(t, body, &entries)
+	if len(entries) != 1 || entries[0].Member != "ada" {
+		t.Fatalf("range by score: %s", body)
+	}
+
+	sc, body = doRequest(t, client, fasthttp.MethodGet, "/zset/board/cyd/rank", "")
+	var rank struct {
+		Rank int `json:"rank"`
+	}
+	mustBodyJSON(t, body, &rank)
+	if sc != fasthttp.StatusOK || rank.Rank != 0 {
+		t.Fatalf("rank: %d %s", sc, body)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodDelete, "/zset/board/cyd", ""); sc != fasthttp.StatusNoContent {
+		t.Fatalf("DELETE member: expected 204, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodGet, "/zset/board/cyd/rank", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("rank of removed member: expected 404, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPut, "/set/board", `["x"]`); sc != fasthttp.StatusConflict {
+		t.Fatalf("PUT set on zset: expected 409, got %d", sc)
+	}
+}
@@ -0,0 +1,65 @@
+package e2e
+
+import (
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestSoftTTL_HTTPStaleHeader(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPut, "/kv/page?soft_ttl_ms=20&ttl=60", "v1"); sc != fasthttp.StatusNoContent {
+		t.Fatalf("put: expected 204, got %d", sc)
+	}
+
+	get := func(uri string) (*fasthttp.Response, leaseEntry) {
+		req := fasthttp.AcquireRequest()
+		resp := fasthttp.AcquireResponse()
+		defer fasthttp.ReleaseRequest(req)
+
+		req.SetRequestURI("http://test" + uri)
+		req.Header.SetMethod(fasthttp.MethodGet)
+		if err := client.Do(req, resp); err != nil {
+			t.Fatalf("request: %v", err)
+		}
+
+		var e leaseEntry
+		mustBodyJSON(t, resp.Body(), &e)
+		return resp, e
+	}
+
+	resp, e := get("/kv/page")
+	if resp.StatusCode() != fasthttp.StatusOK || len(resp.Header.Peek("X-Elysian-Stale")) != 0 || e.Value == nil || *e.Value != "v1" {
+		t.Fatalf("fresh: %d %s", resp.StatusCode(), resp.Body())
+	}
+	fasthttp.ReleaseResponse(resp)
+
+	time.Sleep(40 * time.Millisecond)
+
+	resp, e = get("/kv/page?lease_ms=60000")
+	if resp.StatusCode() != fasthttp.StatusOK || string(resp.Header.Peek("X-Elysian-Stale")) != "1" ||
+		e.Lease == "" || string(resp.Header.Peek("X-Elysian-Lease")) != e.Lease || *e.Value != "v1" {
+		t.Fatalf("stale with lease: %d %s", resp.StatusCode(), resp.Body())
+	}
+	fasthttp.ReleaseResponse(resp)
+	token := e.Lease
+
+	resp, e = get("/kv/page?lease_ms=60000")
+	if string(resp.Header.Peek("X-Elysian-Stale")) != "1" || e.Lease != "" || *e.Value != "v1" {
+		t.Fatalf("second stale read: %d %s", resp.StatusCode(), resp.Body())
+	}
+	fasthttp.ReleaseResponse(resp)
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPut, "/kv/page?soft_ttl=60&lease="+token, "v2"); sc != fasthttp.StatusNoContent {
+		t.Fatalf("refresh: expected 204, got %d", sc)
+	}
+
+	resp, e = get("/kv/page")
+	if len(resp.Header.Peek("X-Elysian-Stale")) != 0 || *e.Value != "v2" {
+		t.Fatalf("after refresh: %d %s", resp.StatusCode(), resp.Body())
+	}
+	fasthttp.ReleaseResponse(resp)
+}
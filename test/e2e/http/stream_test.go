@@ -0,0 +1,102 @@
+package e2e
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type streamEntry struct {
+	ID     string            `json:"id"`
+	Fields map[string]string `json:"fields"`
+}
+
+func TestStream_HTTPRoutes(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	sc, body := doRequest(t, client, fasthttp.MethodPost, "/stream/events?id=1-0", `{"type":"created"}`)
+	var added map[string]string
+	mustBodyJSON(t, body, &added)
+	if sc != fasthttp.StatusOK || added["id"] != "1-0" {
+		t.Fatalf("add: %d %s", sc, body)
+	}
+	doRequest(t, client, fasthttp.MethodPost, "/stream/events?id=2-0", `{"type":"updated"}`)
+
+	sc, body = doRequest(t, client, fasthttp.MethodGet, "/stream/events?start=2", "")
+	var entries []streamEntry
+	mustBodyJSON(t, body, &entries)
+	if sc != fasthttp.StatusOK || len(entries) != 1 || entries[0].Fields["type"] != "updated" {
+		t.Fatalf("range: %d %s", sc, body)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/stream/events?id=1-0", `{"a":"b"}`); sc != fasthttp.StatusBadRequest {
+		t.Fatalf("add with old ID: expected 400, got %d", sc)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/stream/events/groups/workers?id=0", ""); sc != fasthttp.StatusCreated {
+		t.Fatalf("create group: expected 201, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/stream/events/groups/workers", ""); sc != fasthttp.StatusConflict {
+		t.Fatalf("duplicate group: expected 409, got %d", sc)
+	}
+
+	_, body = doRequest(t, client, fasthttp.MethodPost, "/stream/events/groups/workers/read?consumer=w1&count=5", "")
+	mustBodyJSON(t, body, &entries)
+	if len(entries) != 2 {
+		t.Fatalf("group read: %s", body)
+	}
+
+	_, body = doRequest(t, client, fasthttp.MethodGet, "/stream/events/groups/workers/pending", "")
+	var pending []struct {
+		ID       string `json:"id"`
+		Consumer string `json:"consumer"`
+	}
+	mustBodyJSON(t, body, &pending)
+	if len(pending) != 2 || pending[0].Consumer != "w1" {
+		t.Fatalf("pending: %s", body)
+	}
+
+	_, body = doRequest(t, client, fasthttp.MethodPost, "/stream/events/groups/workers/ack", `["1-0","2-0"]`)
+	var acked map[string]int
+	mustBodyJSON(t, body, &acked)
+	if acked["acked"] != 2 {
+		t.Fatalf("ack: %s", body)
+	}
+
+	_, body = doRequest(t, client, fasthttp.MethodPost, "/stream/events/trim?maxlen=0", "")
+	var trimmed map[string]int
+	mustBodyJSON(t, body, &trimmed)
+	if trimmed["removed"] != 2 {
+		t.Fatalf("trim: %s", body)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodPost, "/stream/events/groups/nope/read?consumer=w1", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("unknown group: expected 404, got %d", sc)
+	}
+}
+
+func TestStream_HTTPLongPoll(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	done := make(chan []byte, 1)
+	go func() {
+		_, body := doRequest(t, client, fasthttp.MethodGet, "/stream/events/read?timeout_ms=2000", "")
+		done <- body
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for storage.BlockedClients() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	doRequest(t, client, fasthttp.MethodPost, "/stream/events", `{"type":"created"}`)
+
+	var entries []streamEntry
+	mustBodyJSON(t, <-done, &entries)
+	if len(entries) != 1 || entries[0].Fields["type"] != "created" {
+		t.Fatalf("long poll: %v", entries)
+	}
+}
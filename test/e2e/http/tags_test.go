@@ -0,0 +1,30 @@
+package e2e
+
+import (
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestTags_HTTPRoutes(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	doRequest(t, client, fasthttp.MethodPut, "/kv/page:42?tags=product:42,catalog", "a")
+	doRequest(t, client, fasthttp.MethodPut, "/kv/price:42?tags=product:42&ttl=60", "b")
+	doRequest(t, client, fasthttp.MethodPut, "/kv/page:7?tags=catalog", "c")
+
+	var deleted map[string]int
+	sc, body := doRequest(t, client, fasthttp.MethodDelete, "/tags/product:42", "")
+	mustBodyJSON(t, body, &deleted)
+	if sc != fasthttp.StatusOK || deleted["deleted"] != 2 {
+		t.Fatalf("invalidate: %d %s", sc, body)
+	}
+
+	if sc, _ := doRequest(t, client, fasthttp.MethodGet, "/kv/page:42", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("invalidated key: expected 404, got %d", sc)
+	}
+	if sc, _ := doRequest(t, client, fasthttp.MethodGet, "/kv/page:7", ""); sc != fasthttp.StatusOK {
+		t.Fatalf("other key: expected 200, got %d", sc)
+	}
+}
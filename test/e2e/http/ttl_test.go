@@ -0,0 +1,107 @@
+package e2e
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+type ttlEntry struct {
+	Key   string `json:"key"`
+	TTLMs int64  `json:"ttl_ms"`
+}
+
+func TestTTL_MillisecondsAndLifecycle(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	do := func(method string, uri string, body string) int {
+		t.Helper()
+		req.Reset()
+		resp.Reset()
+		req.Header.SetMethod(method)
+		req.SetRequestURI("http://test" + uri)
+		if body != "" {
+			req.SetBodyString(body)
+		}
+		if err := client.Do(req, resp); err != nil {
+			t.Fatalf("%s %s failed: %v", method, uri, err)
+		}
+		return resp.StatusCode()
+	}
+
+	if sc := do(fasthttp.MethodPut, "/kv/short?ttl_ms=150", "v"); sc != fasthttp.StatusNoContent {
+		t.Fatalf("PUT ttl_ms: expected 204, got %d", sc)
+	}
+	if sc := do(fasthttp.MethodGet, "/kv/short/ttl", ""); sc != fasthttp.StatusOK {
+		t.Fatalf("GET ttl: expected 200, got %d", sc)
+	}
+	var e ttlEntry
+	mustBodyJSON(t, resp.Body(), &e)
+	if e.TTLMs <= 0 || e.TTLMs > 150 {
+		t.Fatalf("unexpected ttl_ms %d", e.TTLMs)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if sc := do(fasthttp.MethodGet, "/kv/short", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("expected 404 after ttl_ms, got %d", sc)
+	}
+	if sc := do(fasthttp.MethodHead, "/kv/short", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("HEAD on expired key: expected 404, got %d", sc)
+	}
+
+	do(fasthttp.MethodPut, "/kv/kept", "v")
+	if sc := do(fasthttp.MethodHead, "/kv/kept", ""); sc != fasthttp.StatusOK {
+		t.Fatalf("HEAD on live key: expected 200, got %d", sc)
+	}
+	if sc := do(fasthttp.MethodGet, "/kv/kept/ttl", ""); sc != fasthttp.StatusOK {
+		t.Fatalf("GET ttl: expected 200, got %d", sc)
+	}
+	mustBodyJSON(t, resp.Body(), &e)
+	if e.TTLMs != -1 {
+		t.Fatalf("expected -1 for key without ttl, got %d", e.TTLMs)
+	}
+
+	if sc := do(fasthttp.MethodPut, "/kv/kept/ttl?ttl=60", ""); sc != fasthttp.StatusNoContent {
+		t.Fatalf("PUT ttl: expected 204, got %d", sc)
+	}
+	do(fasthttp.MethodGet, "/kv/kept/ttl", "")
+	mustBodyJSON(t, resp.Body(), &e)
+	if e.TTLMs <= 59000 || e.TTLMs > 60000 {
+		t.Fatalf("unexpected ttl_ms after expire: %d", e.TTLMs)
+	}
+
+	if sc := do(fasthttp.MethodDelete, "/kv/kept/ttl", ""); sc != fasthttp.StatusNoContent {
+		t.Fatalf("DELETE ttl: expected 204, got %d", sc)
+	}
+	do(fasthttp.MethodGet, "/kv/kept/ttl", "")
+	mustBodyJSON(t, resp.Body(), &e)
+	if e.TTLMs != -1 {
+		t.Fatalf("expected -1 after persist, got %d", e.TTLMs)
+	}
+
+	at := time.Now().Add(-time.Second).UnixMilli()
+	if sc := do(fasthttp.MethodPut, "/kv/kept/ttl?at_ms="+strconv.FormatInt(at, 10), ""); sc != fasthttp.StatusNoContent {
+		t.Fatalf("PUT ttl at_ms: expected 204, got %d", sc)
+	}
+	if sc := do(fasthttp.MethodGet, "/kv/kept", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("expected past expiry to delete key, got %d", sc)
+	}
+
+	if sc := do(fasthttp.MethodGet, "/kv/missing/ttl", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("GET ttl on missing key: expected 404, got %d", sc)
+	}
+	if sc := do(fasthttp.MethodPut, "/kv/missing/ttl?ttl=5", ""); sc != fasthttp.StatusNotFound {
+		t.Fatalf("PUT ttl on missing key: expected 404, got %d", sc)
+	}
+	if sc := do(fasthttp.MethodPut, "/kv/missing/ttl", ""); sc != fasthttp.StatusBadRequest {
+		t.Fatalf("PUT ttl without args: expected 400, got %d", sc)
+	}
+}
@@ -0,0 +1,123 @@
+package shutdown
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/boot"
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func dialUnix(t *testing.T, path string) net.Conn {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		c, err := net.DialTimeout("unix", path, 200*time.Millisecond)
+		if err == nil {
+			return c
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("dial %s: %v", path, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestShutdown_DrainsTCPAndFlushes(t *testing.T) {
+	tmp := t.TempDir()
+	sock := filepath.Join(tmp, "tcp.sock")
+
+	globals.SetConfig(&configuration.Config{
+		Store: configuration.StoreConfig{
+			Folder:               tmp,
+			Shards:               8,
+			FlushIntervalSeconds: 3600,
+		},
+		Server: configuration.ServersConfig{
+			TCP: configuration.ServerConfig{Enabled: true, UnixSocket: sock},
+		},
+	})
+	storage.LoadDB()
+	boot.BootSaver()
+	boot.BootExpirationHandler()
+
+	go boot.InitTCP()
+
+	c := dialUnix(t, sock)
+	defer c.Close()
+	r := bufio.NewReader(c)
+	_ = c.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if _, err := c.Write([]byte("SET drained value\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if l, err := r.ReadString('\n'); err != nil || strings.TrimSpace(l) != "OK" {
+		t.Fatalf("SET reply: %q, %v", l, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := boot.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if _, err := r.ReadString('\n'); err != io.EOF {
+		t.Fatalf("expected idle connection to be closed by shutdown, got %v", err)
+	}
+
+	if _, err := net.DialTimeout("unix", sock, 200*time.Millisecond); err == nil {
+		t.Fatal("listener should no longer accept connections")
+	}
+
+	data, err := storage.ReadFromDB(storage.DataFile)
+	if err != nil {
+		t.Fatalf("ReadFromDB: %v", err)
+	}
+	if string(data["drained"]) != "value" {
+		t.Fatalf("final flush did not persist the write, got %v", data)
+	}
+
+	_ = storage.PutKeyValue("unsaved", []byte("x"))
+	if err := os.RemoveAll(tmp); err != nil {
+		t.Fatalf("remove data folder: %v", err)
+	}
+
+	if err := boot.Shutdown(ctx); err == nil {
+		t.Fatal("expected Shutdown to report the failed final flush")
+	}
+}
+
+func TestShutdown_FlushesPastTheDeadline(t *testing.T) {
+	tmp := t.TempDir()
+
+	globals.SetConfig(&configuration.Config{
+		Store: configuration.StoreConfig{Folder: tmp, Shards: 8, FlushIntervalSeconds: 3600},
+	})
+	storage.LoadDB()
+	boot.BootSaver()
+
+	_ = storage.PutKeyValue("late", []byte("value"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := boot.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	data, err := storage.ReadFromDB(storage.DataFile)
+	if err != nil || string(data["late"]) != "value" {
+		t.Fatalf("final flush after the deadline: %v, %v", data, err)
+	}
+}
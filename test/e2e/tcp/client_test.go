@@ -0,0 +1,98 @@
+package tcp
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/boot"
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func newClient(t *testing.T) *client {
+	t.Helper()
+
+	globals.SetConfig(&configuration.Config{
+		Store: configuration.StoreConfig{
+			Folder: t.TempDir(),
+			Shards: 8,
+		},
+		Server: configuration.ServersConfig{
+			TCP: configuration.ServerConfig{Enabled: true, Host: "127.0.0.1", Port: 8088},
+		},
+	})
+	storage.LoadDB()
+
+	if c, err := net.DialTimeout("tcp", tcpAddr, 150*time.Millisecond); err == nil {
+		_ = c.Close()
+	} else {
+		go boot.InitTCP()
+		if err := waitTCPUp(tcpAddr, 2*time.Second); err != nil {
+			t.Skipf("skipping TCP test: %v", err)
+		}
+	}
+
+	return dialClient(t)
+}
+
+func dialClient(t *testing.T) *client {
+	t.Helper()
+
+	c, err := net.DialTimeout("tcp", tcpAddr, 2*time.Second)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	return &client{t: t, conn: c, r: bufio.NewReader(c)}
+}
+
+func (c *client) write(s string) {
+	c.t.Helper()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(2 * time.Second))
+	if _, err := c.conn.Write([]byte(s + "\n")); err != nil {
+		c.t.Fatalf("write %q: %v", s, err)
+	}
+}
+
+func (c *client) readLine() string {
+	c.t.Helper()
+	_ = c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	l, err := c.r.ReadString('\n')
+	if err != nil {
+		c.t.Fatalf("read: %v", err)
+	}
+	return l[:len(l)-1]
+}
+
+func (c *client) send(s string) string {
+	c.t.Helper()
+	c.write(s)
+	return c.readLine()
+}
+
+func (c *client) sendN(s string, n int) []string {
+	c.t.Helper()
+	c.write(s)
+	out := make([]string, n)
+	for i := range out {
+		out[i] = c.readLine()
+	}
+	return out
+}
+
+func (c *client) expect(cmd string, want string) {
+	c.t.Helper()
+	if got := c.send(cmd); got != want {
+		c.t.Fatalf("%s: want %q, got %q", cmd, want, got)
+	}
+}
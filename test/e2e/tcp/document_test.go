@@ -0,0 +1,26 @@
+package tcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTCP_JSONCommands(t *testing.T) {
+	c := newClient(t)
+
+	c.expect(`JSON.SET user:1 $ {"name": "Ada", "address": {"city": "London"}}`, "OK")
+	c.expect(`JSON.SET user:1 $.address.city "Paris"`, "OK")
+	c.expect("JSON.GET user:1 $.address.city", `"Paris"`)
+	c.expect("JSON.GET user:1", `{"address":{"city":"Paris"},"name":"Ada"}`)
+	c.expect("JSON.GET missing", "missing=not found")
+
+	if got := c.send("JSON.GET user:1 $.nope"); !strings.HasPrefix(got, "ERR path not found") {
+		t.Fatalf("JSON.GET missing path: got %q", got)
+	}
+	if got := c.send("JSON.SET user:1 $.a {oops"); !strings.HasPrefix(got, "ERR value is not valid JSON") {
+		t.Fatalf("JSON.SET invalid: got %q", got)
+	}
+	if got := c.send("JSON.SET user:1"); !strings.HasPrefix(got, "ERR usage") {
+		t.Fatalf("JSON.SET usage: got %q", got)
+	}
+}
@@ -0,0 +1,32 @@
+package tcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTCP_GeoCommands(t *testing.T) {
+	c := newClient(t)
+
+	c.expect("GEOADD sicily 13.361389 38.115556 Palermo 15.087269 37.502669 Catania", "2")
+	c.expect("GEODIST sicily Palermo Catania km", "166.2742")
+	c.expect("GEODIST sicily Palermo Nowhere", "ERR member not found")
+
+	if got := c.sendN("GEOPOS sicily Palermo Nowhere", 2); !strings.HasPrefix(got[0], "Palermo 13.36138") || got[1] != "Nowhere=not found" {
+		t.Fatalf("GEOPOS: got %v", got)
+	}
+
+	if got := c.sendN("GEOSEARCH sicily FROMLONLAT 15 37 BYRADIUS 200 km ASC WITHDIST", 2); got[0] != "Catania 56.4413" || !strings.HasPrefix(got[1], "Palermo 190.4") {
+		t.Fatalf("GEOSEARCH radius: got %v", got)
+	}
+	c.expect("GEOSEARCH sicily FROMMEMBER Palermo BYRADIUS 100 km", "Palermo")
+	c.expect("GEOSEARCH sicily FROMLONLAT 15 37 BYRADIUS 200 km DESC COUNT 1", "Palermo")
+	c.expect("GEOSEARCH sicily FROMLONLAT 15 37 BYBOX 400 400 km COUNT 1", "Catania")
+
+	if got := c.send("GEOSEARCH sicily BYRADIUS 10 km"); !strings.HasPrefix(got, "ERR usage") {
+		t.Fatalf("GEOSEARCH without origin: got %q", got)
+	}
+	if got := c.send("GEOADD sicily 200 10 Nowhere"); !strings.HasPrefix(got, "ERR invalid longitude") {
+		t.Fatalf("GEOADD invalid: got %q", got)
+	}
+}
@@ -0,0 +1,43 @@
+package tcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTCP_HashCommands(t *testing.T) {
+	c := newClient(t)
+
+	c.expect(`HSET user:1 name "Ada Lovelace" visits 1`, "2")
+	c.expect("HSET user:1 visits 2", "0")
+	c.expect("HGET user:1 name", "Ada Lovelace")
+	c.expect("HGET user:1 missing", "missing=not found")
+	c.expect("HINCRBY user:1 visits 40", "42")
+	c.expect("HINCRBY user:1 name 1", "ERR value is not an integer")
+
+	got := c.sendN("HMGET user:1 visits nope name", 3)
+	want := []string{"42", "nope=not found", "Ada Lovelace"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("HMGET[%d]: want %q, got %q", i, want[i], got[i])
+		}
+	}
+
+	all := c.sendN("HGETALL user:1", 2)
+	if all[0] != "name=Ada Lovelace" || all[1] != "visits=42" {
+		t.Fatalf("HGETALL: got %v", all)
+	}
+
+	c.expect("SET plain v", "OK")
+	if got := c.send("HSET plain f v"); !strings.HasPrefix(got, "ERR WRONGTYPE") {
+		t.Fatalf("HSET on string: got %q", got)
+	}
+	if got := c.send("GET user:1"); !strings.HasPrefix(got, "ERR WRONGTYPE") {
+		t.Fatalf("GET on hash: got %q", got)
+	}
+
+	c.expect("HDEL user:1 name visits", "2")
+	c.expect("EXISTS user:1", "0")
+	c.expect("HGETALL user:1", "user:1=not found")
+	c.expect("HSET user:1 name", "ERR usage: HSET <key> <field> <value> [field value ...]")
+}
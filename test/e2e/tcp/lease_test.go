@@ -0,0 +1,33 @@
+package tcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTCP_GetLeaseCommands(t *testing.T) {
+	c := newClient(t)
+	waiter := dialClient(t)
+
+	got := c.send("GETLEASE hot 60000")
+	if !strings.HasPrefix(got, "LEASE ") {
+		t.Fatalf("GETLEASE miss: got %q", got)
+	}
+	token := strings.TrimPrefix(got, "LEASE ")
+
+	c.expect("GETLEASE hot 60000", "hot=not found")
+	c.expect("SET LEASE=bogus hot v", "ERR lease token is not valid for this key")
+
+	waiter.write("GETLEASE hot 60000 WAIT 2000")
+	waitBlocked(t, 1)
+
+	c.expect("SET TTL=60 LEASE="+token+" hot computed", "OK")
+	if got := waiter.readLine(); got != "hot=computed" {
+		t.Fatalf("blocked GETLEASE: got %q", got)
+	}
+
+	c.expect("GETLEASE hot 60000", "hot=computed")
+	if got := c.send("GETLEASE hot"); !strings.HasPrefix(got, "ERR usage") {
+		t.Fatalf("GETLEASE usage: got %q", got)
+	}
+}
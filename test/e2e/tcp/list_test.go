@@ -0,0 +1,67 @@
+package tcp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestTCP_ListCommands(t *testing.T) {
+	c := newClient(t)
+
+	c.expect(`RPUSH jobs b "c d"`, "2")
+	c.expect("LPUSH jobs a", "3")
+	c.expect("LLEN jobs", "3")
+
+	got := c.sendN("LRANGE jobs 0 -1", 3)
+	if got[0] != "a" || got[1] != "b" || got[2] != "c d" {
+		t.Fatalf("LRANGE: got %v", got)
+	}
+
+	c.expect("LTRIM jobs 1 -1", "OK")
+	c.expect("LPOP jobs", "b")
+	c.expect("RPOP jobs", "c d")
+	c.expect("LPOP jobs", "jobs=not found")
+	c.expect("LLEN jobs", "0")
+
+	c.expect("SET plain v", "OK")
+	if got := c.send("RPUSH plain x"); !strings.HasPrefix(got, "ERR WRONGTYPE") {
+		t.Fatalf("RPUSH on string: got %q", got)
+	}
+	c.expect("BLPOP jobs nope", "ERR usage: BLPOP <key> [key ...] <timeout>")
+}
+
+func TestTCP_BlockingPop(t *testing.T) {
+	c := newClient(t)
+	first := dialClient(t)
+	second := dialClient(t)
+
+	first.write("BLPOP queue 2")
+	waitBlocked(t, 1)
+	second.write("BRPOP other queue 2")
+	waitBlocked(t, 2)
+
+	c.expect("RPUSH queue j1 j2", "2")
+
+	if got := first.readLine(); got != "queue=j1" {
+		t.Fatalf("first waiter: got %q", got)
+	}
+	if got := second.readLine(); got != "queue=j2" {
+		t.Fatalf("second waiter: got %q", got)
+	}
+
+	c.expect("BLPOP queue 0.05", "TIMEOUT")
+}
+
+func waitBlocked(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for storage.BlockedClients() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d blocked clients, got %d", n, storage.BlockedClients())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,47 @@
+package tcp
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTCP_LockCommands(t *testing.T) {
+	c := newClient(t)
+	waiter := dialClient(t)
+
+	first := strings.Fields(c.send("LOCK jobs 60000"))
+	if len(first) != 2 {
+		t.Fatalf("LOCK: got %v", first)
+	}
+	owner := first[0]
+
+	c.expect("LOCK jobs 60000", "LOCKED")
+	c.expect("RENEW jobs "+owner+" 60000", first[1])
+	c.expect("UNLOCK jobs intruder", "ERR lock is not held by this owner")
+
+	waiter.write("LOCK jobs 60000 WAIT 2000")
+	waitBlocked(t, 1)
+
+	c.expect("UNLOCK jobs "+owner, "OK")
+
+	second := strings.Fields(waiter.readLine())
+	if len(second) != 2 || second[0] == owner || fence(t, second[1]) <= fence(t, first[1]) {
+		t.Fatalf("blocked LOCK: got %v after %v", second, first)
+	}
+
+	c.expect("LOCK jobs 60000 WAIT 20", "LOCKED")
+
+	if got := c.send("LOCK jobs 0"); !strings.HasPrefix(got, "ERR usage") {
+		t.Fatalf("LOCK usage: got %q", got)
+	}
+}
+
+func fence(t *testing.T, s string) uint64 {
+	t.Helper()
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		t.Fatalf("fence %q: %v", s, err)
+	}
+	return n
+}
@@ -0,0 +1,38 @@
+package tcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTCP_HyperLogLogCommands(t *testing.T) {
+	c := newClient(t)
+
+	c.expect("PFADD visits:mon ada bob cyd", "1")
+	c.expect("PFADD visits:mon ada", "0")
+	c.expect("PFADD visits:tue cyd dan", "1")
+	c.expect("PFCOUNT visits:mon", "3")
+	c.expect("PFCOUNT visits:mon visits:tue", "4")
+	c.expect("PFMERGE visits:week visits:mon visits:tue", "OK")
+	c.expect("PFCOUNT visits:week", "4")
+
+	if got := c.send("PFADD"); !strings.HasPrefix(got, "ERR usage") {
+		t.Fatalf("PFADD usage: got %q", got)
+	}
+}
+
+func TestTCP_BloomCommands(t *testing.T) {
+	c := newClient(t)
+
+	c.expect("BF.RESERVE events 0.001 1000", "OK")
+	c.expect("BF.RESERVE events 0.001 1000", "ERR key already exists")
+	c.expect("BF.ADD events evt-1", "1")
+	c.expect("BF.ADD events evt-1", "0")
+	c.expect("BF.EXISTS events evt-1", "1")
+	c.expect("BF.EXISTS events evt-2", "0")
+	c.expect("BF.EXISTS missing evt-1", "0")
+
+	if got := c.send("BF.RESERVE other 1.5 10"); !strings.HasPrefix(got, "ERR error rate") {
+		t.Fatalf("BF.RESERVE invalid rate: got %q", got)
+	}
+}
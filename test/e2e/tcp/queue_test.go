@@ -0,0 +1,31 @@
+package tcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTCP_QueueCommands(t *testing.T) {
+	c := newClient(t)
+
+	c.expect(`ENQUEUE mail "hello world"`, "1")
+	c.expect("ENQUEUE mail later DELAY 60000 ATTEMPTS 3", "2")
+	c.expect("QINFO mail", "ready 1 delayed 1 reserved 0 dead 0")
+
+	c.expect("RESERVE mail 60000", `1 1 "hello world"`)
+	c.expect("RESERVE mail 60000", "mail=not found")
+	c.expect("TOUCH mail 1 60000", "OK")
+	c.expect("NACK mail 1", "OK")
+	c.expect("RESERVE mail 60000", `1 2 "hello world"`)
+	c.expect("ACK mail 1", "OK")
+	c.expect("ACK mail 1", "ERR no such reserved job")
+
+	c.expect("ENQUEUE jobs once ATTEMPTS 1", "1")
+	c.expect("RESERVE jobs 60000", "1 1 once")
+	c.expect("NACK jobs 1", "OK")
+	c.expect("QDEAD jobs", "1 1 once")
+
+	if got := c.send("ENQUEUE mail x DELAY"); !strings.HasPrefix(got, "ERR usage") {
+		t.Fatalf("ENQUEUE usage: got %q", got)
+	}
+}
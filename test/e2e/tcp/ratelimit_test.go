@@ -0,0 +1,23 @@
+package tcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTCP_RateLimitCommand(t *testing.T) {
+	c := newClient(t)
+
+	c.expect("RATELIMIT api:ada 2 0.01", "ALLOWED 1 0")
+	c.expect("RATELIMIT api:ada 2 0.01", "ALLOWED 0 0")
+
+	if got := c.send("RATELIMIT api:ada 2 0.01"); !strings.HasPrefix(got, "DENIED 0 ") {
+		t.Fatalf("empty bucket: got %q", got)
+	}
+	if got := c.send("RATELIMIT api:ada 2 0.01 3"); !strings.HasPrefix(got, "ERR capacity") {
+		t.Fatalf("cost above capacity: got %q", got)
+	}
+	if got := c.send("RATELIMIT api:ada"); !strings.HasPrefix(got, "ERR usage") {
+		t.Fatalf("RATELIMIT usage: got %q", got)
+	}
+}
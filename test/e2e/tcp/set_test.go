@@ -0,0 +1,53 @@
+package tcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTCP_SetCommands(t *testing.T) {
+	c := newClient(t)
+
+	c.expect("SADD tags:a go db go", "2")
+	c.expect("SADD tags:b db kv", "2")
+	c.expect("SISMEMBER tags:a go", "1")
+	c.expect("SISMEMBER tags:a kv", "0")
+
+	if got := c.sendN("SMEMBERS tags:a", 2); got[0] != "db" || got[1] != "go" {
+		t.Fatalf("SMEMBERS: got %v", got)
+	}
+	c.expect("SINTER tags:a tags:b", "db")
+	if got := c.sendN("SUNION tags:a tags:b", 3); got[0] != "db" || got[2] != "kv" {
+		t.Fatalf("SUNION: got %v", got)
+	}
+
+	c.expect("SREM tags:a go db", "2")
+	c.expect("SMEMBERS tags:a", "tags:a=not found")
+}
+
+func TestTCP_SortedSetCommands(t *testing.T) {
+	c := newClient(t)
+
+	c.expect("ZADD board 30 ada 10 bob 20 cyd", "3")
+	c.expect("ZINCRBY board 25 bob", "35")
+	c.expect("ZRANK board ada", "1")
+	c.expect("ZRANK board nope", "nope=not found")
+
+	if got := c.sendN("ZRANGE board 0 -1 WITHSCORES", 3); got[0] != "cyd=20" || got[2] != "bob=35" {
+		t.Fatalf("ZRANGE: got %v", got)
+	}
+	c.expect("ZRANGE board -1 -1", "bob")
+
+	if got := c.sendN("ZRANGEBYSCORE board (20 +inf LIMIT 0 2", 2); got[0] != "ada" || got[1] != "bob" {
+		t.Fatalf("ZRANGEBYSCORE: got %v", got)
+	}
+
+	c.expect("ZADD board abc x", "ERR value is not a valid finite float")
+	c.expect("ZREM board ada bob cyd", "3")
+	c.expect("EXISTS board", "0")
+
+	c.expect("SET plain v", "OK")
+	if got := c.send("ZADD plain 1 a"); !strings.HasPrefix(got, "ERR WRONGTYPE") {
+		t.Fatalf("ZADD on string: got %q", got)
+	}
+}
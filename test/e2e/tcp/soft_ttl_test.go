@@ -0,0 +1,29 @@
+package tcp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTCP_SoftTTL(t *testing.T) {
+	c := newClient(t)
+
+	c.expect("SET SOFTPX=20 TTL=60 page v1", "OK")
+	c.expect("GET page", "page=v1")
+
+	time.Sleep(40 * time.Millisecond)
+	c.expect("GET page", "STALE page=v1")
+
+	got := c.send("GETLEASE page 60000")
+	if !strings.HasPrefix(got, "STALE LEASE ") || !strings.HasSuffix(got, " page=v1") {
+		t.Fatalf("GETLEASE stale: got %q", got)
+	}
+	token := strings.Fields(got)[2]
+
+	c.expect("GETLEASE page 60000", "STALE page=v1")
+
+	c.expect("SET SOFT=60 LEASE="+token+" page v2", "OK")
+	c.expect("GET page", "page=v2")
+	c.expect("GETLEASE page 60000", "page=v2")
+}
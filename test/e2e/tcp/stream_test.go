@@ -0,0 +1,56 @@
+package tcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTCP_StreamCommands(t *testing.T) {
+	c := newClient(t)
+
+	c.expect(`XADD events 1-1 type created note "hello world"`, "1-1")
+	c.expect("XADD events 1-* type updated", "1-2")
+	c.expect("XADD events 1-1 type late", "ERR stream ID must be greater than the last entry ID")
+	c.expect("XLEN events", "2")
+
+	got := c.sendN("XRANGE events - +", 2)
+	if got[0] != `1-1 type created note "hello world"` || got[1] != "1-2 type updated" {
+		t.Fatalf("XRANGE: got %v", got)
+	}
+	c.expect("XRANGE events (1-1 + COUNT 1", "1-2 type updated")
+	c.expect("XREAD STREAMS events 1-1", "events 1-2 type updated")
+
+	if got := c.send("XADD events MAXLEN 1 * type deleted"); !strings.Contains(got, "-") {
+		t.Fatalf("XADD with MAXLEN: got %q", got)
+	}
+	c.expect("XLEN events", "1")
+}
+
+func TestTCP_StreamConsumerGroups(t *testing.T) {
+	c := newClient(t)
+	worker := dialClient(t)
+
+	c.expect("XGROUP CREATE jobs workers $ MKSTREAM", "OK")
+	if got := c.send("XGROUP CREATE jobs workers $"); !strings.HasPrefix(got, "ERR BUSYGROUP") {
+		t.Fatalf("duplicate group: got %q", got)
+	}
+
+	worker.write("XREADGROUP GROUP workers w1 BLOCK 2000 STREAMS jobs >")
+	waitBlocked(t, 1)
+
+	c.expect("XADD jobs 5-0 job a", "5-0")
+	if got := worker.readLine(); got != "jobs 5-0 job a" {
+		t.Fatalf("blocked XREADGROUP: got %q", got)
+	}
+
+	if got := c.send("XPENDING jobs workers w1"); !strings.HasPrefix(got, "5-0 w1 ") || !strings.HasSuffix(got, " 1") {
+		t.Fatalf("XPENDING: got %q", got)
+	}
+	c.expect("XREADGROUP GROUP workers w1 STREAMS jobs 0", "jobs 5-0 job a")
+	c.expect("XCLAIM jobs workers w2 0 5-0", "5-0 job a")
+	c.expect("XACK jobs workers 5-0", "1")
+	c.expect("XPENDING jobs workers", "")
+	c.expect("XREADGROUP GROUP workers w1 BLOCK 20 STREAMS jobs >", "TIMEOUT")
+	c.expect("XREADGROUP GROUP nope w1 STREAMS jobs >", "ERR NOGROUP no such consumer group")
+	c.expect("XGROUP DESTROY jobs workers", "1")
+}
@@ -0,0 +1,17 @@
+package tcp
+
+import "testing"
+
+func TestTCP_TagInvalidation(t *testing.T) {
+	c := newClient(t)
+
+	c.expect("SET TAGS=product:42,catalog page:42 a", "OK")
+	c.expect("SET PX=60000 TAGS=product:42 price:42 b", "OK")
+	c.expect("SET TAGS=catalog TTL=60 page:7 c", "OK")
+
+	c.expect("INVALIDATE product:42", "2")
+	c.expect("GET price:42", "price:42=not found")
+	c.expect("GET page:7", "page:7=c")
+	c.expect("INVALIDATE catalog", "1")
+	c.expect("INVALIDATE catalog", "0")
+}
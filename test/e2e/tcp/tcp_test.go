@@ -37,6 +37,9 @@ func TestTCP_PING_SET_MGET_GET__WILDCARD__SAVE__RESET(t *testing.T) {
 			Folder: tmp,
 			Shards: 8,
 		},
+		Server: configuration.ServersConfig{
+			TCP: configuration.ServerConfig{Enabled: true, Host: "127.0.0.1", Port: 8088},
+		},
 	})
 	storage.LoadDB()
 	boot.BootSaver()
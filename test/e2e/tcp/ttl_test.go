@@ -0,0 +1,100 @@
+package tcp
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/boot"
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestTCP_ExpiryCommands(t *testing.T) {
+	globals.SetConfig(&configuration.Config{
+		Store: configuration.StoreConfig{
+			Folder: t.TempDir(),
+			Shards: 8,
+		},
+		Server: configuration.ServersConfig{
+			TCP: configuration.ServerConfig{Enabled: true, Host: "127.0.0.1", Port: 8088},
+		},
+	})
+	storage.LoadDB()
+
+	if c, err := net.DialTimeout("tcp", tcpAddr, 150*time.Millisecond); err == nil {
+		_ = c.Close()
+	} else {
+		go boot.InitTCP()
+		if err := waitTCPUp(tcpAddr, 2*time.Second); err != nil {
+			t.Skipf("skipping TCP test: %v", err)
+		}
+	}
+
+	c, err := net.DialTimeout("tcp", tcpAddr, 2*time.Second)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer c.Close()
+
+	r := bufio.NewReader(c)
+	send := func(s string) string {
+		t.Helper()
+		_ = c.SetDeadline(time.Now().Add(2 * time.Second))
+		if _, err := c.Write([]byte(s + "\n")); err != nil {
+			t.Fatalf("write %q: %v", s, err)
+		}
+		l, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		return l[:len(l)-1]
+	}
+	expect := func(cmd string, want string) {
+		t.Helper()
+		if got := send(cmd); got != want {
+			t.Fatalf("%s: want %q, got %q", cmd, want, got)
+		}
+	}
+
+	expect("SET PX=150 short v", "OK")
+	if ms, err := strconv.Atoi(send("PTTL short")); err != nil || ms <= 0 || ms > 150 {
+		t.Fatalf("PTTL short: unexpected %d (%v)", ms, err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	expect("GET short", "short=not found")
+	expect("PTTL short", "-2")
+
+	expect("SET kept v", "OK")
+	expect("TTL kept", "-1")
+	expect("EXPIRE kept 60", "1")
+	expect("TTL kept", "60")
+	expect("PEXPIRE kept 1500", "1")
+	if ms, err := strconv.Atoi(send("PTTL kept")); err != nil || ms <= 1000 || ms > 1500 {
+		t.Fatalf("PTTL after PEXPIRE: unexpected %d (%v)", ms, err)
+	}
+	expect("PERSIST kept", "1")
+	expect("PERSIST kept", "0")
+	expect("TTL kept", "-1")
+
+	at := time.Now().Add(time.Hour).Unix()
+	expect("EXPIREAT kept "+strconv.FormatInt(at, 10), "1")
+	if s, err := strconv.Atoi(send("TTL kept")); err != nil || s < 3590 || s > 3600 {
+		t.Fatalf("TTL after EXPIREAT: unexpected %d (%v)", s, err)
+	}
+
+	past := time.Now().Add(-time.Second).UnixMilli()
+	expect("PEXPIREAT kept "+strconv.FormatInt(past, 10), "1")
+	expect("GET kept", "kept=not found")
+
+	expect("SET a 1", "OK")
+	expect("SET PX=20 b 2", "OK")
+	time.Sleep(40 * time.Millisecond)
+	expect("EXISTS a b missing", "1")
+
+	expect("EXPIRE missing 10", "0")
+	expect("EXPIRE kept", "ERR usage: <key> <amount>")
+}
@@ -0,0 +1,72 @@
+package tcp
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/boot"
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestTCP_UnixSocketListener(t *testing.T) {
+	tmp := t.TempDir()
+	sock := filepath.Join(tmp, "elysian.sock")
+
+	globals.SetConfig(&configuration.Config{
+		Store: configuration.StoreConfig{
+			Folder: tmp,
+			Shards: 8,
+		},
+		Server: configuration.ServersConfig{
+			TCP: configuration.ServerConfig{Enabled: true, UnixSocket: sock, UnixSocketMode: "0600"},
+		},
+	})
+	storage.LoadDB()
+
+	go boot.InitTCP()
+
+	var c net.Conn
+	var err error
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		c, err = net.DialTimeout("unix", sock, 200*time.Millisecond)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("dial unix socket: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	defer c.Close()
+
+	info, err := os.Stat(sock)
+	if err != nil {
+		t.Fatalf("stat socket: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("socket permissions = %o, want 600", perm)
+	}
+
+	r := bufio.NewReader(c)
+	_ = c.SetDeadline(time.Now().Add(2 * time.Second))
+
+	if _, err := c.Write([]byte("SET sock:1 hello\nGET sock:1\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for _, want := range []string{"OK", "sock:1=hello"} {
+		l, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if got := l[:len(l)-1]; got != want {
+			t.Fatalf("want %q, got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package bloom_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/taymour/elysiandb/internal/bloom"
+)
+
+func TestFilter_NoFalseNegativesAndBoundedFalsePositives(t *testing.T) {
+	f, err := bloom.New(0.01, 1000)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for i := 0; i < 5000; i++ {
+		f.Add([]byte("event:" + strconv.Itoa(i)))
+	}
+	if f.Layers() < 2 {
+		t.Fatalf("filter should have scaled past its initial capacity, layers = %d", f.Layers())
+	}
+
+	for i := 0; i < 5000; i++ {
+		if !f.Exists([]byte("event:" + strconv.Itoa(i))) {
+			t.Fatalf("false negative for event:%d", i)
+		}
+	}
+
+	falsePositives := 0
+	for i := 0; i < 20000; i++ {
+		if f.Exists([]byte("other:" + strconv.Itoa(i))) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / 20000; rate > 0.02 {
+		t.Fatalf("false positive rate %.4f exceeds the configured bound", rate)
+	}
+}
+
+func TestFilter_AddReportsNewItems(t *testing.T) {
+	f, _ := bloom.New(0.001, 10)
+	if !f.Add([]byte("a")) || f.Add([]byte("a")) {
+		t.Fatalf("Add should report true only for a new item")
+	}
+	if f.Count() != 1 {
+		t.Fatalf("Count = %d", f.Count())
+	}
+}
+
+func TestFilter_EncodingRoundTrip(t *testing.T) {
+	f, _ := bloom.New(0.01, 50)
+	for i := 0; i < 200; i++ {
+		f.Add([]byte(strconv.Itoa(i)))
+	}
+
+	data, _ := f.MarshalBinary()
+	var decoded bloom.Filter
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if decoded.Count() != f.Count() || decoded.Layers() != f.Layers() || !decoded.Exists([]byte("199")) {
+		t.Fatalf("decoded filter differs from the original")
+	}
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, bloom.ErrCorrupt) {
+		t.Fatalf("expected ErrCorrupt, got %v", err)
+	}
+}
+
+func TestNew_RejectsInvalidParams(t *testing.T) {
+	for _, tc := range []struct {
+		rate     float64
+		capacity uint64
+	}{{0, 10}, {1, 10}, {0.01, 0}} {
+		if _, err := bloom.New(tc.rate, tc.capacity); !errors.Is(err, bloom.ErrInvalidParams) {
+			t.Fatalf("New(%v, %d): expected ErrInvalidParams, got %v", tc.rate, tc.capacity, err)
+		}
+	}
+}
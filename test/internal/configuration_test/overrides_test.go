@@ -0,0 +1,132 @@
+package configuration_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	cfgpkg "github.com/taymour/elysiandb/internal/configuration"
+)
+
+func writeYAML(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "elysian.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write yaml: %v", err)
+	}
+	return path
+}
+
+func envMap(m map[string]string) func(string) (string, bool) {
+	return func(k string) (string, bool) {
+		v, ok := m[k]
+		return v, ok
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	cases := map[string]string{
+		"store.flushIntervalSeconds":  "ELYSIAN_STORE_FLUSH_INTERVAL_SECONDS",
+		"server.http.port":            "ELYSIAN_SERVER_HTTP_PORT",
+		"log.maxSizeMB":               "ELYSIAN_LOG_MAX_SIZE_MB",
+		"encryption.previousKeyFiles": "ELYSIAN_ENCRYPTION_PREVIOUS_KEY_FILES",
+	}
+	for key, want := range cases {
+		if got := cfgpkg.EnvName(key); got != want {
+			t.Errorf("EnvName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestReadConfig_FillsDefaults(t *testing.T) {
+	path := writeYAML(t, "store:\n  folder: /tmp/x\n")
+
+	cfg, err := cfgpkg.ReadConfig(path)
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+
+	if cfg.Store.Folder != "/tmp/x" {
+		t.Fatalf("folder = %q", cfg.Store.Folder)
+	}
+	if cfg.Store.Shards != 512 || cfg.Server.HTTP.Port != 8089 || cfg.Log.Level != "info" {
+		t.Fatalf("defaults not applied: %+v", cfg)
+	}
+}
+
+func TestSource_Precedence(t *testing.T) {
+	path := writeYAML(t, "store:\n  folder: /tmp/x\n  shards: 16\n  flushIntervalSeconds: 7\n")
+
+	src, rest, err := cfgpkg.ParseFlags("test", []string{
+		"--config", path,
+		"--store.shards=32",
+		"--stats.enabled",
+	}, io.Discard)
+	if err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	if len(rest) != 0 {
+		t.Fatalf("unexpected positional args %v", rest)
+	}
+	src.Lookup = envMap(map[string]string{
+		"ELYSIAN_STORE_SHARDS":                  "64",
+		"ELYSIAN_STORE_FLUSH_INTERVAL_SECONDS":  "9",
+		"ELYSIAN_ENCRYPTION_PREVIOUS_KEY_FILES": "/a, /b",
+	})
+
+	cfg, err := src.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if cfg.Store.Shards != 32 {
+		t.Errorf("flag must win over env: shards = %d", cfg.Store.Shards)
+	}
+	if cfg.Store.FlushIntervalSeconds != 9 {
+		t.Errorf("env must win over file: flush = %d", cfg.Store.FlushIntervalSeconds)
+	}
+	if !cfg.Stats.Enabled {
+		t.Errorf("boolean flag not applied")
+	}
+	if !reflect.DeepEqual(cfg.Encryption.PreviousKeyFiles, []string{"/a", "/b"}) {
+		t.Errorf("previousKeyFiles = %v", cfg.Encryption.PreviousKeyFiles)
+	}
+}
+
+func TestSource_RejectsBadValues(t *testing.T) {
+	path := writeYAML(t, "store:\n  folder: /tmp/x\n")
+
+	src := cfgpkg.Source{Path: path, Lookup: envMap(map[string]string{"ELYSIAN_SERVER_HTTP_PORT": "http"})}
+	if _, err := src.Load(); err == nil || !strings.Contains(err.Error(), "server.http.port") {
+		t.Fatalf("expected integer parse error, got %v", err)
+	}
+
+	src = cfgpkg.Source{Path: path, Overrides: map[string]string{"store.shards": "0"}}
+	if _, err := src.Load(); err == nil || !strings.Contains(err.Error(), "store.shards") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+
+	if _, _, err := cfgpkg.ParseFlags("test", []string{"--store.nope=1"}, io.Discard); err == nil {
+		t.Fatalf("expected unknown flag error")
+	}
+}
+
+func TestCheck_PrintsEffectiveConfig(t *testing.T) {
+	path := writeYAML(t, "store:\n  folder: /tmp/x\n")
+
+	var out bytes.Buffer
+	src := cfgpkg.Source{Path: path, Overrides: map[string]string{"log.level": "debug"}}
+	if err := cfgpkg.Check(src, &out); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	for _, want := range []string{"folder: /tmp/x", "shards: 512", "level: debug"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package configuration_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	cfgpkg "github.com/taymour/elysiandb/internal/configuration"
+)
+
+func validConfig() *cfgpkg.Config {
+	return &cfgpkg.Config{
+		Store: cfgpkg.StoreConfig{Folder: "/tmp/elysian", Shards: 8, FlushIntervalSeconds: 5},
+		Server: cfgpkg.ServersConfig{
+			HTTP: cfgpkg.ServerConfig{Enabled: true, Host: "0.0.0.0", Port: 8089},
+			TCP:  cfgpkg.ServerConfig{Enabled: true, Host: "0.0.0.0", Port: 8088},
+		},
+	}
+}
+
+func TestValidate_OK(t *testing.T) {
+	if err := cfgpkg.Validate(validConfig()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestValidate_ReportsAllErrors(t *testing.T) {
+	cfg := validConfig()
+	cfg.Store.Shards = 3
+	cfg.Store.FlushIntervalSeconds = -1
+	cfg.Log.Level = "loud"
+
+	err := cfgpkg.Validate(cfg)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	for _, want := range []string{"store.shards", "store.flushIntervalSeconds", "log.level"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestValidate_PortConflict(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.TCP.Port = cfg.Server.HTTP.Port
+
+	if err := cfgpkg.Validate(cfg); err == nil {
+		t.Fatalf("expected port conflict error")
+	}
+}
+
+func TestValidate_Indexes(t *testing.T) {
+	cfg := validConfig()
+	cfg.Store.Indexes = []string{"by_email user:* $.email"}
+	if err := cfgpkg.Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, bad := range [][]string{
+		{"by_email user:*"},
+		{"by_email user:* $..email"},
+		{"by_email user:* $.email", "by_email order:* $.email"},
+	} {
+		cfg.Store.Indexes = bad
+		if err := cfgpkg.Validate(cfg); err == nil || !strings.Contains(err.Error(), "store.indexes") {
+			t.Fatalf("Validate(%v): expected store.indexes error, got %v", bad, err)
+		}
+	}
+}
+
+func TestDiff_AndCopyFields(t *testing.T) {
+	a := validConfig()
+	b := validConfig()
+	b.Store.FlushIntervalSeconds = 30
+	b.Log.Level = "debug"
+	b.Server.HTTP.Port = 9000
+
+	got := cfgpkg.Diff(a, b)
+	want := []string{"store.flushIntervalSeconds", "server.http.port", "log.level"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Diff = %v, want %v", got, want)
+	}
+
+	cfgpkg.CopyFields(a, b, []string{"store.flushIntervalSeconds", "log.level"})
+	if a.Store.FlushIntervalSeconds != 30 || a.Log.Level != "debug" {
+		t.Fatalf("CopyFields did not copy: %+v", a)
+	}
+	if a.Server.HTTP.Port != 8089 {
+		t.Fatalf("CopyFields copied an unrequested field")
+	}
+}
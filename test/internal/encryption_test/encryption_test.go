@@ -0,0 +1,204 @@
+package encryption_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/encryption"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, encryption.KeySize)
+}
+
+func TestSealOpen_RoundTrip(t *testing.T) {
+	if err := encryption.SetKeys([][]byte{testKey(1)}); err != nil {
+		t.Fatalf("SetKeys: %v", err)
+	}
+	defer encryption.SetKeys(nil)
+
+	plain := []byte(`{"foo":"YmFy"}`)
+	sealed, err := encryption.Seal(plain)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !encryption.IsSealed(sealed) || bytes.Contains(sealed, plain) {
+		t.Fatalf("sealed data should not contain plaintext: %q", sealed)
+	}
+
+	got, stale, err := encryption.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if stale {
+		t.Fatal("data sealed with the current key should not be stale")
+	}
+	if !bytes.Equal(got, plain) {
+		t.Fatalf("got %q, want %q", got, plain)
+	}
+}
+
+func TestOpen_RotatedKeyIsStale(t *testing.T) {
+	if err := encryption.SetKeys([][]byte{testKey(1)}); err != nil {
+		t.Fatalf("SetKeys: %v", err)
+	}
+	sealed, _ := encryption.Seal([]byte("payload"))
+
+	if err := encryption.SetKeys([][]byte{testKey(2), testKey(1)}); err != nil {
+		t.Fatalf("SetKeys: %v", err)
+	}
+	defer encryption.SetKeys(nil)
+
+	got, stale, err := encryption.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if !stale || string(got) != "payload" {
+		t.Fatalf("expected stale payload, got stale=%v %q", stale, got)
+	}
+
+	if err := encryption.SetKeys([][]byte{testKey(3)}); err != nil {
+		t.Fatalf("SetKeys: %v", err)
+	}
+	if _, _, err := encryption.Open(sealed); !errors.Is(err, encryption.ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestOpen_TamperedData(t *testing.T) {
+	if err := encryption.SetKeys([][]byte{testKey(1)}); err != nil {
+		t.Fatalf("SetKeys: %v", err)
+	}
+	defer encryption.SetKeys(nil)
+
+	sealed, _ := encryption.Seal([]byte("payload"))
+	sealed[len(sealed)-1] ^= 0xff
+
+	if _, _, err := encryption.Open(sealed); !errors.Is(err, encryption.ErrCorrupted) {
+		t.Fatalf("expected ErrCorrupted, got %v", err)
+	}
+}
+
+func TestOpen_EncryptedWithoutKey(t *testing.T) {
+	if err := encryption.SetKeys([][]byte{testKey(1)}); err != nil {
+		t.Fatalf("SetKeys: %v", err)
+	}
+	sealed, _ := encryption.Seal([]byte("payload"))
+	encryption.SetKeys(nil)
+
+	if _, _, err := encryption.Open(sealed); !errors.Is(err, encryption.ErrNoKey) {
+		t.Fatalf("expected ErrNoKey, got %v", err)
+	}
+}
+
+func TestLoadKeyring_FromFileAndEnv(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "key")
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(testKey(4))+"\n"), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	defer encryption.SetKeys(nil)
+
+	err := encryption.LoadKeyring(configuration.EncryptionConfig{Enabled: true, KeyFile: path})
+	if err != nil || !encryption.Enabled() {
+		t.Fatalf("LoadKeyring from file: err=%v enabled=%v", err, encryption.Enabled())
+	}
+
+	t.Setenv("TEST_ELYSIAN_KEY", hex.EncodeToString(testKey(5)))
+	err = encryption.LoadKeyring(configuration.EncryptionConfig{Enabled: true, KeyEnv: "TEST_ELYSIAN_KEY"})
+	if err != nil {
+		t.Fatalf("LoadKeyring from env: %v", err)
+	}
+
+	err = encryption.LoadKeyring(configuration.EncryptionConfig{Enabled: true, KeyEnv: "TEST_ELYSIAN_MISSING"})
+	if err == nil {
+		t.Fatal("expected error when key env is missing")
+	}
+}
+
+func TestStorage_SnapshotIsEncryptedAndReencryptedOnRotation(t *testing.T) {
+	tmp := t.TempDir()
+	globals.SetConfig(&configuration.Config{
+		Store: configuration.StoreConfig{Folder: tmp, Shards: 8},
+	})
+
+	if err := encryption.SetKeys([][]byte{testKey(1)}); err != nil {
+		t.Fatalf("SetKeys: %v", err)
+	}
+	defer encryption.SetKeys(nil)
+
+	storage.LoadDB()
+	_ = storage.PutKeyValue("session", []byte("secret-session-data"))
+	storage.WriteToDB()
+
+	path := filepath.Join(tmp, storage.DataFile)
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+	if !encryption.IsSealed(first) || bytes.Contains(first, []byte("secret")) {
+		t.Fatalf("snapshot is not encrypted: %q", first)
+	}
+
+	if err := encryption.SetKeys([][]byte{testKey(2), testKey(1)}); err != nil {
+		t.Fatalf("SetKeys: %v", err)
+	}
+
+	storage.LoadDB()
+	if v, err := storage.GetByKey("session"); err != nil || string(v) != "secret-session-data" {
+		t.Fatalf("GetByKey after rotation: %q, %v", v, err)
+	}
+	storage.WriteToDB()
+
+	encryption.SetKeys([][]byte{testKey(2)})
+	if _, err := storage.ReadFromDB(storage.DataFile); err != nil {
+		t.Fatalf("snapshot was not re-encrypted with the new key: %v", err)
+	}
+}
+
+func TestStorage_FencingReservationReencryptedOnRotation(t *testing.T) {
+	tmp := t.TempDir()
+	globals.SetConfig(&configuration.Config{
+		Store: configuration.StoreConfig{Folder: tmp, Shards: 8},
+	})
+
+	if err := encryption.SetKeys([][]byte{testKey(1)}); err != nil {
+		t.Fatalf("SetKeys: %v", err)
+	}
+	defer encryption.SetKeys(nil)
+
+	storage.LoadDB()
+	first, ok, err := storage.AcquireLock("job", time.Minute, false, 0, nil)
+	if err != nil || !ok {
+		t.Fatalf("AcquireLock: %v %v", ok, err)
+	}
+	_ = storage.ReleaseLock("job", first.Owner)
+
+	if err := encryption.SetKeys([][]byte{testKey(2), testKey(1)}); err != nil {
+		t.Fatalf("SetKeys: %v", err)
+	}
+	storage.LoadDB()
+
+	encryption.SetKeys([][]byte{testKey(2)})
+	raw, err := os.ReadFile(filepath.Join(tmp, storage.FencingDataFile))
+	if err != nil {
+		t.Fatalf("read fencing file: %v", err)
+	}
+	if _, _, err := encryption.Open(raw); err != nil {
+		t.Fatalf("fencing reservation was not re-encrypted with the new key: %v", err)
+	}
+
+	storage.LoadDB()
+	next, ok, err := storage.AcquireLock("job", time.Minute, false, 0, nil)
+	if err != nil || !ok || next.Fence <= first.Fence {
+		t.Fatalf("fence after reboot = %d (first %d), %v %v", next.Fence, first.Fence, ok, err)
+	}
+}
@@ -0,0 +1,65 @@
+package geo_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/taymour/elysiandb/internal/geo"
+)
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	for _, p := range [][2]float64{{13.361389, 38.115556}, {-122.4194, 37.7749}, {179.9999, -85}, {-180, 85.05}} {
+		lon, lat := geo.Decode(geo.Encode(p[0], p[1]))
+		if math.Abs(lon-p[0]) > 1e-5 || math.Abs(lat-p[1]) > 1e-5 {
+			t.Fatalf("Decode(Encode(%v)) = %v, %v", p, lon, lat)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	d := geo.Distance(13.361389, 38.115556, 15.087269, 37.502669)
+	if math.Abs(d-166274.15) > 1 {
+		t.Fatalf("Palermo-Catania = %.2f m", d)
+	}
+}
+
+func TestSearchRanges_CoverRadius(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 11))
+
+	for i := 0; i < 200; i++ {
+		lon := rng.Float64()*360 - 180
+		lat := rng.Float64()*160 - 80
+		radius := math.Pow(10, rng.Float64()*6)
+		ranges := geo.SearchRanges(lon, lat, radius)
+
+		for j := 0; j < 50; j++ {
+			plon := lon + (rng.Float64()*2-1)*radius/50000
+			plat := lat + (rng.Float64()*2-1)*radius/120000
+			if !geo.Valid(plon, plat) || geo.Distance(lon, lat, plon, plat) > radius {
+				continue
+			}
+
+			h := geo.Encode(plon, plat)
+			covered := false
+			for _, r := range ranges {
+				if h >= r.Min && h < r.Max {
+					covered = true
+					break
+				}
+			}
+			if !covered {
+				t.Fatalf("point %v,%v within %.0fm of %v,%v is not covered by %v", plon, plat, radius, lon, lat, ranges)
+			}
+		}
+	}
+}
+
+func TestUnitFactor(t *testing.T) {
+	if f, ok := geo.UnitFactor("KM"); !ok || f != 1000 {
+		t.Fatalf("UnitFactor(KM) = %v, %v", f, ok)
+	}
+	if _, ok := geo.UnitFactor("parsec"); ok {
+		t.Fatalf("unknown unit should be rejected")
+	}
+}
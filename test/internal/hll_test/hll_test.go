@@ -0,0 +1,76 @@
+package hll_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/taymour/elysiandb/internal/hll"
+)
+
+func within(t *testing.T, got uint64, want int, tolerance float64) {
+	t.Helper()
+	if diff := math.Abs(float64(got)-float64(want)) / float64(want); diff > tolerance {
+		t.Fatalf("estimate %d is %.2f%% off %d", got, diff*100, want)
+	}
+}
+
+func TestSketch_EstimatesCardinality(t *testing.T) {
+	for _, n := range []int{10, 1000, 100000} {
+		s := hll.New()
+		for i := 0; i < n; i++ {
+			s.Add([]byte("user:" + strconv.Itoa(i)))
+			s.Add([]byte("user:" + strconv.Itoa(i)))
+		}
+		within(t, s.Count(), n, 0.03)
+	}
+
+	if hll.New().Count() != 0 {
+		t.Fatalf("empty sketch should count 0")
+	}
+}
+
+func TestSketch_AddReportsChanges(t *testing.T) {
+	s := hll.New()
+	if !s.Add([]byte("a")) {
+		t.Fatalf("first add should change a register")
+	}
+	if s.Add([]byte("a")) {
+		t.Fatalf("repeated add should not change any register")
+	}
+}
+
+func TestSketch_MergeAndEncoding(t *testing.T) {
+	a, b := hll.New(), hll.New()
+	for i := 0; i < 30000; i++ {
+		a.Add([]byte("a" + strconv.Itoa(i)))
+		b.Add([]byte("b" + strconv.Itoa(i)))
+	}
+	small := hll.New()
+	small.Add([]byte("x"))
+
+	merged := a.Clone()
+	merged.Merge(b)
+	merged.Merge(small)
+	within(t, merged.Count(), 60001, 0.03)
+
+	for _, s := range []*hll.Sketch{merged, small} {
+		data, _ := s.MarshalBinary()
+		var decoded hll.Sketch
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary: %v", err)
+		}
+		if decoded.Count() != s.Count() {
+			t.Fatalf("decoded count %d != %d", decoded.Count(), s.Count())
+		}
+	}
+
+	if data, _ := small.MarshalBinary(); len(data) > 16 {
+		t.Fatalf("sparse sketch encoded in %d bytes", len(data))
+	}
+
+	var bad hll.Sketch
+	if err := bad.UnmarshalBinary([]byte{1, 2, 3}); err == nil {
+		t.Fatalf("expected corrupt encoding error")
+	}
+}
@@ -0,0 +1,155 @@
+package jsondoc_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/taymour/elysiandb/internal/jsondoc"
+)
+
+func mustDecode(t *testing.T, s string) any {
+	t.Helper()
+	v, err := jsondoc.Decode([]byte(s))
+	if err != nil {
+		t.Fatalf("Decode(%s): %v", s, err)
+	}
+	return v
+}
+
+func encode(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return string(b)
+}
+
+func TestDecode_RejectsInvalidAndTrailingInput(t *testing.T) {
+	for _, s := range []string{"", "{", `{"a":1} {}`, "nope"} {
+		if _, err := jsondoc.Decode([]byte(s)); !errors.Is(err, jsondoc.ErrInvalidJSON) {
+			t.Fatalf("Decode(%q): expected ErrInvalidJSON, got %v", s, err)
+		}
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	cases := map[string]jsondoc.Path{
+		"$":                   {},
+		"$.address.city":      {{Key: "address"}, {Key: "city"}},
+		"address.city":        {{Key: "address"}, {Key: "city"}},
+		"$.tags[1]":           {{Key: "tags"}, {Index: 1, IsIndex: true}},
+		"$['a.b'][-1]":        {{Key: "a.b"}, {Index: -1, IsIndex: true}},
+		`$["x"].y`:            {{Key: "x"}, {Key: "y"}},
+		"$.items[0].price[2]": {{Key: "items"}, {Index: 0, IsIndex: true}, {Key: "price"}, {Index: 2, IsIndex: true}},
+	}
+
+	for in, want := range cases {
+		got, err := jsondoc.ParsePath(in)
+		if err != nil {
+			t.Fatalf("ParsePath(%q): %v", in, err)
+		}
+		if encode(t, got) != encode(t, want) {
+			t.Fatalf("ParsePath(%q) = %v, want %v", in, got, want)
+		}
+	}
+
+	for _, in := range []string{"$..a", "$.a[", "$[x]", "$a"} {
+		if _, err := jsondoc.ParsePath(in); !errors.Is(err, jsondoc.ErrInvalidPath) {
+			t.Fatalf("ParsePath(%q): expected ErrInvalidPath, got %v", in, err)
+		}
+	}
+}
+
+func TestGetAndSet(t *testing.T) {
+	doc := mustDecode(t, `{"address":{"city":"Paris"},"tags":["a","b"]}`)
+
+	path, _ := jsondoc.ParsePath("$.address.city")
+	if v, ok := jsondoc.Get(doc, path); !ok || v != "Paris" {
+		t.Fatalf("Get city = %v, %v", v, ok)
+	}
+
+	path, _ = jsondoc.ParsePath("$.tags[-1]")
+	if v, ok := jsondoc.Get(doc, path); !ok || v != "b" {
+		t.Fatalf("Get last tag = %v, %v", v, ok)
+	}
+
+	path, _ = jsondoc.ParsePath("$.address.zip")
+	doc, err := jsondoc.Set(doc, path, "75001")
+	if err != nil {
+		t.Fatalf("Set zip: %v", err)
+	}
+
+	path, _ = jsondoc.ParsePath("$.tags[2]")
+	if doc, err = jsondoc.Set(doc, path, "c"); err != nil {
+		t.Fatalf("Set append: %v", err)
+	}
+
+	want := `{"address":{"city":"Paris","zip":"75001"},"tags":["a","b","c"]}`
+	if got := encode(t, doc); got != want {
+		t.Fatalf("doc = %s, want %s", got, want)
+	}
+
+	path, _ = jsondoc.ParsePath("$.missing.child")
+	if _, err := jsondoc.Set(doc, path, 1); !errors.Is(err, jsondoc.ErrPathNotFound) {
+		t.Fatalf("Set under missing parent: expected ErrPathNotFound, got %v", err)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	doc := mustDecode(t, `{"a":"b","c":{"d":"e","f":"g"}}`)
+	patch := mustDecode(t, `{"a":"z","c":{"f":null},"h":[1]}`)
+
+	want := `{"a":"z","c":{"d":"e"},"h":[1]}`
+	if got := encode(t, jsondoc.MergePatch(doc, patch)); got != want {
+		t.Fatalf("MergePatch = %s, want %s", got, want)
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	doc := mustDecode(t, `{"name":"ada","tags":["a","c"],"n":1.0}`)
+
+	var ops []jsondoc.PatchOp
+	_ = json.Unmarshal([]byte(`[
+		{"op":"test","path":"/n","value":1},
+		{"op":"add","path":"/tags/1","value":"b"},
+		{"op":"add","path":"/tags/-","value":"d"},
+		{"op":"replace","path":"/name","value":"Ada"},
+		{"op":"copy","from":"/name","path":"/alias"},
+		{"op":"move","from":"/n","path":"/count"},
+		{"op":"remove","path":"/tags/0"}
+	]`), &ops)
+
+	patched, err := jsondoc.ApplyPatch(doc, ops)
+	if err != nil {
+		t.Fatalf("ApplyPatch: %v", err)
+	}
+
+	want := `{"alias":"Ada","count":1.0,"name":"Ada","tags":["b","c","d"]}`
+	if got := encode(t, patched); got != want {
+		t.Fatalf("ApplyPatch = %s, want %s", got, want)
+	}
+	if got := encode(t, doc); got != `{"n":1.0,"name":"ada","tags":["a","c"]}` {
+		t.Fatalf("original document was modified: %s", got)
+	}
+}
+
+func TestApplyPatch_Errors(t *testing.T) {
+	doc := mustDecode(t, `{"a":1}`)
+
+	cases := map[string]error{
+		`[{"op":"test","path":"/a","value":2}]`:    jsondoc.ErrTestFailed,
+		`[{"op":"replace","path":"/b","value":2}]`: jsondoc.ErrPathNotFound,
+		`[{"op":"remove","path":"a"}]`:             jsondoc.ErrInvalidPath,
+		`[{"op":"frobnicate","path":"/a"}]`:        jsondoc.ErrInvalidPatch,
+	}
+
+	for raw, want := range cases {
+		var ops []jsondoc.PatchOp
+		_ = json.Unmarshal([]byte(raw), &ops)
+		if _, err := jsondoc.ApplyPatch(doc, ops); !errors.Is(err, want) {
+			t.Fatalf("ApplyPatch(%s): expected %v, got %v", raw, want, err)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -111,3 +112,34 @@ func TestFatalExitsWithNonZero(t *testing.T) {
 		t.Fatalf("expected non-zero exit code, got 0")
 	}
 }
+
+func TestFatalIsWrittenThroughTheSinks(t *testing.T) {
+	if path := os.Getenv("FATAL_LOG_FILE"); path != "" {
+		if err := pkglog.Configure(pkglog.Options{Level: pkglog.LevelError, JSON: true, File: path}); err != nil {
+			t.Fatalf("Configure: %v", err)
+		}
+		pkglog.Error("queued")
+		pkglog.Fatal("Error loading database:", fmt.Errorf("kaboom"))
+		return
+	}
+
+	path := filepath.Join(t.TempDir(), "fatal.log")
+	cmd := exec.Command(os.Args[0], "-test.run", t.Name())
+	cmd.Env = append(os.Environ(), "FATAL_LOG_FILE="+path)
+	stdout, _ := cmd.Output()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "queued") {
+		t.Fatalf("file sink = %q", data)
+	}
+	if !strings.Contains(lines[1], `"level":"fatal"`) || !strings.Contains(lines[1], `"msg":"Error loading database: kaboom"`) {
+		t.Fatalf("fatal line = %q", lines[1])
+	}
+	if !strings.Contains(string(stdout), `"level":"fatal"`) {
+		t.Fatalf("stdout = %q", stdout)
+	}
+}
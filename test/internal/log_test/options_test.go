@@ -0,0 +1,184 @@
+package log_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pkglog "github.com/taymour/elysiandb/internal/log"
+)
+
+func configure(t *testing.T, opts pkglog.Options) {
+	t.Helper()
+	if err := pkglog.Configure(opts); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = pkglog.Configure(pkglog.Options{Level: pkglog.LevelDebug})
+	})
+}
+
+func TestLevelFilteringAndOrdering(t *testing.T) {
+	configure(t, pkglog.Options{Level: pkglog.LevelWarn})
+
+	finish := captureStdout(t)
+	pkglog.Info("skipped-info")
+	for i := 0; i < 50; i++ {
+		pkglog.Warn(fmt.Sprintf("warn-%02d", i))
+	}
+	pkglog.Debug("skipped-debug")
+	pkglog.WriteLogs()
+	out := finish()
+
+	if strings.Contains(out, "skipped-") {
+		t.Fatalf("messages below the configured level were written:\n%s", out)
+	}
+
+	last := -1
+	for i := 0; i < 50; i++ {
+		idx := strings.Index(out, fmt.Sprintf("warn-%02d", i))
+		if idx <= last {
+			t.Fatalf("warn-%02d out of order or missing:\n%s", i, out)
+		}
+		last = idx
+	}
+}
+
+func TestRingBufferDropsOldestAndReports(t *testing.T) {
+	configure(t, pkglog.Options{Level: pkglog.LevelDebug, BufferSize: 4})
+
+	finish := captureStdout(t)
+	for i := 0; i < 10; i++ {
+		pkglog.Info(fmt.Sprintf("msg-%d", i))
+	}
+	if d := pkglog.Dropped(); d != 6 {
+		t.Errorf("Dropped() = %d, want 6", d)
+	}
+	pkglog.WriteLogs()
+	out := finish()
+
+	if strings.Contains(out, "msg-5\n") || !strings.Contains(out, "msg-9") || !strings.Contains(out, "msg-6") {
+		t.Fatalf("expected only the newest 4 messages, got:\n%s", out)
+	}
+	if !strings.Contains(out, "6 message(s) dropped") {
+		t.Fatalf("expected a dropped-messages warning, got:\n%s", out)
+	}
+	if pkglog.Dropped() != 0 {
+		t.Fatal("drop counter should be cleared after a flush")
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	configure(t, pkglog.Options{Level: pkglog.LevelDebug, JSON: true})
+
+	finish := captureStdout(t)
+	pkglog.Error("json-message")
+	pkglog.WriteLogs()
+	out := strings.TrimSpace(finish())
+
+	var line struct {
+		Time  string `json:"time"`
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+	}
+	if err := json.Unmarshal([]byte(out), &line); err != nil {
+		t.Fatalf("output is not JSON: %v (%q)", err, out)
+	}
+	if line.Level != "error" || line.Msg != "json-message" || line.Time == "" {
+		t.Fatalf("unexpected JSON line: %+v", line)
+	}
+	if strings.Contains(out, "\033[") {
+		t.Fatalf("JSON output must not contain ANSI colours: %q", out)
+	}
+}
+
+func TestFileSinkRotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "elysian.log")
+
+	configure(t, pkglog.Options{Level: pkglog.LevelDebug, File: path, MaxSizeMB: 0, MaxBackups: 2})
+	finish := captureStdout(t)
+	pkglog.Info("first")
+	pkglog.WriteLogs()
+	finish()
+
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), "first") {
+		t.Fatalf("log file missing message: %q (%v)", data, err)
+	}
+	if strings.Contains(string(data), "\033[") {
+		t.Fatalf("file sink must not contain ANSI colours: %q", data)
+	}
+
+	configure(t, pkglog.Options{Level: pkglog.LevelDebug, File: path, MaxSizeMB: 1, MaxBackups: 2})
+	finish = captureStdout(t)
+	big := strings.Repeat("x", 400<<10)
+	for i := 0; i < 8; i++ {
+		pkglog.Info(big)
+		pkglog.WriteLogs()
+		time.Sleep(2 * time.Millisecond)
+	}
+	finish()
+
+	backups, _ := filepath.Glob(path + ".*")
+	if len(backups) == 0 {
+		t.Fatal("expected at least one rotated backup")
+	}
+	if len(backups) > 2 {
+		t.Fatalf("expected at most 2 backups, got %d: %v", len(backups), backups)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > 1<<20 {
+		t.Fatalf("active log file exceeds max size: %v (%v)", info.Size(), err)
+	}
+}
+
+func TestFileSinkEncryptsLines(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "elysian.log")
+
+	configure(t, pkglog.Options{
+		Level: pkglog.LevelDebug,
+		File:  path,
+		Encrypt: func(b []byte) ([]byte, error) {
+			out := make([]byte, len(b))
+			for i := range b {
+				out[i] = b[i] ^ 0x5a
+			}
+			return out, nil
+		},
+	})
+
+	finish := captureStdout(t)
+	pkglog.Info("top-secret")
+	pkglog.WriteLogs()
+	finish()
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "top-secret") || len(strings.TrimSpace(string(data))) == 0 {
+		t.Fatalf("log file should contain only encrypted lines, got %q", data)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]pkglog.Level{
+		"":      pkglog.LevelInfo,
+		"debug": pkglog.LevelDebug,
+		"WARN":  pkglog.LevelWarn,
+		"error": pkglog.LevelError,
+	} {
+		got, err := pkglog.ParseLevel(in)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+
+	if _, err := pkglog.ParseLevel("verbose"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
@@ -0,0 +1,124 @@
+package reload_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/reload"
+)
+
+func writeConfig(t *testing.T, path string, body string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write yaml: %v", err)
+	}
+}
+
+func setup(t *testing.T) string {
+	t.Helper()
+
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "elysian.yaml")
+	writeConfig(t, path, `
+store:
+  folder: "`+filepath.ToSlash(tmp)+`"
+  shards: 8
+  flushIntervalSeconds: 5
+server:
+  http:
+    enabled: true
+    host: "127.0.0.1"
+    port: 9090
+log:
+  flushIntervalSeconds: 1
+`)
+
+	cfg, err := configuration.ReadConfig(path)
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+	globals.SetConfig(cfg)
+	globals.SetConfigSource(configuration.Source{Path: path})
+
+	return tmp
+}
+
+func TestReload_AppliesRuntimeSettings(t *testing.T) {
+	tmp := setup(t)
+	path := globals.GetConfigSource().Path
+
+	var hooked []string
+	reload.OnReload(func(applied []string, cfg *configuration.Config) {
+		hooked = applied
+	})
+
+	writeConfig(t, path, `
+store:
+  folder: "`+filepath.ToSlash(tmp)+`"
+  shards: 16
+  flushIntervalSeconds: 30
+  maxValueBytes: 1024
+server:
+  http:
+    enabled: true
+    host: "127.0.0.1"
+    port: 9090
+log:
+  flushIntervalSeconds: 1
+  level: debug
+`)
+
+	res, err := reload.Reload()
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	wantApplied := []string{"store.flushIntervalSeconds", "store.maxValueBytes", "log.level"}
+	if !reflect.DeepEqual(res.Applied, wantApplied) {
+		t.Fatalf("Applied = %v, want %v", res.Applied, wantApplied)
+	}
+	if !reflect.DeepEqual(res.RestartRequired, []string{"store.shards"}) {
+		t.Fatalf("RestartRequired = %v", res.RestartRequired)
+	}
+	if !reflect.DeepEqual(hooked, wantApplied) {
+		t.Fatalf("hook received %v", hooked)
+	}
+
+	cfg := globals.GetConfig()
+	if cfg.Store.FlushIntervalSeconds != 30 || cfg.Store.MaxValueBytes != 1024 || cfg.Log.Level != "debug" {
+		t.Fatalf("runtime settings not applied: %+v", cfg)
+	}
+	if cfg.Store.Shards != 8 {
+		t.Fatalf("restart-only setting must not be applied, shards=%d", cfg.Store.Shards)
+	}
+}
+
+func TestReload_RejectsInvalidConfig(t *testing.T) {
+	tmp := setup(t)
+	before := globals.GetConfig()
+
+	writeConfig(t, globals.GetConfigSource().Path, `
+store:
+  folder: "`+filepath.ToSlash(tmp)+`"
+  shards: 8
+  flushIntervalSeconds: -3
+log:
+  level: loud
+`)
+
+	if _, err := reload.Reload(); err == nil {
+		t.Fatalf("expected invalid config to be rejected")
+	}
+	if globals.GetConfig() != before {
+		t.Fatalf("config must not change when reload is rejected")
+	}
+
+	writeConfig(t, globals.GetConfigSource().Path, "store: [")
+	if _, err := reload.Reload(); err == nil {
+		t.Fatalf("expected malformed yaml to be rejected")
+	}
+}
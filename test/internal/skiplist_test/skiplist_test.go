@@ -0,0 +1,115 @@
+package skiplist_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"testing"
+
+	"github.com/taymour/elysiandb/internal/skiplist"
+)
+
+func reference(scores map[string]float64) []skiplist.Element {
+	out := make([]skiplist.Element, 0, len(scores))
+	for m, s := range scores {
+		out = append(out, skiplist.Element{Member: m, Score: s})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score < out[j].Score
+		}
+		return out[i].Member < out[j].Member
+	})
+	return out
+}
+
+func TestSkiplist_MatchesSortedReference(t *testing.T) {
+	l := skiplist.New()
+	scores := make(map[string]float64)
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for i := 0; i < 5000; i++ {
+		m := fmt.Sprintf("m%d", rng.IntN(500))
+		if old, ok := scores[m]; ok {
+			if !l.Delete(m, old) {
+				t.Fatalf("Delete(%s, %v) failed", m, old)
+			}
+			delete(scores, m)
+			if rng.IntN(2) == 0 {
+				continue
+			}
+		}
+		s := float64(rng.IntN(50))
+		l.Insert(m, s)
+		scores[m] = s
+	}
+
+	want := reference(scores)
+	if l.Len() != len(want) {
+		t.Fatalf("Len = %d, want %d", l.Len(), len(want))
+	}
+
+	got := l.Range(0, l.Len()-1)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Range[%d] = %v, want %v", i, got[i], want[i])
+		}
+		if r, ok := l.Rank(want[i].Member, want[i].Score); !ok || r != i {
+			t.Fatalf("Rank(%s) = %d %v, want %d", want[i].Member, r, ok, i)
+		}
+	}
+
+	if sub := l.Range(10, 14); len(sub) != 5 || sub[0] != want[10] || sub[4] != want[14] {
+		t.Fatalf("Range(10, 14) = %v", sub)
+	}
+	if _, ok := l.Rank("missing", 1); ok {
+		t.Fatalf("Rank of missing member should fail")
+	}
+}
+
+func TestSkiplist_RangeByScore(t *testing.T) {
+	l := skiplist.New()
+	for i, m := range []string{"a", "b", "c", "d", "e"} {
+		l.Insert(m, float64(i))
+	}
+
+	got := l.RangeByScore(skiplist.ScoreRange{Min: 1, Max: 3}, 0, -1)
+	if len(got) != 3 || got[0].Member != "b" || got[2].Member != "d" {
+		t.Fatalf("inclusive range = %v", got)
+	}
+
+	got = l.RangeByScore(skiplist.ScoreRange{Min: 1, Max: 3, MinExclusive: true, MaxExclusive: true}, 0, -1)
+	if len(got) != 1 || got[0].Member != "c" {
+		t.Fatalf("exclusive range = %v", got)
+	}
+
+	got = l.RangeByScore(skiplist.ScoreRange{Min: 0, Max: 10}, 1, 2)
+	if len(got) != 2 || got[0].Member != "b" || got[1].Member != "c" {
+		t.Fatalf("limited range = %v", got)
+	}
+}
+
+func TestSkiplist_Ascend(t *testing.T) {
+	l := skiplist.New()
+	for i, m := range []string{"a", "b", "c", "d", "e"} {
+		l.Insert(m, float64(i/2))
+	}
+
+	var got []string
+	l.Ascend(1, "c", func(e skiplist.Element) bool {
+		got = append(got, e.Member)
+		return e.Member != "d"
+	})
+	if fmt.Sprint(got) != "[c d]" {
+		t.Fatalf("Ascend from (1, c) = %v", got)
+	}
+
+	got = nil
+	l.Ascend(1, "", func(e skiplist.Element) bool {
+		got = append(got, e.Member)
+		return true
+	})
+	if fmt.Sprint(got) != "[c d e]" {
+		t.Fatalf("Ascend from score 1 = %v", got)
+	}
+}
@@ -0,0 +1,66 @@
+package stat_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/stat"
+)
+
+func TestToOpenMetrics_ExposesCounters(t *testing.T) {
+	s := stat.NewStatsContainer()
+
+	s.SetKeysCount(3)
+	s.IncrementHits()
+	s.ObserveCommand(stat.ProtocolTCP, "GET", 200*time.Microsecond)
+	s.ObserveCommand(stat.ProtocolTCP, "GET", 3*time.Millisecond)
+	s.ObserveCommand(stat.ProtocolHTTP, "PUT /kv/{key}", time.Millisecond)
+	s.AddBytesIn(stat.ProtocolTCP, 10)
+	s.AddBytesOut(stat.ProtocolTCP, 4)
+	s.IncrementTCPClients()
+	s.AddExpiredKeys(2)
+	s.ObserveSnapshot(5*time.Millisecond, 1234)
+
+	out := s.ToOpenMetrics()
+
+	for _, want := range []string{
+		"elysiandb_keys 3\n",
+		"elysiandb_hits_total 1\n",
+		`elysiandb_command_requests_total{protocol="tcp",command="GET"} 2`,
+		`elysiandb_command_requests_total{protocol="http",command="PUT /kv/{key}"} 1`,
+		`elysiandb_request_duration_seconds_bucket{protocol="tcp",le="0.00025"} 1`,
+		`elysiandb_request_duration_seconds_bucket{protocol="tcp",le="+Inf"} 2`,
+		`elysiandb_request_duration_seconds_count{protocol="tcp"} 2`,
+		`elysiandb_received_bytes_total{protocol="tcp"} 10`,
+		`elysiandb_sent_bytes_total{protocol="tcp"} 4`,
+		"elysiandb_tcp_connected_clients 1\n",
+		"elysiandb_expired_keys_total 2\n",
+		"elysiandb_snapshot_size_bytes 1234\n",
+		"elysiandb_snapshot_duration_seconds 0.005\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics output missing %q\n%s", want, out)
+		}
+	}
+
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Errorf("metrics output must end with # EOF, got:\n%s", out)
+	}
+}
+
+func TestReset_ClearsMetrics(t *testing.T) {
+	s := stat.NewStatsContainer()
+	s.ObserveCommand(stat.ProtocolTCP, "SET", time.Millisecond)
+	s.AddExpiredKeys(5)
+
+	s.Reset()
+
+	out := s.ToOpenMetrics()
+	if strings.Contains(out, `command="SET"`) {
+		t.Errorf("per-command counters should be cleared after Reset:\n%s", out)
+	}
+	if !strings.Contains(out, "elysiandb_expired_keys_total 0\n") {
+		t.Errorf("expired keys should be cleared after Reset:\n%s", out)
+	}
+}
@@ -0,0 +1,121 @@
+package stat_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/taymour/elysiandb/internal/stat"
+)
+
+func TestSlowlog_ThresholdAndOrdering(t *testing.T) {
+	s := stat.NewSlowlog(3, 5*time.Millisecond)
+
+	if s.IsSlow(time.Millisecond) {
+		t.Fatal("1ms should be below the 5ms threshold")
+	}
+	if !s.IsSlow(5 * time.Millisecond) {
+		t.Fatal("5ms should reach the threshold")
+	}
+
+	now := time.Now()
+	for i := 1; i <= 5; i++ {
+		s.Add(stat.ProtocolTCP, "GET", fmt.Sprintf("key:%d", i), now, time.Duration(i)*time.Millisecond)
+	}
+
+	if n := s.Len(); n != 3 {
+		t.Fatalf("Len = %d, want 3 (bounded)", n)
+	}
+
+	entries := s.Get(-1)
+	if len(entries) != 3 {
+		t.Fatalf("Get returned %d entries, want 3", len(entries))
+	}
+	for i, want := range []string{"key:5", "key:4", "key:3"} {
+		if entries[i].Key != want {
+			t.Fatalf("entries[%d].Key = %q, want %q (newest first)", i, entries[i].Key, want)
+		}
+	}
+	if entries[0].ID != 5 || entries[0].DurationMicros != 5000 {
+		t.Fatalf("unexpected newest entry: %+v", entries[0])
+	}
+
+	if got := s.Get(1); len(got) != 1 || got[0].Key != "key:5" {
+		t.Fatalf("Get(1) = %+v", got)
+	}
+
+	s.Reset()
+	if s.Len() != 0 {
+		t.Fatal("expected empty slowlog after Reset")
+	}
+}
+
+func TestSlowlog_TruncatesKeysAndResizes(t *testing.T) {
+	s := stat.NewSlowlog(4, 0)
+
+	long := strings.Repeat("k", 200)
+	s.Add(stat.ProtocolHTTP, "GET /kv/{key}", long, time.Now(), time.Millisecond)
+	s.Add(stat.ProtocolHTTP, "GET /kv/{key}", "short", time.Now(), time.Millisecond)
+
+	e := s.Get(-1)
+	if len(e[1].Key) != stat.SlowlogMaxKeyLength+3 || !strings.HasSuffix(e[1].Key, "...") {
+		t.Fatalf("key was not truncated: %q", e[1].Key)
+	}
+
+	s.Configure(true, 1, 0)
+	if got := s.Get(-1); len(got) != 1 || got[0].Key != "short" {
+		t.Fatalf("shrinking should keep the newest entry, got %+v", got)
+	}
+
+	s.Configure(false, 1, 0)
+	if s.IsSlow(time.Hour) {
+		t.Fatal("disabled slowlog should never report slow commands")
+	}
+}
+
+func TestSlowlog_TruncatesOnARuneBoundary(t *testing.T) {
+	s := stat.NewSlowlog(1, 0)
+
+	key := "k" + strings.Repeat("é", 100)
+	s.Add(stat.ProtocolTCP, "GET", key, time.Now(), time.Millisecond)
+
+	got := s.Get(-1)[0].Key
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncated key is not valid UTF-8: %q", got)
+	}
+	if want := key[:stat.SlowlogMaxKeyLength-1] + "..."; got != want {
+		t.Fatalf("truncated key = %q, want %q", got, want)
+	}
+}
+
+func TestLatencyHistogram_Quantiles(t *testing.T) {
+	var h stat.LatencyHistogram
+
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Microsecond)
+	}
+
+	if h.Count() != 1000 {
+		t.Fatalf("Count = %d, want 1000", h.Count())
+	}
+	if h.Max() != 1000*time.Microsecond {
+		t.Fatalf("Max = %v, want 1ms", h.Max())
+	}
+
+	check := func(q float64, want time.Duration) {
+		got := h.Quantile(q)
+		lo, hi := want-want/16, want+want/16
+		if got < lo || got > hi {
+			t.Errorf("Quantile(%v) = %v, want ~%v", q, got, want)
+		}
+	}
+	check(0.5, 500*time.Microsecond)
+	check(0.99, 990*time.Microsecond)
+
+	h.Reset()
+	if h.Count() != 0 || h.Quantile(0.5) != 0 {
+		t.Fatal("expected empty histogram after Reset")
+	}
+}
@@ -0,0 +1,92 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/taymour/elysiandb/internal/jsondoc"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestDocument_PathGetAndSet(t *testing.T) {
+	loadTmpDB(t)
+
+	if err := storage.SetDocument("user:1", []byte(`{"name":"Ada","address":{"city":"London"}}`)); err != nil {
+		t.Fatalf("SetDocument: %v", err)
+	}
+
+	if err := storage.SetDocumentPath("user:1", "$.address.city", []byte(`"Paris"`)); err != nil {
+		t.Fatalf("SetDocumentPath: %v", err)
+	}
+
+	got, found, err := storage.GetDocument("user:1", "$.address")
+	if err != nil || !found || string(got) != `{"city":"Paris"}` {
+		t.Fatalf("GetDocument = %s, %v, %v", got, found, err)
+	}
+
+	if _, _, err := storage.GetDocument("user:1", "$.address.zip"); !errors.Is(err, jsondoc.ErrPathNotFound) {
+		t.Fatalf("expected ErrPathNotFound, got %v", err)
+	}
+	if _, found, _ := storage.GetDocument("missing", "$"); found {
+		t.Fatalf("missing document should not be found")
+	}
+
+	if err := storage.SetDocumentPath("missing", "$.a", []byte(`1`)); !errors.Is(err, jsondoc.ErrPathNotFound) {
+		t.Fatalf("sub-path set on missing key: expected ErrPathNotFound, got %v", err)
+	}
+	if err := storage.SetDocument("bad", []byte(`{"a":`)); !errors.Is(err, jsondoc.ErrInvalidJSON) {
+		t.Fatalf("expected ErrInvalidJSON, got %v", err)
+	}
+
+	_ = storage.PutKeyValue("plain", []byte("v"))
+	if err := storage.SetDocument("plain", []byte(`{}`)); !errors.Is(err, storage.ErrWrongType) {
+		t.Fatalf("expected WRONGTYPE, got %v", err)
+	}
+}
+
+func TestDocument_Patch(t *testing.T) {
+	loadTmpDB(t)
+
+	got, err := storage.PatchDocument("cfg", []byte(`{"a":1,"b":{"c":2}}`), storage.MergePatch)
+	if err != nil || string(got) != `{"a":1,"b":{"c":2}}` {
+		t.Fatalf("merge patch creating key = %s, %v", got, err)
+	}
+
+	got, err = storage.PatchDocument("cfg", []byte(`{"a":null,"b":{"d":3}}`), storage.MergePatch)
+	if err != nil || string(got) != `{"b":{"c":2,"d":3}}` {
+		t.Fatalf("merge patch = %s, %v", got, err)
+	}
+
+	got, err = storage.PatchDocument("cfg", []byte(`[{"op":"add","path":"/list","value":[1]},{"op":"remove","path":"/b/c"}]`), storage.JSONPatch)
+	if err != nil || string(got) != `{"b":{"d":3},"list":[1]}` {
+		t.Fatalf("json patch = %s, %v", got, err)
+	}
+
+	_, err = storage.PatchDocument("cfg", []byte(`[{"op":"remove","path":"/b"},{"op":"test","path":"/list/0","value":2}]`), storage.JSONPatch)
+	if !errors.Is(err, jsondoc.ErrTestFailed) {
+		t.Fatalf("expected ErrTestFailed, got %v", err)
+	}
+	if got, _, _ := storage.GetDocument("cfg", "$"); string(got) != `{"b":{"d":3},"list":[1]}` {
+		t.Fatalf("failed patch must not modify the document, got %s", got)
+	}
+
+	if _, err := storage.PatchDocument("missing", []byte(`[]`), storage.JSONPatch); !errors.Is(err, jsondoc.ErrPathNotFound) {
+		t.Fatalf("json patch on missing key: expected ErrPathNotFound, got %v", err)
+	}
+}
+
+func TestDocument_PersistedInSnapshot(t *testing.T) {
+	loadTmpDB(t)
+
+	_ = storage.SetDocument("doc", []byte(`{"big":12345678901234567890,"f":1.50}`))
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+
+	storage.LoadDB()
+
+	got, found, err := storage.GetDocument("doc", "$")
+	if err != nil || !found || string(got) != `{"big":12345678901234567890,"f":1.50}` {
+		t.Fatalf("reloaded document = %s, %v, %v", got, found, err)
+	}
+}
@@ -0,0 +1,90 @@
+package storage_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/taymour/elysiandb/internal/geo"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func seedCouriers(t *testing.T) {
+	t.Helper()
+	added, err := storage.GeoAdd("couriers", []storage.GeoPoint{
+		{Member: "louvre", Lon: 2.3376, Lat: 48.8606},
+		{Member: "notre-dame", Lon: 2.3499, Lat: 48.8530},
+		{Member: "eiffel", Lon: 2.2945, Lat: 48.8584},
+		{Member: "versailles", Lon: 2.1204, Lat: 48.8049},
+	})
+	if err != nil || added != 4 {
+		t.Fatalf("GeoAdd = %d, %v", added, err)
+	}
+}
+
+func members(matches []storage.GeoMatch) []string {
+	out := make([]string, len(matches))
+	for i, m := range matches {
+		out[i] = m.Member
+	}
+	return out
+}
+
+func TestGeo_PosAndDist(t *testing.T) {
+	loadTmpDB(t)
+	seedCouriers(t)
+
+	points, err := storage.GeoPos("couriers", []string{"eiffel", "nope"})
+	if err != nil || points[1] != nil || math.Abs(points[0].Lon-2.2945) > 1e-5 || math.Abs(points[0].Lat-48.8584) > 1e-5 {
+		t.Fatalf("GeoPos = %+v, %v", points, err)
+	}
+
+	d, err := storage.GeoDist("couriers", "louvre", "notre-dame")
+	if err != nil || d < 1200 || d > 1300 {
+		t.Fatalf("GeoDist = %.1f, %v", d, err)
+	}
+	if _, err := storage.GeoDist("couriers", "louvre", "nope"); !errors.Is(err, storage.ErrNoSuchMember) {
+		t.Fatalf("expected ErrNoSuchMember, got %v", err)
+	}
+
+	if _, err := storage.GeoAdd("couriers", []storage.GeoPoint{{Member: "x", Lon: 200, Lat: 0}}); !errors.Is(err, geo.ErrInvalidCoordinates) {
+		t.Fatalf("expected ErrInvalidCoordinates, got %v", err)
+	}
+}
+
+func TestGeo_Search(t *testing.T) {
+	loadTmpDB(t)
+	seedCouriers(t)
+
+	matches, err := storage.GeoSearch("couriers", storage.GeoQuery{Lon: 2.3400, Lat: 48.8580, Radius: 2000})
+	if err != nil {
+		t.Fatalf("GeoSearch: %v", err)
+	}
+	if got := members(matches); len(got) != 2 || got[0] != "louvre" || got[1] != "notre-dame" {
+		t.Fatalf("radius search = %v", got)
+	}
+	if matches[0].Dist > matches[1].Dist {
+		t.Fatalf("results should be sorted by distance")
+	}
+
+	matches, _ = storage.GeoSearch("couriers", storage.GeoQuery{Member: "louvre", Radius: 5000, Desc: true, Count: 2})
+	if got := members(matches); len(got) != 2 || got[0] != "eiffel" || got[1] != "notre-dame" {
+		t.Fatalf("member search desc = %v", got)
+	}
+
+	matches, _ = storage.GeoSearch("couriers", storage.GeoQuery{Lon: 2.3400, Lat: 48.8580, Width: 10000, Height: 1000})
+	if got := members(matches); len(got) != 2 || got[0] != "louvre" || got[1] != "eiffel" {
+		t.Fatalf("box search = %v", got)
+	}
+
+	if matches, _ := storage.GeoSearch("missing", storage.GeoQuery{Lon: 0, Lat: 0, Radius: 10}); len(matches) != 0 {
+		t.Fatalf("search on missing key = %v", matches)
+	}
+	if _, err := storage.GeoSearch("couriers", storage.GeoQuery{Member: "nope", Radius: 10}); !errors.Is(err, storage.ErrNoSuchMember) {
+		t.Fatalf("expected ErrNoSuchMember, got %v", err)
+	}
+
+	if rank, _, ok, _ := storage.ZRank("couriers", "eiffel"); !ok || rank < 0 {
+		t.Fatalf("geo keys should be readable as sorted sets")
+	}
+}
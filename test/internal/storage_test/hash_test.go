@@ -0,0 +1,113 @@
+package storage_test
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func loadTmpDB(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	globals.SetConfig(&configuration.Config{
+		Store: configuration.StoreConfig{Folder: dir, Shards: 4},
+	})
+	storage.LoadDB()
+	return dir
+}
+
+func TestHash_FieldOperations(t *testing.T) {
+	loadTmpDB(t)
+
+	added, err := storage.HSet("user:1", []storage.HashField{
+		{Field: "name", Value: []byte("Ada Lovelace")},
+		{Field: "visits", Value: []byte("1")},
+	})
+	if err != nil || added != 2 {
+		t.Fatalf("HSet: added=%d err=%v", added, err)
+	}
+
+	if v, ok, _ := storage.HGet("user:1", "name"); !ok || string(v) != "Ada Lovelace" {
+		t.Fatalf("HGet name = %q (%v)", v, ok)
+	}
+
+	if n, err := storage.HIncrBy("user:1", "visits", 41); err != nil || n != 42 {
+		t.Fatalf("HIncrBy = %d, %v", n, err)
+	}
+	if _, err := storage.HIncrBy("user:1", "name", 1); !errors.Is(err, storage.ErrNotInteger) {
+		t.Fatalf("expected ErrNotInteger, got %v", err)
+	}
+
+	if _, err := storage.HIncrBy("user:1", "big", math.MaxInt64); err != nil {
+		t.Fatalf("HIncrBy MaxInt64: %v", err)
+	}
+	if _, err := storage.HIncrBy("user:1", "big", 1); !errors.Is(err, storage.ErrOverflow) {
+		t.Fatalf("expected ErrOverflow, got %v", err)
+	}
+	if _, err := storage.HIncrBy("user:1", "low", math.MinInt64); err != nil {
+		t.Fatalf("HIncrBy MinInt64: %v", err)
+	}
+	if _, err := storage.HIncrBy("user:1", "low", -1); !errors.Is(err, storage.ErrOverflow) {
+		t.Fatalf("expected ErrOverflow on underflow, got %v", err)
+	}
+	if v, _, _ := storage.HGet("user:1", "big"); string(v) != strconv.FormatInt(math.MaxInt64, 10) {
+		t.Fatalf("big after overflow = %s", v)
+	}
+	_, _ = storage.HDel("user:1", []string{"big", "low"})
+
+	values, _ := storage.HMGet("user:1", []string{"visits", "missing"})
+	if string(values[0]) != "42" || values[1] != nil {
+		t.Fatalf("HMGet = %q", values)
+	}
+
+	if n, _ := storage.HDel("user:1", []string{"name", "visits", "nope"}); n != 2 {
+		t.Fatalf("HDel removed %d, want 2", n)
+	}
+	if storage.Exists("user:1") {
+		t.Fatalf("hash without fields must be removed")
+	}
+}
+
+func TestHash_WrongType(t *testing.T) {
+	loadTmpDB(t)
+
+	_ = storage.PutKeyValue("plain", []byte("v"))
+	if _, err := storage.HSet("plain", []storage.HashField{{Field: "f", Value: []byte("v")}}); !errors.Is(err, storage.ErrWrongType) {
+		t.Fatalf("HSet on string: expected ErrWrongType, got %v", err)
+	}
+
+	_, _ = storage.HSet("h", []storage.HashField{{Field: "f", Value: []byte("v")}})
+	if _, err := storage.GetByKey("h"); !errors.Is(err, storage.ErrWrongType) {
+		t.Fatalf("GetByKey on hash: expected ErrWrongType, got %v", err)
+	}
+	if typ, ok := storage.TypeOf("h"); !ok || typ != storage.TypeHash {
+		t.Fatalf("TypeOf(h) = %v, %v", typ, ok)
+	}
+
+	if err := storage.PutKeyValue("h", []byte("now a string")); err != nil {
+		t.Fatalf("SET must overwrite any type: %v", err)
+	}
+	if v, _ := storage.GetByKey("h"); string(v) != "now a string" {
+		t.Fatalf("GetByKey after overwrite = %q", v)
+	}
+}
+
+func TestHash_PersistedInSnapshot(t *testing.T) {
+	loadTmpDB(t)
+
+	_, _ = storage.HSet("profile", []storage.HashField{{Field: "city", Value: []byte("Paris")}})
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+
+	storage.LoadDB()
+
+	if v, ok, err := storage.HGet("profile", "city"); err != nil || !ok || string(v) != "Paris" {
+		t.Fatalf("hash not restored: %q %v %v", v, ok, err)
+	}
+}
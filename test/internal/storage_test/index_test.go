@@ -0,0 +1,199 @@
+package storage_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func queryKeys(t *testing.T, name string, q storage.IndexQuery) []string {
+	t.Helper()
+	matches, err := storage.QueryIndex(name, q)
+	if err != nil {
+		t.Fatalf("QueryIndex(%s): %v", name, err)
+	}
+	keys := make([]string, len(matches))
+	for i, m := range matches {
+		keys[i] = m.Key
+	}
+	return keys
+}
+
+func eq(v string) storage.IndexQuery {
+	return storage.IndexQuery{Min: v, Max: v, HasMin: true, HasMax: true}
+}
+
+func TestIndex_MaintainedOnWrites(t *testing.T) {
+	loadTmpDB(t)
+
+	_ = storage.PutKeyValue("user:1", []byte(`{"email":"ada@example.com","age":36}`))
+	_ = storage.PutKeyValue("user:2", []byte(`{"email":"bob@example.com","age":25}`))
+	_ = storage.PutKeyValue("order:1", []byte(`{"email":"ada@example.com"}`))
+
+	if err := storage.CreateIndex(storage.IndexDefinition{Name: "by_email", Pattern: "user:*", Path: "$.email"}); err != nil {
+		t.Fatalf("CreateIndex: %v", err)
+	}
+	if err := storage.CreateIndex(storage.IndexDefinition{Name: "by_email", Pattern: "x", Path: "$.y"}); !errors.Is(err, storage.ErrIndexExists) {
+		t.Fatalf("expected ErrIndexExists, got %v", err)
+	}
+
+	matches, _ := storage.QueryIndex("by_email", eq("ada@example.com"))
+	if len(matches) != 1 || matches[0].Key != "user:1" || string(matches[0].Value) != `{"email":"ada@example.com","age":36}` {
+		t.Fatalf("backfilled query = %+v", matches)
+	}
+
+	_ = storage.PutKeyValue("user:3", []byte(`{"email":"ada@example.com"}`))
+	_ = storage.PutKeyValue("user:1", []byte(`{"email":"ada@lovelace.dev"}`))
+	if got := queryKeys(t, "by_email", eq("ada@example.com")); !reflect.DeepEqual(got, []string{"user:3"}) {
+		t.Fatalf("after update = %v", got)
+	}
+
+	storage.DeleteByKey("user:3")
+	_ = storage.PutKeyValue("user:2", []byte("not json"))
+	if got := queryKeys(t, "by_email", storage.IndexQuery{}); !reflect.DeepEqual(got, []string{"user:1"}) {
+		t.Fatalf("after delete = %v", got)
+	}
+
+	if _, err := storage.QueryIndex("missing", eq("x")); !errors.Is(err, storage.ErrNoSuchIndex) {
+		t.Fatalf("expected ErrNoSuchIndex, got %v", err)
+	}
+}
+
+func TestIndex_RangeQueriesAndDocuments(t *testing.T) {
+	loadTmpDB(t)
+
+	_ = storage.CreateIndex(storage.IndexDefinition{Name: "by_age", Pattern: "user:*", Path: "$.age"})
+
+	_ = storage.PutKeyValue("user:a", []byte(`{"age":36}`))
+	_ = storage.PutKeyValue("user:b", []byte(`{"age":25}`))
+	_ = storage.PutKeyValue("user:c", []byte(`{"age":"unknown"}`))
+	_ = storage.SetDocument("user:d", []byte(`{"age":40}`))
+
+	if got := queryKeys(t, "by_age", storage.IndexQuery{Min: "30", HasMin: true}); !reflect.DeepEqual(got, []string{"user:a", "user:d"}) {
+		t.Fatalf("age >= 30 = %v", got)
+	}
+	if got := queryKeys(t, "by_age", storage.IndexQuery{Max: "36", HasMax: true, MaxExclusive: true}); !reflect.DeepEqual(got, []string{"user:b"}) {
+		t.Fatalf("age < 36 = %v", got)
+	}
+	if got := queryKeys(t, "by_age", eq(`"unknown"`)); !reflect.DeepEqual(got, []string{"user:c"}) {
+		t.Fatalf("age = \"unknown\" = %v", got)
+	}
+	if got := queryKeys(t, "by_age", storage.IndexQuery{Limit: 2}); !reflect.DeepEqual(got, []string{"user:b", "user:a"}) {
+		t.Fatalf("limit = %v", got)
+	}
+
+	_ = storage.SetDocumentPath("user:d", "$.age", []byte(`20`))
+	if got := queryKeys(t, "by_age", storage.IndexQuery{Max: "30", HasMax: true}); !reflect.DeepEqual(got, []string{"user:d", "user:b"}) {
+		t.Fatalf("after document update = %v", got)
+	}
+
+	storage.ResetStore()
+	if got := queryKeys(t, "by_age", storage.IndexQuery{}); len(got) != 0 {
+		t.Fatalf("after reset = %v", got)
+	}
+}
+
+func TestIndex_StringRangesAndRewrites(t *testing.T) {
+	loadTmpDB(t)
+
+	_ = storage.CreateIndex(storage.IndexDefinition{Name: "by_name", Pattern: "user:*", Path: "$.name"})
+
+	for k, name := range map[string]string{"user:1": "ab", "user:2": "a", "user:3": "b", "user:4": "ab", "user:5": `a\u0000`} {
+		_ = storage.PutKeyValue(k, []byte(`{"name":"`+name+`"}`))
+	}
+	_ = storage.PutKeyValue("user:6", []byte(`{"name":7}`))
+
+	if got := queryKeys(t, "by_name", storage.IndexQuery{}); !reflect.DeepEqual(got, []string{"user:6", "user:2", "user:5", "user:1", "user:4", "user:3"}) {
+		t.Fatalf("full scan = %v", got)
+	}
+	q := storage.IndexQuery{Min: `"a"`, HasMin: true, MinExclusive: true, Max: `"b"`, HasMax: true, MaxExclusive: true}
+	if got := queryKeys(t, "by_name", q); !reflect.DeepEqual(got, []string{"user:5", "user:1", "user:4"}) {
+		t.Fatalf("(a, b) = %v", got)
+	}
+
+	_ = storage.PutKeyValue("user:1", []byte(`{"name":"c"}`))
+	if got := queryKeys(t, "by_name", eq(`"ab"`)); !reflect.DeepEqual(got, []string{"user:4"}) {
+		t.Fatalf("after rewrite = %v", got)
+	}
+}
+
+func TestIndex_QueryValueTypes(t *testing.T) {
+	loadTmpDB(t)
+
+	_ = storage.CreateIndex(storage.IndexDefinition{Name: "by_code", Pattern: "item:*", Path: "$.code"})
+	_ = storage.PutKeyValue("item:1", []byte(`{"code":"123"}`))
+	_ = storage.PutKeyValue("item:2", []byte(`{"code":123}`))
+
+	if got := queryKeys(t, "by_code", eq("123")); !reflect.DeepEqual(got, []string{"item:2"}) {
+		t.Fatalf("eq=123 = %v", got)
+	}
+	if got := queryKeys(t, "by_code", eq(`"123"`)); !reflect.DeepEqual(got, []string{"item:1"}) {
+		t.Fatalf(`eq="123" = %v`, got)
+	}
+
+	q := eq("123")
+	q.Type = storage.IndexString
+	if got := queryKeys(t, "by_code", q); !reflect.DeepEqual(got, []string{"item:1"}) {
+		t.Fatalf("eq=123 type=string = %v", got)
+	}
+	q.Type = storage.IndexNumber
+	if got := queryKeys(t, "by_code", q); !reflect.DeepEqual(got, []string{"item:2"}) {
+		t.Fatalf("eq=123 type=number = %v", got)
+	}
+
+	q = eq("abc")
+	q.Type = storage.IndexNumber
+	if _, err := storage.QueryIndex("by_code", q); !errors.Is(err, storage.ErrInvalidIndexQuery) {
+		t.Fatalf("non-numeric number bound: expected ErrInvalidIndexQuery, got %v", err)
+	}
+	q.Type = "date"
+	if _, err := storage.QueryIndex("by_code", q); !errors.Is(err, storage.ErrInvalidIndexQuery) {
+		t.Fatalf("unknown type: expected ErrInvalidIndexQuery, got %v", err)
+	}
+}
+
+func TestIndex_LimitSkipsExpiredKeys(t *testing.T) {
+	loadTmpDB(t)
+
+	_ = storage.CreateIndex(storage.IndexDefinition{Name: "by_age", Pattern: "user:*", Path: "$.age"})
+
+	_ = storage.PutKeyValueWithTTLDuration("user:a", []byte(`{"age":10}`), 20*time.Millisecond)
+	_ = storage.PutKeyValue("user:b", []byte(`{"age":20}`))
+	time.Sleep(40 * time.Millisecond)
+
+	if got := queryKeys(t, "by_age", storage.IndexQuery{Limit: 1}); !reflect.DeepEqual(got, []string{"user:b"}) {
+		t.Fatalf("limit 1 after the first key expired = %v", got)
+	}
+}
+
+func TestIndex_RebuiltAtBoot(t *testing.T) {
+	dir := loadTmpDB(t)
+
+	_ = storage.CreateIndex(storage.IndexDefinition{Name: "by_city", Pattern: "user:*", Path: "$.address.city"})
+	_ = storage.PutKeyValue("user:1", []byte(`{"address":{"city":"Paris"}}`))
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+
+	globals.SetConfig(&configuration.Config{
+		Store: configuration.StoreConfig{Folder: dir, Shards: 4, Indexes: []string{"by_name user:* $.name"}},
+	})
+	storage.LoadDB()
+
+	defs := storage.Indexes()
+	if len(defs) != 2 || defs[0].Name != "by_city" || defs[1].Name != "by_name" {
+		t.Fatalf("Indexes() = %+v", defs)
+	}
+	if got := queryKeys(t, "by_city", eq("Paris")); !reflect.DeepEqual(got, []string{"user:1"}) {
+		t.Fatalf("rebuilt index = %v", got)
+	}
+
+	if !storage.DropIndex("by_city") || storage.DropIndex("by_city") {
+		t.Fatalf("DropIndex should succeed exactly once")
+	}
+}
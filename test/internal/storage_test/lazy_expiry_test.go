@@ -0,0 +1,111 @@
+package storage_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/stat"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestExpiredKeysAreAbsentFromEveryAccessor(t *testing.T) {
+	globals.SetConfig(&configuration.Config{
+		Store: configuration.StoreConfig{Folder: t.TempDir(), Shards: 4},
+		Stats: configuration.StatsConfig{Enabled: true},
+	})
+	stat.Init()
+	storage.LoadDB()
+
+	_ = storage.PutKeyValue("live:1", []byte("a"))
+	_ = storage.PutKeyValueWithTTLDuration("live:2", []byte("b"), 20*time.Millisecond)
+	_ = storage.PutKeyValueWithTTLDuration("live:3", []byte("c"), 20*time.Millisecond)
+
+	time.Sleep(40 * time.Millisecond)
+
+	if storage.Exists("live:2") {
+		t.Fatalf("Exists must report expired keys as absent")
+	}
+	if _, err := storage.GetByKey("live:3"); err == nil {
+		t.Fatalf("GetByKey must not return expired keys")
+	}
+	if got := storage.GetByWildcardKey("live:*"); len(got) != 1 || string(got["live:1"]) != "a" {
+		t.Fatalf("wildcard read returned expired keys: %v", got)
+	}
+
+	_ = storage.PutKeyValueWithTTLDuration("live:4", []byte("d"), 20*time.Millisecond)
+	time.Sleep(40 * time.Millisecond)
+
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+	snapshot, err := storage.ReadFromDB(storage.DataFile)
+	if err != nil {
+		t.Fatalf("ReadFromDB: %v", err)
+	}
+	if _, ok := snapshot["live:4"]; ok || len(snapshot) != 1 {
+		t.Fatalf("snapshot must only contain live keys, got %v", snapshot)
+	}
+
+	var counters struct {
+		KeysCount           uint64 `json:"keys_count,string"`
+		ExpirationKeysCount uint64 `json:"expiration_keys_count,string"`
+	}
+	if err := json.Unmarshal([]byte(stat.Stats.ToJson()), &counters); err != nil {
+		t.Fatalf("stats json: %v", err)
+	}
+	if counters.KeysCount != 1 || counters.ExpirationKeysCount != 0 {
+		t.Fatalf("counters include expired keys: %+v", counters)
+	}
+}
+
+func TestPlainSetReplacesALapsedDeadline(t *testing.T) {
+	loadTmpDB(t)
+
+	_ = storage.PutKeyValueWithTTLDuration("session", []byte("old"), 20*time.Millisecond)
+	time.Sleep(40 * time.Millisecond)
+
+	_ = storage.PutKeyValue("session", []byte("new"))
+
+	if v, err := storage.GetByKey("session"); err != nil || string(v) != "new" {
+		t.Fatalf("GetByKey after rewriting a lapsed key = %q, %v", v, err)
+	}
+	if _, hasExpiry, _ := storage.GetTTL("session"); hasExpiry {
+		t.Fatalf("a write without TTL must drop the previous deadline")
+	}
+}
+
+func TestConcurrentInsertsCountKeysOnce(t *testing.T) {
+	globals.SetConfig(&configuration.Config{
+		Store: configuration.StoreConfig{Folder: t.TempDir(), Shards: 4},
+		Stats: configuration.StatsConfig{Enabled: true},
+	})
+	stat.Init()
+	storage.LoadDB()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				_ = storage.PutKeyValue(fmt.Sprintf("k:%d", i), []byte("v"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	var counters struct {
+		KeysCount uint64 `json:"keys_count,string"`
+	}
+	if err := json.Unmarshal([]byte(stat.Stats.ToJson()), &counters); err != nil {
+		t.Fatalf("stats json: %v", err)
+	}
+	if counters.KeysCount != 200 {
+		t.Fatalf("keys_count = %d, expected 200", counters.KeysCount)
+	}
+}
@@ -0,0 +1,97 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestLease_SingleFlightOnMiss(t *testing.T) {
+	loadTmpDB(t)
+
+	first, err := storage.GetLease("hot", time.Minute, false, 0, nil)
+	if err != nil || first.Found || first.Token == "" {
+		t.Fatalf("first GetLease = %+v, %v", first, err)
+	}
+
+	second, _ := storage.GetLease("hot", time.Minute, false, 0, nil)
+	if second.Found || second.Token != "" {
+		t.Fatalf("a second caller must not get a lease: %+v", second)
+	}
+
+	if err := storage.PutKeyValueWithLease("hot", []byte("v"), storage.PutOptions{}, "bogus"); !errors.Is(err, storage.ErrInvalidLease) {
+		t.Fatalf("fill with wrong token: %v", err)
+	}
+
+	got := make(chan storage.LeaseResult, 1)
+	go func() {
+		res, _ := storage.GetLease("hot", time.Minute, true, 2*time.Second, nil)
+		got <- res
+	}()
+	waitBlocked(t, 1)
+
+	if err := storage.PutKeyValueWithLease("hot", []byte("computed"), storage.PutOptions{}, first.Token); err != nil {
+		t.Fatalf("fill: %v", err)
+	}
+
+	if res := <-got; !res.Found || string(res.Value) != "computed" {
+		t.Fatalf("waiter = %+v", res)
+	}
+	if err := storage.PutKeyValueWithLease("hot", []byte("again"), storage.PutOptions{}, first.Token); !errors.Is(err, storage.ErrInvalidLease) {
+		t.Fatalf("lease must be single use: %v", err)
+	}
+
+	if res, _ := storage.GetLease("hot", time.Minute, false, 0, nil); !res.Found || res.Token != "" {
+		t.Fatalf("hit = %+v", res)
+	}
+}
+
+func TestLease_ExpiryHandsTheLeaseToAWaiter(t *testing.T) {
+	loadTmpDB(t)
+
+	first, _ := storage.GetLease("hot", 30*time.Millisecond, false, 0, nil)
+
+	res, _ := storage.GetLease("hot", time.Minute, true, 2*time.Second, nil)
+	if res.Found || res.Token == "" || res.Token == first.Token {
+		t.Fatalf("waiter after lease expiry = %+v", res)
+	}
+
+	if err := storage.PutKeyValueWithLease("hot", []byte("v"), storage.PutOptions{}, first.Token); !errors.Is(err, storage.ErrInvalidLease) {
+		t.Fatalf("expired lease filled the key: %v", err)
+	}
+
+	if res, _ := storage.GetLease("other", time.Minute, true, 0, nil); res.Token == "" {
+		t.Fatalf("unrelated key = %+v", res)
+	}
+	if res, _ := storage.GetLease("hot", time.Minute, true, 20*time.Millisecond, nil); res.Found || res.Token != "" {
+		t.Fatalf("expected timeout, got %+v", res)
+	}
+}
+
+func TestLease_PlainWriteWakesWaiters(t *testing.T) {
+	loadTmpDB(t)
+
+	if first, _ := storage.GetLease("hot", time.Minute, false, 0, nil); first.Token == "" {
+		t.Fatalf("expected a lease, got %+v", first)
+	}
+
+	got := make(chan storage.LeaseResult, 1)
+	go func() {
+		res, _ := storage.GetLease("hot", time.Minute, true, 2*time.Second, nil)
+		got <- res
+	}()
+	waitBlocked(t, 1)
+
+	_ = storage.PutKeyValue("hot", []byte("written"))
+
+	select {
+	case res := <-got:
+		if !res.Found || string(res.Value) != "written" {
+			t.Fatalf("waiter = %+v", res)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatalf("a plain write did not wake the waiter")
+	}
+}
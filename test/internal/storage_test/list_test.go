@@ -0,0 +1,171 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func bytesList(values ...string) [][]byte {
+	out := make([][]byte, len(values))
+	for i, v := range values {
+		out[i] = []byte(v)
+	}
+	return out
+}
+
+func waitBlocked(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for storage.BlockedClients() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d blocked clients, got %d", n, storage.BlockedClients())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestList_PushPopRangeTrim(t *testing.T) {
+	loadTmpDB(t)
+
+	if n, err := storage.RPush("jobs", bytesList("b", "c")); err != nil || n != 2 {
+		t.Fatalf("RPush = %d, %v", n, err)
+	}
+	if n, err := storage.LPush("jobs", bytesList("a", "z")); err != nil || n != 4 {
+		t.Fatalf("LPush = %d, %v", n, err)
+	}
+
+	got, found, err := storage.LRange("jobs", 0, -1)
+	if err != nil || !found || len(got) != 4 || string(got[0]) != "z" || string(got[3]) != "c" {
+		t.Fatalf("LRange = %q, %v, %v", got, found, err)
+	}
+
+	if err := storage.LTrim("jobs", 1, -1); err != nil {
+		t.Fatalf("LTrim: %v", err)
+	}
+	if got, _, _ := storage.LRange("jobs", -2, 10); len(got) != 2 || string(got[0]) != "b" || string(got[1]) != "c" {
+		t.Fatalf("LRange after trim = %q", got)
+	}
+
+	if v, _ := storage.LPop("jobs", 1); len(v) != 1 || string(v[0]) != "a" {
+		t.Fatalf("LPop = %q", v)
+	}
+	if v, _ := storage.RPop("jobs", 5); len(v) != 2 || string(v[0]) != "c" || string(v[1]) != "b" {
+		t.Fatalf("RPop = %q", v)
+	}
+	if storage.Exists("jobs") {
+		t.Fatalf("empty list should be removed")
+	}
+	if n, _ := storage.LLen("jobs"); n != 0 {
+		t.Fatalf("LLen = %d", n)
+	}
+
+	_ = storage.PutKeyValue("plain", []byte("v"))
+	if _, err := storage.LPush("plain", bytesList("x")); !errors.Is(err, storage.ErrWrongType) {
+		t.Fatalf("expected WRONGTYPE, got %v", err)
+	}
+}
+
+func TestList_ManyPushesFromBothEnds(t *testing.T) {
+	loadTmpDB(t)
+
+	for i := 0; i < 5000; i++ {
+		_, _ = storage.LPush("q", bytesList("x"))
+		_, _ = storage.RPush("q", bytesList("y"))
+	}
+	for i := 0; i < 4000; i++ {
+		_, _ = storage.LPop("q", 1)
+	}
+	if n, _ := storage.LLen("q"); n != 6000 {
+		t.Fatalf("LLen = %d", n)
+	}
+	if v, _ := storage.LPop("q", 1); string(v[0]) != "x" {
+		t.Fatalf("head = %q", v)
+	}
+	if v, _ := storage.RPop("q", 1); string(v[0]) != "y" {
+		t.Fatalf("tail = %q", v)
+	}
+}
+
+func TestList_BlockingPopServesWaitersInArrivalOrder(t *testing.T) {
+	loadTmpDB(t)
+
+	results := make([]chan string, 3)
+	for i := range results {
+		results[i] = make(chan string, 1)
+		go func(ch chan string) {
+			_, v, ok, err := storage.BLPop([]string{"jobs"}, 2*time.Second, nil)
+			if err != nil || !ok {
+				ch <- "timeout"
+				return
+			}
+			ch <- string(v)
+		}(results[i])
+		waitBlocked(t, i+1)
+	}
+
+	if n, err := storage.RPush("jobs", bytesList("1", "2")); err != nil || n != 2 {
+		t.Fatalf("RPush = %d, %v", n, err)
+	}
+	if got := <-results[0]; got != "1" {
+		t.Fatalf("first waiter got %q", got)
+	}
+	if got := <-results[1]; got != "2" {
+		t.Fatalf("second waiter got %q", got)
+	}
+	if storage.Exists("jobs") {
+		t.Fatalf("pushed elements should have been handed to waiters")
+	}
+
+	_, _ = storage.RPush("jobs", bytesList("3"))
+	if got := <-results[2]; got != "3" {
+		t.Fatalf("third waiter got %q", got)
+	}
+}
+
+func TestList_BlockingPopAcrossKeysAndTimeout(t *testing.T) {
+	loadTmpDB(t)
+
+	_, _ = storage.RPush("b", bytesList("ready"))
+	key, v, ok, err := storage.BRPop([]string{"a", "b"}, time.Second, nil)
+	if err != nil || !ok || key != "b" || string(v) != "ready" {
+		t.Fatalf("BRPop = %s %q %v %v", key, v, ok, err)
+	}
+
+	start := time.Now()
+	if _, _, ok, err := storage.BLPop([]string{"a", "b"}, 50*time.Millisecond, nil); ok || err != nil {
+		t.Fatalf("expected timeout, got ok=%v err=%v", ok, err)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Fatalf("BLPop returned before its timeout")
+	}
+	if storage.BlockedClients() != 0 {
+		t.Fatalf("timed out waiter should be unregistered")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(done)
+	}()
+	if _, _, ok, _ := storage.BLPop([]string{"a"}, 0, done); ok {
+		t.Fatalf("cancelled BLPop should not pop")
+	}
+}
+
+func TestList_PersistsAcrossReload(t *testing.T) {
+	loadTmpDB(t)
+
+	_, _ = storage.RPush("jobs", bytesList("a", "b"))
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+
+	storage.LoadDB()
+
+	if got, found, _ := storage.LRange("jobs", 0, -1); !found || len(got) != 2 || string(got[1]) != "b" {
+		t.Fatalf("reloaded list = %q", got)
+	}
+}
@@ -0,0 +1,149 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestLock_AcquireRenewRelease(t *testing.T) {
+	loadTmpDB(t)
+
+	first, ok, err := storage.AcquireLock("jobs", time.Minute, false, 0, nil)
+	if err != nil || !ok || first.Owner == "" || first.Fence == 0 {
+		t.Fatalf("AcquireLock = %+v, %v, %v", first, ok, err)
+	}
+	if _, ok, _ := storage.AcquireLock("jobs", time.Minute, false, 0, nil); ok {
+		t.Fatalf("a held lock must not be acquired twice")
+	}
+
+	if _, err := storage.RenewLock("jobs", "intruder", time.Minute); !errors.Is(err, storage.ErrNotLockOwner) {
+		t.Fatalf("renew by non-owner: %v", err)
+	}
+	if renewed, err := storage.RenewLock("jobs", first.Owner, time.Minute); err != nil || renewed.Fence != first.Fence {
+		t.Fatalf("RenewLock = %+v, %v", renewed, err)
+	}
+
+	if err := storage.ReleaseLock("jobs", "intruder"); !errors.Is(err, storage.ErrNotLockOwner) {
+		t.Fatalf("release by non-owner: %v", err)
+	}
+	if err := storage.ReleaseLock("jobs", first.Owner); err != nil {
+		t.Fatalf("ReleaseLock: %v", err)
+	}
+	if err := storage.ReleaseLock("jobs", first.Owner); !errors.Is(err, storage.ErrNotLockOwner) {
+		t.Fatalf("double release: %v", err)
+	}
+
+	second, ok, _ := storage.AcquireLock("jobs", time.Minute, false, 0, nil)
+	if !ok || second.Fence <= first.Fence || second.Owner == first.Owner {
+		t.Fatalf("reacquire = %+v after %+v", second, first)
+	}
+
+	_ = storage.PutKeyValue("plain", []byte("v"))
+	if _, _, err := storage.AcquireLock("plain", time.Minute, false, 0, nil); !errors.Is(err, storage.ErrWrongType) {
+		t.Fatalf("expected WRONGTYPE, got %v", err)
+	}
+}
+
+func TestLock_ExpiryFreesLock(t *testing.T) {
+	loadTmpDB(t)
+
+	first, _, _ := storage.AcquireLock("jobs", 20*time.Millisecond, false, 0, nil)
+	time.Sleep(40 * time.Millisecond)
+
+	if _, err := storage.RenewLock("jobs", first.Owner, time.Minute); !errors.Is(err, storage.ErrNotLockOwner) {
+		t.Fatalf("renewing an expired lock: %v", err)
+	}
+
+	second, ok, _ := storage.AcquireLock("jobs", time.Minute, false, 0, nil)
+	if !ok || second.Fence <= first.Fence {
+		t.Fatalf("acquire after expiry = %+v, %v", second, ok)
+	}
+}
+
+func TestLock_LateRenewCannotShortenNewHolder(t *testing.T) {
+	loadTmpDB(t)
+
+	for i := 0; i < 200; i++ {
+		old, _, _ := storage.AcquireLock("jobs", time.Microsecond, false, 0, nil)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for {
+				if _, err := storage.RenewLock("jobs", old.Owner, time.Microsecond); err != nil {
+					return
+				}
+			}
+		}()
+
+		var holder storage.Lock
+		for ok := false; !ok; {
+			holder, ok, _ = storage.AcquireLock("jobs", time.Minute, false, 0, nil)
+		}
+		<-done
+
+		if ttl, _, exists := storage.GetTTL("jobs"); !exists || ttl < 30*time.Second {
+			t.Fatalf("iteration %d: new holder's TTL = %v (exists %v)", i, ttl, exists)
+		}
+		_ = storage.ReleaseLock("jobs", holder.Owner)
+	}
+}
+
+func TestLock_FencingSurvivesRestart(t *testing.T) {
+	loadTmpDB(t)
+
+	before, _, _ := storage.AcquireLock("jobs", time.Minute, false, 0, nil)
+
+	// Reload without saving: tokens must still move forward.
+	storage.LoadDB()
+
+	after, ok, _ := storage.AcquireLock("other", time.Minute, false, 0, nil)
+	if !ok || after.Fence <= before.Fence {
+		t.Fatalf("fence went backwards across restart: %d then %d", before.Fence, after.Fence)
+	}
+}
+
+func TestLock_BlockingAcquire(t *testing.T) {
+	loadTmpDB(t)
+
+	held, _, _ := storage.AcquireLock("jobs", time.Minute, false, 0, nil)
+
+	type result struct {
+		lock storage.Lock
+		ok   bool
+	}
+	got := make(chan result, 1)
+	go func() {
+		l, ok, _ := storage.AcquireLock("jobs", time.Minute, true, 2*time.Second, nil)
+		got <- result{l, ok}
+	}()
+	waitBlocked(t, 1)
+
+	_ = storage.ReleaseLock("jobs", held.Owner)
+
+	r := <-got
+	if !r.ok || r.lock.Fence <= held.Fence {
+		t.Fatalf("blocked acquire = %+v", r)
+	}
+
+	go func() {
+		l, ok, _ := storage.AcquireLock("jobs", time.Minute, true, 2*time.Second, nil)
+		got <- result{l, ok}
+	}()
+	waitBlocked(t, 1)
+
+	_ = storage.Expire("jobs", time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	storage.CleanAllPastKeys()
+
+	if r := <-got; !r.ok {
+		t.Fatalf("acquire after expiry = %+v", r)
+	}
+
+	if _, ok, err := storage.AcquireLock("jobs", time.Minute, true, 20*time.Millisecond, nil); ok || err != nil {
+		t.Fatalf("expected timeout, got %v %v", ok, err)
+	}
+}
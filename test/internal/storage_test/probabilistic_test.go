@@ -0,0 +1,91 @@
+package storage_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/taymour/elysiandb/internal/bloom"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestHLL_CountAndMerge(t *testing.T) {
+	loadTmpDB(t)
+
+	visitors := make([]string, 500)
+	for i := range visitors {
+		visitors[i] = "v" + strconv.Itoa(i)
+	}
+
+	if changed, err := storage.PFAdd("day:1", visitors[:300]); err != nil || !changed {
+		t.Fatalf("PFAdd = %v, %v", changed, err)
+	}
+	if changed, _ := storage.PFAdd("day:1", visitors[:10]); changed {
+		t.Fatalf("re-adding known elements should not change the sketch")
+	}
+	_, _ = storage.PFAdd("day:2", visitors[200:])
+
+	if n, _ := storage.PFCount([]string{"day:1"}); n < 290 || n > 310 {
+		t.Fatalf("PFCount(day:1) = %d", n)
+	}
+	if n, _ := storage.PFCount([]string{"day:1", "day:2", "missing"}); n < 485 || n > 515 {
+		t.Fatalf("PFCount(union) = %d", n)
+	}
+
+	if err := storage.PFMerge("week", []string{"day:1", "day:2"}); err != nil {
+		t.Fatalf("PFMerge: %v", err)
+	}
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+	storage.LoadDB()
+
+	if n, _ := storage.PFCount([]string{"week"}); n < 485 || n > 515 {
+		t.Fatalf("PFCount(week) after reload = %d", n)
+	}
+
+	_ = storage.PutKeyValue("plain", []byte("v"))
+	if _, err := storage.PFCount([]string{"week", "plain"}); !errors.Is(err, storage.ErrWrongType) {
+		t.Fatalf("expected WRONGTYPE, got %v", err)
+	}
+}
+
+func TestBloom_ReserveAddExists(t *testing.T) {
+	loadTmpDB(t)
+
+	if err := storage.BFReserve("seen", 0.001, 1000); err != nil {
+		t.Fatalf("BFReserve: %v", err)
+	}
+	if err := storage.BFReserve("seen", 0.01, 10); !errors.Is(err, storage.ErrKeyExists) {
+		t.Fatalf("expected ErrKeyExists, got %v", err)
+	}
+	if err := storage.BFReserve("bad", 2, 10); !errors.Is(err, bloom.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+
+	if added, _ := storage.BFAdd("seen", "evt-1"); !added {
+		t.Fatalf("first BFAdd should report a new item")
+	}
+	if added, _ := storage.BFAdd("seen", "evt-1"); added {
+		t.Fatalf("second BFAdd should report a known item")
+	}
+
+	if added, err := storage.BFAdd("auto", "x"); err != nil || !added {
+		t.Fatalf("BFAdd on a missing key should create a default filter: %v, %v", added, err)
+	}
+
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+	storage.LoadDB()
+
+	if ok, _ := storage.BFExists("seen", "evt-1"); !ok {
+		t.Fatalf("evt-1 should exist after reload")
+	}
+	if ok, _ := storage.BFExists("seen", "evt-2"); ok {
+		t.Fatalf("evt-2 should not exist")
+	}
+	if ok, err := storage.BFExists("missing", "x"); ok || err != nil {
+		t.Fatalf("BFExists on a missing key = %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,158 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestQueue_DelayedJobsBecomeReady(t *testing.T) {
+	loadTmpDB(t)
+
+	now := time.Now()
+	later, _ := storage.Enqueue("mail", []byte("reminder"), now.Add(40*time.Millisecond), 0)
+	first, _ := storage.Enqueue("mail", []byte("welcome"), now, 0)
+	second, _ := storage.Enqueue("mail", []byte("receipt"), now, 0)
+
+	if stats, _ := storage.QueueInfo("mail"); stats.Ready != 2 || stats.Delayed != 1 {
+		t.Fatalf("QueueInfo = %+v", stats)
+	}
+
+	for _, want := range []string{first, second} {
+		job, ok, err := storage.Reserve("mail", time.Minute)
+		if err != nil || !ok || job.ID != want || job.Attempts != 1 {
+			t.Fatalf("Reserve = %+v, %v, %v; want %s", job, ok, err, want)
+		}
+	}
+	if _, ok, _ := storage.Reserve("mail", time.Minute); ok {
+		t.Fatalf("delayed job reserved before its run-at time")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	job, ok, _ := storage.Reserve("mail", time.Minute)
+	if !ok || job.ID != later || string(job.Payload) != "reminder" {
+		t.Fatalf("delayed Reserve = %+v, %v", job, ok)
+	}
+
+	for _, id := range []string{first, second, later} {
+		if err := storage.Ack("mail", id); err != nil {
+			t.Fatalf("Ack(%s): %v", id, err)
+		}
+	}
+	if err := storage.Ack("mail", first); !errors.Is(err, storage.ErrNoSuchJob) {
+		t.Fatalf("double ack: %v", err)
+	}
+	if stats, _ := storage.QueueInfo("mail"); stats != (storage.QueueStats{}) {
+		t.Fatalf("queue not empty after acks: %+v", stats)
+	}
+}
+
+func TestQueue_VisibilityTimeoutAndTouch(t *testing.T) {
+	loadTmpDB(t)
+
+	id, _ := storage.Enqueue("jobs", []byte("resize"), time.Now(), 0)
+
+	_, _, _ = storage.Reserve("jobs", 30*time.Millisecond)
+	if err := storage.Touch("jobs", id, 200*time.Millisecond); err != nil {
+		t.Fatalf("Touch: %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if _, ok, _ := storage.Reserve("jobs", time.Minute); ok {
+		t.Fatalf("touched job was redelivered early")
+	}
+	if stats, _ := storage.QueueInfo("jobs"); stats.Reserved != 1 {
+		t.Fatalf("QueueInfo = %+v", stats)
+	}
+
+	if err := storage.Touch("jobs", id, 20*time.Millisecond); err != nil {
+		t.Fatalf("Touch: %v", err)
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	if err := storage.Ack("jobs", id); !errors.Is(err, storage.ErrNoSuchJob) {
+		t.Fatalf("ack after the visibility timeout lapsed: %v", err)
+	}
+
+	job, ok, _ := storage.Reserve("jobs", time.Minute)
+	if !ok || job.ID != id || job.Attempts != 2 {
+		t.Fatalf("redelivery = %+v, %v", job, ok)
+	}
+}
+
+func TestQueue_NackAndDeadLetter(t *testing.T) {
+	loadTmpDB(t)
+
+	id, _ := storage.Enqueue("jobs", []byte("flaky"), time.Now(), 2)
+
+	_, _, _ = storage.Reserve("jobs", time.Minute)
+	if err := storage.Nack("jobs", id, 20*time.Millisecond); err != nil {
+		t.Fatalf("Nack: %v", err)
+	}
+	if _, ok, _ := storage.Reserve("jobs", time.Minute); ok {
+		t.Fatalf("nacked job redelivered before its delay")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	job, ok, _ := storage.Reserve("jobs", time.Minute)
+	if !ok || job.Attempts != 2 {
+		t.Fatalf("second delivery = %+v, %v", job, ok)
+	}
+	if err := storage.Nack("jobs", id, 0); err != nil {
+		t.Fatalf("Nack: %v", err)
+	}
+
+	dead, _ := storage.DeadJobs("jobs")
+	if len(dead) != 1 || dead[0].ID != id || dead[0].Attempts != 2 {
+		t.Fatalf("DeadJobs = %+v", dead)
+	}
+	if stats, _ := storage.QueueInfo("jobs"); stats != (storage.QueueStats{Dead: 1}) {
+		t.Fatalf("QueueInfo = %+v", stats)
+	}
+
+	id, _ = storage.Enqueue("jobs", []byte("slow"), time.Now(), 1)
+	_, _, _ = storage.Reserve("jobs", 20*time.Millisecond)
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok, _ := storage.Reserve("jobs", time.Minute); ok {
+		t.Fatalf("job past its attempts must not be redelivered")
+	}
+	if dead, _ := storage.DeadJobs("jobs"); len(dead) != 2 || dead[1].ID != id {
+		t.Fatalf("DeadJobs after lapsed visibility = %+v", dead)
+	}
+}
+
+func TestQueue_SurvivesSnapshot(t *testing.T) {
+	loadTmpDB(t)
+
+	reserved, _ := storage.Enqueue("jobs", []byte("a"), time.Now(), 0)
+	_, _ = storage.Enqueue("jobs", []byte("b"), time.Now(), 0)
+	_, _ = storage.Enqueue("jobs", []byte("c"), time.Now().Add(time.Hour), 0)
+	_, _, _ = storage.Reserve("jobs", time.Minute)
+
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+	storage.LoadDB()
+
+	if stats, _ := storage.QueueInfo("jobs"); stats != (storage.QueueStats{Ready: 1, Delayed: 1, Reserved: 1}) {
+		t.Fatalf("QueueInfo after reload = %+v", stats)
+	}
+	if err := storage.Ack("jobs", reserved); err != nil {
+		t.Fatalf("Ack after reload: %v", err)
+	}
+
+	id, _ := storage.Enqueue("jobs", []byte("d"), time.Now(), 0)
+	if id != "4" {
+		t.Fatalf("job IDs must keep counting after reload, got %s", id)
+	}
+
+	_ = storage.PutKeyValue("plain", []byte("v"))
+	if _, err := storage.Enqueue("plain", []byte("x"), time.Now(), 0); !errors.Is(err, storage.ErrWrongType) {
+		t.Fatalf("expected WRONGTYPE, got %v", err)
+	}
+}
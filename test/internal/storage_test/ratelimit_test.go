@@ -0,0 +1,141 @@
+package storage_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestRateLimit_TokenBucket(t *testing.T) {
+	loadTmpDB(t)
+
+	for i := 2; i >= 0; i-- {
+		res, err := storage.RateLimit("api:ada", 3, 10, 1)
+		if err != nil || !res.Allowed || res.Remaining != int64(i) {
+			t.Fatalf("request %d = %+v, %v", 3-i, res, err)
+		}
+	}
+
+	res, _ := storage.RateLimit("api:ada", 3, 10, 1)
+	if res.Allowed || res.RetryAfter <= 0 || res.RetryAfter > 100*time.Millisecond {
+		t.Fatalf("empty bucket = %+v", res)
+	}
+
+	time.Sleep(120 * time.Millisecond)
+
+	if res, _ := storage.RateLimit("api:ada", 3, 10, 1); !res.Allowed {
+		t.Fatalf("bucket did not refill: %+v", res)
+	}
+	if res, _ := storage.RateLimit("api:ada", 3, 10, 3); res.Allowed {
+		t.Fatalf("cost larger than the remaining tokens must be denied: %+v", res)
+	}
+
+	if _, err := storage.RateLimit("api:ada", 3, 10, 4); !errors.Is(err, storage.ErrInvalidRateLimit) {
+		t.Fatalf("cost above capacity: %v", err)
+	}
+	if _, err := storage.RateLimit("api:ada", 3, 0, 1); !errors.Is(err, storage.ErrInvalidRateLimit) {
+		t.Fatalf("zero refill: %v", err)
+	}
+
+	_ = storage.PutKeyValue("plain", []byte("v"))
+	if _, err := storage.RateLimit("plain", 3, 10, 1); !errors.Is(err, storage.ErrWrongType) {
+		t.Fatalf("expected WRONGTYPE, got %v", err)
+	}
+}
+
+func TestRateLimit_IdleBucketsExpire(t *testing.T) {
+	loadTmpDB(t)
+
+	_, _ = storage.RateLimit("api:bob", 2, 100, 2)
+
+	ttl, hasExpiry, exists := storage.GetTTL("api:bob")
+	if !exists || !hasExpiry || ttl > 20*time.Millisecond {
+		t.Fatalf("bucket TTL = %v %v %v", ttl, hasExpiry, exists)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	if storage.Exists("api:bob") {
+		t.Fatalf("refilled bucket should have expired")
+	}
+}
+
+func TestRateLimit_ConcurrentCallersShareTheBucket(t *testing.T) {
+	loadTmpDB(t)
+
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if res, _ := storage.RateLimit("api:shared", 10, 0.001, 1); res.Allowed {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed.Load() != 10 {
+		t.Fatalf("expected exactly 10 allowed requests, got %d", allowed.Load())
+	}
+}
+
+func TestRateLimit_ConcurrentCallersNeverShortenTheExpiry(t *testing.T) {
+	loadTmpDB(t)
+
+	// Each token takes 1000s to refill, so every call pushes the idle
+	// deadline 1000s further out.
+	const calls = 400
+	deadline := func() time.Time {
+		ttl, _, _ := storage.GetTTL("api:busy")
+		return time.Now().Add(ttl)
+	}
+
+	stop := make(chan struct{})
+	backwards := make(chan time.Duration, 1)
+	go func() {
+		var last time.Time
+		for {
+			select {
+			case <-stop:
+				close(backwards)
+				return
+			default:
+			}
+			d := deadline()
+			if !last.IsZero() && last.Sub(d) > 500*time.Second {
+				backwards <- last.Sub(d)
+				close(backwards)
+				return
+			}
+			if d.After(last) {
+				last = d
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < calls/8; i++ {
+				_, _ = storage.RateLimit("api:busy", 1000, 0.001, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	close(stop)
+
+	if d, ok := <-backwards; ok {
+		t.Fatalf("bucket expiry moved backwards by %v", d)
+	}
+	if ttl, _, _ := storage.GetTTL("api:busy"); ttl < (calls-1)*1000*time.Second {
+		t.Fatalf("final bucket TTL = %v, expected about %v", ttl, calls*1000*time.Second)
+	}
+}
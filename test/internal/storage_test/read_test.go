@@ -1,9 +1,11 @@
 package storage_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/taymour/elysiandb/internal/configuration"
@@ -153,3 +155,33 @@ func TestReadExpirationsFromDB_UnparseableKey_Skipped(t *testing.T) {
 		t.Fatalf("expected key 1700000000 to be present, got %v", got)
 	}
 }
+
+func TestWriteToDB_ConcurrentFlushes(t *testing.T) {
+	loadTmpDB(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				_ = storage.PutKeyValue(fmt.Sprintf("k:%d:%d", w, i), []byte("v"))
+				if err := storage.WriteToDB(); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("concurrent WriteToDB: %v", err)
+	}
+
+	if data, err := storage.ReadFromDB(storage.DataFile); err != nil || len(data) != 400 {
+		t.Fatalf("snapshot has %d keys, %v", len(data), err)
+	}
+}
@@ -0,0 +1,116 @@
+package storage_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestSet_MembershipAndCombinations(t *testing.T) {
+	loadTmpDB(t)
+
+	if n, err := storage.SAdd("tags:a", []string{"go", "db", "go"}); err != nil || n != 2 {
+		t.Fatalf("SAdd = %d, %v", n, err)
+	}
+	_, _ = storage.SAdd("tags:b", []string{"db", "kv"})
+
+	if ok, _ := storage.SIsMember("tags:a", "go"); !ok {
+		t.Fatalf("expected go to be a member")
+	}
+
+	if got, _ := storage.SInter([]string{"tags:a", "tags:b"}); !reflect.DeepEqual(got, []string{"db"}) {
+		t.Fatalf("SInter = %v", got)
+	}
+	if got, _ := storage.SInter([]string{"tags:a", "missing"}); len(got) != 0 {
+		t.Fatalf("SInter with missing key = %v", got)
+	}
+	if got, _ := storage.SUnion([]string{"tags:a", "tags:b", "missing"}); !reflect.DeepEqual(got, []string{"db", "go", "kv"}) {
+		t.Fatalf("SUnion = %v", got)
+	}
+
+	if n, _ := storage.SRem("tags:b", []string{"db", "kv", "nope"}); n != 2 {
+		t.Fatalf("SRem = %d", n)
+	}
+	if storage.Exists("tags:b") {
+		t.Fatalf("empty set should be removed")
+	}
+
+	_ = storage.PutKeyValue("plain", []byte("v"))
+	if _, err := storage.SUnion([]string{"tags:a", "plain"}); !errors.Is(err, storage.ErrWrongType) {
+		t.Fatalf("expected WRONGTYPE, got %v", err)
+	}
+}
+
+func TestZSet_Leaderboard(t *testing.T) {
+	loadTmpDB(t)
+
+	added, err := storage.ZAdd("board", []storage.ScoredMember{
+		{Member: "ada", Score: 30},
+		{Member: "bob", Score: 10},
+		{Member: "cyd", Score: 20},
+	})
+	if err != nil || added != 3 {
+		t.Fatalf("ZAdd = %d, %v", added, err)
+	}
+
+	if s, err := storage.ZIncrBy("board", "bob", 25); err != nil || s != 35 {
+		t.Fatalf("ZIncrBy = %v, %v", s, err)
+	}
+
+	got, _, _ := storage.ZRange("board", 0, -1)
+	want := []storage.ScoredMember{{Member: "cyd", Score: 20}, {Member: "ada", Score: 30}, {Member: "bob", Score: 35}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ZRange = %v", got)
+	}
+
+	if rank, score, ok, _ := storage.ZRank("board", "ada"); !ok || rank != 1 || score != 30 {
+		t.Fatalf("ZRank = %d %v %v", rank, score, ok)
+	}
+
+	r := storage.ScoreRange{Min: 20, Max: math.Inf(1), MinExclusive: true}
+	if got, _, _ := storage.ZRangeByScore("board", r, 0, -1); len(got) != 2 || got[0].Member != "ada" {
+		t.Fatalf("ZRangeByScore = %v", got)
+	}
+
+	if _, err := storage.ZAdd("board", []storage.ScoredMember{{Member: "x", Score: math.NaN()}}); !errors.Is(err, storage.ErrNotFloat) {
+		t.Fatalf("expected ErrNotFloat, got %v", err)
+	}
+
+	if n, _ := storage.ZRem("board", []string{"ada", "nope"}); n != 1 {
+		t.Fatalf("ZRem = %d", n)
+	}
+	if _, _, ok, _ := storage.ZRank("board", "ada"); ok {
+		t.Fatalf("removed member should have no rank")
+	}
+}
+
+func TestSetAndZSet_PersistAndExpire(t *testing.T) {
+	loadTmpDB(t)
+
+	_, _ = storage.SAdd("s", []string{"a", "b"})
+	_, _ = storage.ZAdd("z", []storage.ScoredMember{{Member: "a", Score: 1.5}, {Member: "b", Score: -2}})
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+
+	storage.LoadDB()
+
+	if got, found, _ := storage.SMembers("s"); !found || !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("reloaded set = %v", got)
+	}
+	if got, _, _ := storage.ZRange("z", 0, -1); len(got) != 2 || got[0].Member != "b" || got[1].Score != 1.5 {
+		t.Fatalf("reloaded zset = %v", got)
+	}
+
+	if !storage.Expire("z", 20*time.Millisecond) {
+		t.Fatalf("Expire on zset failed")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, found, _ := storage.ZRange("z", 0, -1); found {
+		t.Fatalf("expired zset should be gone")
+	}
+}
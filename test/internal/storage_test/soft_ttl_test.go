@@ -0,0 +1,100 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestSoftTTL_ServesStaleUntilHardTTL(t *testing.T) {
+	loadTmpDB(t)
+
+	opts := storage.PutOptions{TTL: 80 * time.Millisecond, SoftTTL: 20 * time.Millisecond}
+	if err := storage.PutKeyValueWithOptions("page", []byte("v1"), opts); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	if v, stale, err := storage.GetByKeyWithStale("page"); err != nil || stale || string(v) != "v1" {
+		t.Fatalf("fresh read = %q %v %v", v, stale, err)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if v, stale, err := storage.GetByKeyWithStale("page"); err != nil || !stale || string(v) != "v1" {
+		t.Fatalf("stale read = %q %v %v", v, stale, err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, _, err := storage.GetByKeyWithStale("page"); err == nil {
+		t.Fatalf("value must be gone after the hard TTL")
+	}
+}
+
+func TestSoftTTL_ResetByWrites(t *testing.T) {
+	loadTmpDB(t)
+
+	_ = storage.PutKeyValueWithOptions("page", []byte("v1"), storage.PutOptions{SoftTTL: time.Millisecond})
+	time.Sleep(5 * time.Millisecond)
+	if _, stale, _ := storage.GetByKeyWithStale("page"); !stale {
+		t.Fatalf("expected a stale value")
+	}
+
+	_ = storage.PutKeyValue("page", []byte("v2"))
+	if v, stale, _ := storage.GetByKeyWithStale("page"); stale || string(v) != "v2" {
+		t.Fatalf("a plain write must clear the soft TTL: %q %v", v, stale)
+	}
+
+	_ = storage.PutKeyValueWithOptions("page", []byte("v3"), storage.PutOptions{SoftTTL: time.Millisecond})
+	storage.DeleteByKey("page")
+	_ = storage.PutKeyValue("page", []byte("v4"))
+	time.Sleep(5 * time.Millisecond)
+	if _, stale, _ := storage.GetByKeyWithStale("page"); stale {
+		t.Fatalf("deleted key kept its soft TTL")
+	}
+}
+
+func TestSoftTTL_StaleValueHandsOutOneLease(t *testing.T) {
+	loadTmpDB(t)
+
+	_ = storage.PutKeyValueWithOptions("hot", []byte("old"), storage.PutOptions{SoftTTL: time.Millisecond})
+	time.Sleep(5 * time.Millisecond)
+
+	first, err := storage.GetLease("hot", time.Minute, false, 0, nil)
+	if err != nil || !first.Found || !first.Stale || first.Token == "" || string(first.Value) != "old" {
+		t.Fatalf("first stale read = %+v, %v", first, err)
+	}
+
+	second, _ := storage.GetLease("hot", time.Minute, true, time.Second, nil)
+	if !second.Found || !second.Stale || second.Token != "" || string(second.Value) != "old" {
+		t.Fatalf("second stale read = %+v", second)
+	}
+
+	opts := storage.PutOptions{SoftTTL: time.Minute}
+	if err := storage.PutKeyValueWithLease("hot", []byte("new"), opts, first.Token); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	if res, _ := storage.GetLease("hot", time.Minute, false, 0, nil); !res.Found || res.Stale || res.Token != "" || string(res.Value) != "new" {
+		t.Fatalf("after refresh = %+v", res)
+	}
+}
+
+func TestSoftTTL_SurvivesRestart(t *testing.T) {
+	loadTmpDB(t)
+
+	_ = storage.PutKeyValueWithOptions("page", []byte("v"), storage.PutOptions{SoftTTL: time.Millisecond})
+	_ = storage.PutKeyValueWithOptions("fresh", []byte("v"), storage.PutOptions{SoftTTL: time.Hour})
+
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+	storage.LoadDB()
+	time.Sleep(5 * time.Millisecond)
+
+	if _, stale, _ := storage.GetByKeyWithStale("page"); !stale {
+		t.Fatalf("soft TTL lost on reload")
+	}
+	if _, stale, _ := storage.GetByKeyWithStale("fresh"); stale {
+		t.Fatalf("fresh key reported stale after reload")
+	}
+}
@@ -0,0 +1,139 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func event(field, value string) []storage.HashField {
+	return []storage.HashField{{Field: field, Value: []byte(value)}}
+}
+
+func TestStream_AddRangeAndTrim(t *testing.T) {
+	loadTmpDB(t)
+
+	first, err := storage.XAdd("events", "*", event("type", "created"), storage.StreamTrim{})
+	if err != nil {
+		t.Fatalf("XAdd: %v", err)
+	}
+	second, _ := storage.XAdd("events", "*", event("type", "updated"), storage.StreamTrim{})
+	if !first.Less(second) {
+		t.Fatalf("IDs must be monotonic: %s then %s", first, second)
+	}
+
+	if _, err := storage.XAdd("events", first.String(), event("x", "y"), storage.StreamTrim{}); !errors.Is(err, storage.ErrStreamIDTooSmall) {
+		t.Fatalf("expected ErrStreamIDTooSmall, got %v", err)
+	}
+
+	entries, found, err := storage.XRange("events", "-", "+", 0)
+	if err != nil || !found || len(entries) != 2 || string(entries[1].Fields[0].Value) != "updated" {
+		t.Fatalf("XRange = %v %v %v", entries, found, err)
+	}
+	if entries, _, _ := storage.XRange("events", "("+first.String(), "+", 0); len(entries) != 1 || entries[0].ID != second {
+		t.Fatalf("exclusive XRange = %v", entries)
+	}
+
+	for i := 0; i < 5; i++ {
+		_, _ = storage.XAdd("events", "*", event("n", "v"), storage.StreamTrim{MaxLen: 3, ByLength: true})
+	}
+	if n, _ := storage.XLen("events"); n != 3 {
+		t.Fatalf("XLen after MAXLEN = %d", n)
+	}
+
+	if _, err := storage.XAdd("old", "1000-0", event("a", "b"), storage.StreamTrim{}); err != nil {
+		t.Fatalf("XAdd explicit ID: %v", err)
+	}
+	_, _ = storage.XAdd("old", "*", event("a", "c"), storage.StreamTrim{})
+	if removed, _ := storage.XTrim("old", storage.StreamTrim{MaxAge: time.Hour}); removed != 1 {
+		t.Fatalf("XTrim MAXAGE removed %d", removed)
+	}
+}
+
+func TestStream_BlockingRead(t *testing.T) {
+	loadTmpDB(t)
+
+	got := make(chan []storage.StreamRead, 1)
+	go func() {
+		reads, _ := storage.XRead([]string{"a", "events"}, []string{"$", "$"}, 0, true, 2*time.Second, nil)
+		got <- reads
+	}()
+	waitBlocked(t, 1)
+
+	id, _ := storage.XAdd("events", "*", event("type", "created"), storage.StreamTrim{})
+
+	reads := <-got
+	if len(reads) != 1 || reads[0].Key != "events" || reads[0].Entries[0].ID != id {
+		t.Fatalf("XRead = %v", reads)
+	}
+
+	reads, err := storage.XRead([]string{"events"}, []string{id.String()}, 0, true, 20*time.Millisecond, nil)
+	if err != nil || len(reads) != 0 {
+		t.Fatalf("expected timeout, got %v %v", reads, err)
+	}
+}
+
+func TestStream_ConsumerGroups(t *testing.T) {
+	loadTmpDB(t)
+
+	if err := storage.XGroupCreate("jobs", "workers", "$", false); !errors.Is(err, storage.ErrNoSuchStream) {
+		t.Fatalf("expected ErrNoSuchStream, got %v", err)
+	}
+	if err := storage.XGroupCreate("jobs", "workers", "$", true); err != nil {
+		t.Fatalf("XGroupCreate: %v", err)
+	}
+	if err := storage.XGroupCreate("jobs", "workers", "$", true); !errors.Is(err, storage.ErrGroupExists) {
+		t.Fatalf("expected ErrGroupExists, got %v", err)
+	}
+
+	a, _ := storage.XAdd("jobs", "*", event("job", "a"), storage.StreamTrim{})
+	b, _ := storage.XAdd("jobs", "*", event("job", "b"), storage.StreamTrim{})
+
+	r1, err := storage.XReadGroup("workers", "w1", []string{"jobs"}, []string{">"}, 1, false, 0, nil)
+	if err != nil || len(r1) != 1 || r1[0].Entries[0].ID != a {
+		t.Fatalf("w1 read = %v %v", r1, err)
+	}
+	r2, _ := storage.XReadGroup("workers", "w2", []string{"jobs"}, []string{">"}, 0, false, 0, nil)
+	if len(r2) != 1 || len(r2[0].Entries) != 1 || r2[0].Entries[0].ID != b {
+		t.Fatalf("w2 read = %v", r2)
+	}
+
+	pending, _ := storage.XPending("jobs", "workers", "")
+	if len(pending) != 2 || pending[0].Consumer != "w1" || pending[1].Consumer != "w2" {
+		t.Fatalf("XPending = %v", pending)
+	}
+
+	history, _ := storage.XReadGroup("workers", "w1", []string{"jobs"}, []string{"0"}, 0, false, 0, nil)
+	if len(history) != 1 || len(history[0].Entries) != 1 || history[0].Entries[0].ID != a {
+		t.Fatalf("w1 history = %v", history)
+	}
+
+	claimed, _ := storage.XClaim("jobs", "workers", "w2", 0, []string{a.String()})
+	if len(claimed) != 1 {
+		t.Fatalf("XClaim = %v", claimed)
+	}
+	if pending, _ := storage.XPending("jobs", "workers", "w2"); len(pending) != 2 || pending[0].Deliveries != 2 {
+		t.Fatalf("pending after claim = %v", pending)
+	}
+
+	if n, _ := storage.XAck("jobs", "workers", []string{a.String(), b.String(), b.String()}); n != 2 {
+		t.Fatalf("XAck = %d", n)
+	}
+
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+	storage.LoadDB()
+
+	c, _ := storage.XAdd("jobs", "*", event("job", "c"), storage.StreamTrim{})
+	r3, _ := storage.XReadGroup("workers", "w1", []string{"jobs"}, []string{">"}, 0, false, 0, nil)
+	if len(r3) != 1 || len(r3[0].Entries) != 1 || r3[0].Entries[0].ID != c {
+		t.Fatalf("read after reload = %v", r3)
+	}
+
+	if _, err := storage.XReadGroup("nope", "w1", []string{"jobs"}, []string{">"}, 0, false, 0, nil); !errors.Is(err, storage.ErrNoGroup) {
+		t.Fatalf("expected ErrNoGroup, got %v", err)
+	}
+}
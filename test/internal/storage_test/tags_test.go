@@ -0,0 +1,89 @@
+package storage_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestTags_InvalidateRemovesTaggedKeys(t *testing.T) {
+	loadTmpDB(t)
+
+	_ = storage.PutKeyValueWithOptions("page:42", []byte("a"), storage.PutOptions{Tags: []string{"product:42", "catalog"}})
+	_ = storage.PutKeyValueWithOptions("price:42", []byte("b"), storage.PutOptions{Tags: []string{"product:42"}})
+	_ = storage.PutKeyValueWithOptions("page:7", []byte("c"), storage.PutOptions{Tags: []string{"product:7", "catalog"}})
+	_ = storage.PutKeyValue("other", []byte("d"))
+
+	if n := storage.InvalidateTag("product:42"); n != 2 {
+		t.Fatalf("InvalidateTag(product:42) = %d", n)
+	}
+	if storage.Exists("page:42") || storage.Exists("price:42") {
+		t.Fatalf("tagged keys survived invalidation")
+	}
+	if !storage.Exists("page:7") || !storage.Exists("other") {
+		t.Fatalf("untagged keys were removed")
+	}
+
+	if n := storage.InvalidateTag("catalog"); n != 1 {
+		t.Fatalf("InvalidateTag(catalog) = %d", n)
+	}
+	if n := storage.InvalidateTag("catalog"); n != 0 {
+		t.Fatalf("second InvalidateTag(catalog) = %d", n)
+	}
+}
+
+func TestTags_RewritesAndDeletesUpdateTheIndex(t *testing.T) {
+	loadTmpDB(t)
+
+	_ = storage.PutKeyValueWithOptions("page", []byte("v1"), storage.PutOptions{Tags: []string{"old"}})
+	_ = storage.PutKeyValue("page", []byte("v2"))
+
+	if n := storage.InvalidateTag("old"); n != 0 {
+		t.Fatalf("a plain write must drop the previous tags, invalidated %d", n)
+	}
+
+	_ = storage.PutKeyValueWithOptions("page", []byte("v1"), storage.PutOptions{Tags: []string{"old"}})
+	_ = storage.PutKeyValueWithOptions("page", []byte("v2"), storage.PutOptions{Tags: []string{"new"}})
+	if n := storage.InvalidateTag("old"); n != 0 {
+		t.Fatalf("retagged key still under its old tag")
+	}
+
+	storage.DeleteByKey("page")
+	_ = storage.PutKeyValue("page", []byte("v3"))
+	if n := storage.InvalidateTag("new"); n != 0 {
+		t.Fatalf("deleted key kept its tags")
+	}
+
+	_ = storage.PutKeyValueWithOptions("short", []byte("v"), storage.PutOptions{TTL: 20 * time.Millisecond, Tags: []string{"tmp"}})
+	time.Sleep(40 * time.Millisecond)
+	if n := storage.InvalidateTag("tmp"); n != 0 {
+		t.Fatalf("expired keys must not count as invalidated")
+	}
+}
+
+func TestTags_SurviveRestart(t *testing.T) {
+	loadTmpDB(t)
+
+	_ = storage.PutKeyValueWithOptions("page:1", []byte("a"), storage.PutOptions{Tags: []string{"catalog"}})
+	_ = storage.PutKeyValueWithOptions("page:2", []byte("b"), storage.PutOptions{Tags: []string{"catalog"}})
+
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+	storage.LoadDB()
+
+	if n := storage.InvalidateTag("catalog"); n != 2 {
+		t.Fatalf("InvalidateTag after reload = %d", n)
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	if got := storage.ParseTags(" a,b,,a , c"); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("ParseTags = %q", got)
+	}
+	if got := storage.ParseTags(""); got == nil || len(got) != 0 {
+		t.Fatalf("ParseTags(\"\") = %#v", got)
+	}
+}
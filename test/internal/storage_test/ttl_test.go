@@ -0,0 +1,76 @@
+package storage_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestLoadDB_ConvertsLegacySecondExpirations(t *testing.T) {
+	dir := t.TempDir()
+	globals.SetConfig(&configuration.Config{
+		Store: configuration.StoreConfig{Folder: dir, Shards: 4},
+	})
+
+	at := time.Now().Add(time.Hour).Unix()
+	exp, _ := json.Marshal(map[string][]string{strconv.FormatInt(at, 10): {"legacy"}})
+	writeFile(t, dir, storage.DataFile, []byte(`{"legacy":"dg=="}`))
+	writeFile(t, dir, storage.ExpirationDataFile, exp)
+
+	storage.LoadDB()
+
+	ttl, hasExpiry, exists := storage.GetTTL("legacy")
+	if !exists || !hasExpiry {
+		t.Fatalf("expected legacy key with expiry, exists=%v hasExpiry=%v", exists, hasExpiry)
+	}
+	if ttl < 59*time.Minute || ttl > time.Hour {
+		t.Fatalf("legacy second timestamp not converted, ttl=%v", ttl)
+	}
+
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+	got, err := storage.ReadExpirationsFromDB(storage.ExpirationDataFile)
+	if err != nil {
+		t.Fatalf("ReadExpirationsFromDB: %v", err)
+	}
+	if _, ok := got[at*1000]; !ok {
+		t.Fatalf("expected millisecond bucket %d, got %v", at*1000, got)
+	}
+}
+
+func TestExpireDueKeys_RemovesKeysInBoundedBatches(t *testing.T) {
+	globals.SetConfig(&configuration.Config{
+		Store: configuration.StoreConfig{Folder: t.TempDir(), Shards: 4},
+		Stats: configuration.StatsConfig{Enabled: true},
+	})
+	storage.LoadDB()
+
+	for i := 0; i < 250; i++ {
+		if err := storage.PutKeyValueWithTTLDuration("k"+strconv.Itoa(i), []byte("v"), 20*time.Millisecond); err != nil {
+			t.Fatalf("put: %v", err)
+		}
+	}
+	_ = storage.PutKeyValueWithTTLDuration("later", []byte("v"), time.Hour)
+
+	time.Sleep(50 * time.Millisecond)
+
+	if n := storage.ExpireDueKeys(100); n != 100 {
+		t.Fatalf("expected a full batch of 100, got %d", n)
+	}
+	storage.CleanAllPastKeys()
+
+	for i := 0; i < 250; i++ {
+		if _, _, exists := storage.GetTTL("k" + strconv.Itoa(i)); exists {
+			t.Fatalf("k%d should have been expired", i)
+		}
+	}
+	if _, hasExpiry, exists := storage.GetTTL("later"); !exists || !hasExpiry {
+		t.Fatalf("later must survive the sweep")
+	}
+}
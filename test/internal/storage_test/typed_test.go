@@ -0,0 +1,86 @@
+package storage_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/configuration"
+	"github.com/taymour/elysiandb/internal/globals"
+	"github.com/taymour/elysiandb/internal/stat"
+	"github.com/taymour/elysiandb/internal/storage"
+)
+
+func TestTyped_ReadOnlyUpdatesDoNotDirtyTheStore(t *testing.T) {
+	dir := loadTmpDB(t)
+	path := filepath.Join(dir, storage.TypedDataFile)
+
+	_, _, _ = storage.AcquireLock("job", time.Minute, false, 0, nil)
+	_ = storage.XGroupCreate("events", "workers", "$", true)
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+	_ = os.Remove(path)
+
+	if _, ok, _ := storage.AcquireLock("job", time.Minute, false, 0, nil); ok {
+		t.Fatalf("lock should be held")
+	}
+	if _, _, ok, _ := storage.BLPop([]string{"jobs"}, 5*time.Millisecond, nil); ok {
+		t.Fatalf("BLPop on an empty key popped something")
+	}
+	if reads, err := storage.XReadGroup("workers", "w1", []string{"events"}, []string{">"}, 0, false, 0, nil); err != nil || len(reads) != 0 {
+		t.Fatalf("XReadGroup = %v, %v", reads, err)
+	}
+
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("no-op updates rewrote the snapshot: %v", err)
+	}
+
+	_, _ = storage.SAdd("members", []string{"a"})
+	if err := storage.WriteToDB(); err != nil {
+		t.Fatalf("WriteToDB: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("a real update was not persisted: %v", err)
+	}
+}
+
+func TestTyped_EmptiedValueIsDeletedWithItsExpiry(t *testing.T) {
+	globals.SetConfig(&configuration.Config{
+		Store: configuration.StoreConfig{Folder: t.TempDir(), Shards: 4},
+		Stats: configuration.StatsConfig{Enabled: true},
+	})
+	stat.Init()
+	storage.LoadDB()
+
+	_, _ = storage.HSet("cart", []storage.HashField{{Field: "apple", Value: []byte("1")}})
+	storage.Expire("cart", time.Minute)
+
+	if n, err := storage.HDel("cart", []string{"apple"}); err != nil || n != 1 {
+		t.Fatalf("HDel = %d, %v", n, err)
+	}
+	if _, _, exists := storage.GetTTL("cart"); exists {
+		t.Fatalf("emptied hash still exists")
+	}
+
+	_, _ = storage.HSet("cart", []storage.HashField{{Field: "pear", Value: []byte("2")}})
+	if _, hasExpiry, _ := storage.GetTTL("cart"); hasExpiry {
+		t.Fatalf("recreated hash inherited the old deadline")
+	}
+
+	var counters struct {
+		KeysCount           uint64 `json:"keys_count,string"`
+		ExpirationKeysCount uint64 `json:"expiration_keys_count,string"`
+	}
+	if err := json.Unmarshal([]byte(stat.Stats.ToJson()), &counters); err != nil {
+		t.Fatalf("stats json: %v", err)
+	}
+	if counters.KeysCount != 1 || counters.ExpirationKeysCount != 0 {
+		t.Fatalf("counters after emptying and recreating = %+v", counters)
+	}
+}
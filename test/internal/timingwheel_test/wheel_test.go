@@ -0,0 +1,134 @@
+package timingwheel_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/taymour/elysiandb/internal/timingwheel"
+)
+
+const tick = 10 * time.Millisecond
+
+func sorted(keys []int) []int {
+	sort.Ints(keys)
+	return keys
+}
+
+func TestWheel_FiresInOrderAcrossLevels(t *testing.T) {
+	start := time.Now()
+	w := timingwheel.New[int](tick, start)
+
+	delays := map[int]time.Duration{
+		1: 5 * time.Millisecond,
+		2: 3 * time.Second,
+		3: 20 * time.Minute,
+		4: 30 * time.Hour,
+	}
+	for k, d := range delays {
+		w.Schedule(k, start.Add(d))
+	}
+
+	if got := w.Advance(start.Add(time.Millisecond), 0); len(got) != 0 {
+		t.Fatalf("nothing should be due yet, got %v", got)
+	}
+
+	for k := 1; k <= 4; k++ {
+		d := delays[k]
+		if got := w.Advance(start.Add(d-tick), 0); len(got) != 0 {
+			t.Fatalf("key %d fired early: %v", k, got)
+		}
+		got := w.Advance(start.Add(d+tick), 0)
+		if len(got) != 1 || got[0] != k {
+			t.Fatalf("expected key %d at %v, got %v", k, d, got)
+		}
+	}
+
+	if w.Len() != 0 {
+		t.Fatalf("expected empty wheel, len=%d", w.Len())
+	}
+}
+
+func TestWheel_CatchUpAfterMissedTicks(t *testing.T) {
+	start := time.Now()
+	w := timingwheel.New[int](tick, start)
+
+	for i := 0; i < 100; i++ {
+		w.Schedule(i, start.Add(time.Duration(i)*time.Second))
+	}
+
+	got := w.Advance(start.Add(2*time.Minute), 0)
+	if len(got) != 100 {
+		t.Fatalf("expected all 100 missed expiries, got %d", len(got))
+	}
+}
+
+func TestWheel_RemoveAndReschedule(t *testing.T) {
+	start := time.Now()
+	w := timingwheel.New[string](tick, start)
+
+	w.Schedule("a", start.Add(time.Second))
+	w.Schedule("b", start.Add(time.Second))
+	w.Schedule("c", start.Add(time.Second))
+
+	if !w.Remove("b") || w.Remove("b") {
+		t.Fatalf("Remove should succeed once")
+	}
+	w.Schedule("c", start.Add(time.Hour))
+
+	got := w.Advance(start.Add(2*time.Second), 0)
+	if len(got) != 1 || got[0] != "a" {
+		t.Fatalf("expected only a, got %v", got)
+	}
+	if w.Len() != 1 {
+		t.Fatalf("expected c to remain, len=%d", w.Len())
+	}
+}
+
+func TestWheel_BoundedWorkPerAdvance(t *testing.T) {
+	start := time.Now()
+	w := timingwheel.New[int](tick, start)
+
+	for i := 0; i < 2500; i++ {
+		w.Schedule(i, start.Add(50*time.Millisecond))
+	}
+	w.Schedule(-1, start.Add(-time.Second))
+
+	all := make([]int, 0, 2501)
+	for i := 0; i < 3; i++ {
+		got := w.Advance(start.Add(time.Second), 1000)
+		if len(got) != 1000 && i < 2 {
+			t.Fatalf("batch %d: expected 1000 keys, got %d", i, len(got))
+		}
+		all = append(all, got...)
+	}
+	all = append(all, w.Advance(start.Add(time.Second), 1000)...)
+
+	if len(all) != 2501 || sorted(all)[0] != -1 {
+		t.Fatalf("expected 2501 distinct keys including the past deadline, got %d", len(all))
+	}
+	if w.Len() != 0 {
+		t.Fatalf("expected empty wheel, len=%d", w.Len())
+	}
+}
+
+func TestWheel_RemoveReadyEntry(t *testing.T) {
+	start := time.Now()
+	w := timingwheel.New[int](tick, start)
+
+	w.Schedule(1, start.Add(tick))
+	w.Schedule(2, start.Add(tick))
+	if got := w.Advance(start.Add(time.Second), 1); len(got) != 1 {
+		t.Fatalf("expected one key, got %v", got)
+	}
+
+	w.Remove(1)
+	w.Remove(2)
+
+	if got := w.Advance(start.Add(2*time.Second), 0); len(got) != 0 {
+		t.Fatalf("removed entries must not fire, got %v", got)
+	}
+	if w.Len() != 0 {
+		t.Fatalf("expected empty wheel, len=%d", w.Len())
+	}
+}